@@ -8,12 +8,54 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"strings"
+
 	"github.com/jankampherbeek/segoport"
 )
 
 func main() {
-
+	if len(os.Args) > 1 && os.Args[1] == "inspect" {
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: segoport inspect <file.se1> ...")
+			os.Exit(2)
+		}
+		failed := false
+		for _, fnam := range os.Args[2:] {
+			if err := Inspect(fnam); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "asteroids" {
+		if len(os.Args) > 3 {
+			fmt.Fprintln(os.Stderr, "usage: segoport asteroids [path]")
+			os.Exit(2)
+		}
+		path := ""
+		if len(os.Args) == 3 {
+			path = os.Args[2]
+		}
+		if err := Asteroids(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "jplascii" {
+		if err := JplAscii(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	Version()
 }
 
@@ -23,3 +65,125 @@ func Version() {
 	jd := p.UseSweJulDay(2000, 1, 1, 12.0, 1)
 	fmt.Printf("Julian day : %f\n", jd)
 }
+
+// Inspect prints the header of a Swiss Ephemeris file: DE number, time range, byte order and the constants of the
+// bodies in the file.
+func Inspect(fnam string) error {
+	p := segoport.Port{}
+	h, err := p.ReadFileHeader(fnam)
+	if err != nil {
+		return err
+	}
+	f := h.File
+	fmt.Printf("File       : %s\n", f.Fnam)
+	fmt.Printf("Version    : %d\n", f.Fversion)
+	fmt.Printf("DE number  : %d\n", f.SwephDenum)
+	fmt.Printf("Time range : %s\n", timeRange(f.Tfstart, f.Tfend))
+	fmt.Printf("Byte order : %s\n", byteOrder(f.Iflg))
+	if f.Astnam != "" {
+		fmt.Printf("Name       : %s\n", f.Astnam)
+	}
+	fmt.Printf("Bodies     : %d\n", f.Npl)
+	for _, pd := range h.Planets {
+		fmt.Printf("  %-24s %s\n", bodyName(pd.Ibdy), timeRange(pd.Tfstart, pd.Tfend))
+		fmt.Printf("  %-24s segment %g days, %d coefficients, rmax %g, flags %s\n", "", pd.Dseg, pd.Ncoe, pd.Rmax,
+			planetFlags(pd.Iflg))
+	}
+	return nil
+}
+
+// Asteroids prints the asteroid files in the ephemeris path: MPC number, name, time range and file name. Files that
+// could not be read are reported in the error.
+func Asteroids(path string) error {
+	p := segoport.Port{}
+	p.SetEphePath(path)
+	asteroids, err := p.ListAsteroids()
+	for _, af := range asteroids {
+		fmt.Printf("%6d %-20s %s %s\n", af.Number, af.Name, timeRange(af.Tfstart, af.Tfend), af.Fnam)
+	}
+	return err
+}
+
+// JplAscii converts the ASCII files of a JPL ephemeris into a binary file that can be used with SEFLG_JPLEPH.
+// Arguments: [-start JD] [-end JD] <out.eph> <header.4xx> <ascpNNNN.4xx> ...
+func JplAscii(args []string) error {
+	fs := flag.NewFlagSet("jplascii", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: segoport jplascii [-start JD] [-end JD] <out.eph> <header.4xx> <ascpNNNN.4xx> ...")
+		fs.PrintDefaults()
+	}
+	tjdStart := fs.Float64("start", 0, "first Julian day (TT) to convert, 0 for the start of the data")
+	tjdEnd := fs.Float64("end", 0, "last Julian day (TT) to convert, 0 for the end of the data")
+	fs.Parse(args)
+	if fs.NArg() < 3 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	p := segoport.Port{}
+	tstart, tend, err := p.ConvertJplAscii(fs.Arg(0), fs.Arg(1), fs.Args()[2:], *tjdStart, *tjdEnd)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %s\n", fs.Arg(0), timeRange(tstart, tend))
+	return nil
+}
+
+func timeRange(tjdStart, tjdEnd float64) string {
+	return fmt.Sprintf("JD %.1f - %.1f (%s - %s)", tjdStart, tjdEnd, date(tjdStart), date(tjdEnd))
+}
+
+func date(tjd float64) string {
+	p := segoport.Port{}
+	gregflag := 1
+	if tjd < 2299160.5 {
+		gregflag = 0
+	}
+	year, month, day, _ := p.UseSweRevJul(tjd, gregflag)
+	return fmt.Sprintf("%d/%02d/%02d", year, month, day)
+}
+
+func byteOrder(iflg int32) string {
+	s := "big-endian"
+	if iflg&segoport.SEI_FILE_LITENDIAN != 0 {
+		s = "little-endian"
+	}
+	if iflg&segoport.SEI_FILE_REORD != 0 {
+		s += ", bytes reordered"
+	}
+	return s
+}
+
+func planetFlags(iflg int32) string {
+	var flags []string
+	if iflg&segoport.SEI_FLG_HELIO != 0 {
+		flags = append(flags, "helio")
+	} else {
+		flags = append(flags, "bary")
+	}
+	if iflg&segoport.SEI_FLG_ROTATE != 0 {
+		flags = append(flags, "rotate")
+	}
+	if iflg&segoport.SEI_FLG_ELLIPSE != 0 {
+		flags = append(flags, "ellipse")
+	}
+	if iflg&segoport.SEI_FLG_EMBHEL != 0 {
+		flags = append(flags, "embhel")
+	}
+	return strings.Join(flags, ",")
+}
+
+// bodyNames are the names of the internal body numbers in ephemeris files
+var bodyNames = []string{"Earth-Moon barycenter", "Moon", "Mercury", "Venus", "Mars", "Jupiter", "Saturn", "Uranus",
+	"Neptune", "Pluto", "Sun (barycentric)", "any body", "Chiron", "Pholus", "Ceres", "Pallas", "Juno", "Vesta"}
+
+func bodyName(ibdy int) string {
+	switch {
+	case ibdy >= segoport.SE_AST_OFFSET:
+		return fmt.Sprintf("asteroid %d", ibdy-segoport.SE_AST_OFFSET)
+	case ibdy >= segoport.SE_PLMOON_OFFSET:
+		return fmt.Sprintf("planetary moon %d", ibdy)
+	case ibdy >= 0 && ibdy < len(bodyNames):
+		return bodyNames[ibdy]
+	}
+	return fmt.Sprintf("body %d", ibdy)
+}
@@ -0,0 +1,166 @@
+package segoport
+
+import "github.com/jankampherbeek/segoport/internal"
+
+// Models for sidereal time
+const (
+	SEMOD_SIDT_IAU_1976       = internal.SEMOD_SIDT_IAU_1976
+	SEMOD_SIDT_IAU_2006       = internal.SEMOD_SIDT_IAU_2006
+	SEMOD_SIDT_IERS_CONV_2010 = internal.SEMOD_SIDT_IERS_CONV_2010
+	SEMOD_SIDT_LONGTERM       = internal.SEMOD_SIDT_LONGTERM
+)
+
+// Models for precession
+const (
+	SEMOD_PREC_IAU_1976       = internal.SEMOD_PREC_IAU_1976
+	SEMOD_PREC_LASKAR_1986    = internal.SEMOD_PREC_LASKAR_1986
+	SEMOD_PREC_WILL_EPS_LASK  = internal.SEMOD_PREC_WILL_EPS_LASK
+	SEMOD_PREC_WILLIAMS_1994  = internal.SEMOD_PREC_WILLIAMS_1994
+	SEMOD_PREC_SIMON_1994     = internal.SEMOD_PREC_SIMON_1994
+	SEMOD_PREC_IAU_2000       = internal.SEMOD_PREC_IAU_2000
+	SEMOD_PREC_BRETAGNON_2003 = internal.SEMOD_PREC_BRETAGNON_2003
+	SEMOD_PREC_IAU_2006       = internal.SEMOD_PREC_IAU_2006
+	SEMOD_PREC_VONDRAK_2011   = internal.SEMOD_PREC_VONDRAK_2011
+	SEMOD_PREC_OWEN_1990      = internal.SEMOD_PREC_OWEN_1990
+	SEMOD_PREC_NEWCOMB        = internal.SEMOD_PREC_NEWCOMB
+)
+
+// Models for nutation
+const (
+	SEMOD_NUT_IAU_1980      = internal.SEMOD_NUT_IAU_1980
+	SEMOD_NUT_IAU_CORR_1987 = internal.SEMOD_NUT_IAU_CORR_1987
+	SEMOD_NUT_IAU_2000A     = internal.SEMOD_NUT_IAU_2000A
+	SEMOD_NUT_IAU_2000B     = internal.SEMOD_NUT_IAU_2000B
+	SEMOD_NUT_WOOLARD       = internal.SEMOD_NUT_WOOLARD
+)
+
+// Models for frame bias
+const (
+	SEMOD_BIAS_NONE    = internal.SEMOD_BIAS_NONE
+	SEMOD_BIAS_IAU2000 = internal.SEMOD_BIAS_IAU2000
+	SEMOD_BIAS_IAU2006 = internal.SEMOD_BIAS_IAU2006
+)
+
+// Flags for calculations
+const (
+	SEFLG_JPLEPH        = internal.SEFLG_JPLEPH
+	SEFLG_SWIEPH        = internal.SEFLG_SWIEPH
+	SEFLG_MOSEPH        = internal.SEFLG_MOSEPH
+	SEFLG_SPKEPH        = internal.SEFLG_SPKEPH
+	SEFLG_HELCTR        = internal.SEFLG_HELCTR
+	SEFLG_TRUEPOS       = internal.SEFLG_TRUEPOS
+	SEFLG_J2000         = internal.SEFLG_J2000
+	SEFLG_NONUT         = internal.SEFLG_NONUT
+	SEFLG_SPEED3        = internal.SEFLG_SPEED3
+	SEFLG_SPEED         = internal.SEFLG_SPEED
+	SEFLG_NOGDEFL       = internal.SEFLG_NOGDEFL
+	SEFLG_NOABERR       = internal.SEFLG_NOABERR
+	SEFLG_EQUATORIAL    = internal.SEFLG_EQUATORIAL
+	SEFLG_XYZ           = internal.SEFLG_XYZ
+	SEFLG_RADIANS       = internal.SEFLG_RADIANS
+	SEFLG_BARYCTR       = internal.SEFLG_BARYCTR
+	SEFLG_ICRS          = internal.SEFLG_ICRS
+	SEFLG_JPLHOR        = internal.SEFLG_JPLHOR
+	SEFLG_JPLHOR_APPROX = internal.SEFLG_JPLHOR_APPROX
+	SEFLG_CENTER_BODY   = internal.SEFLG_CENTER_BODY
+)
+
+// Body numbers for Calc
+const (
+	SE_ECL_NUT   = internal.SE_ECL_NUT
+	SE_SUN       = internal.SE_SUN
+	SE_MOON      = internal.SE_MOON
+	SE_MERCURY   = internal.SE_MERCURY
+	SE_VENUS     = internal.SE_VENUS
+	SE_MARS      = internal.SE_MARS
+	SE_JUPITER   = internal.SE_JUPITER
+	SE_SATURN    = internal.SE_SATURN
+	SE_URANUS    = internal.SE_URANUS
+	SE_NEPTUNE   = internal.SE_NEPTUNE
+	SE_PLUTO     = internal.SE_PLUTO
+	SE_MEAN_NODE = internal.SE_MEAN_NODE
+	SE_TRUE_NODE = internal.SE_TRUE_NODE
+	SE_MEAN_APOG = internal.SE_MEAN_APOG
+	SE_OSCU_APOG = internal.SE_OSCU_APOG
+	SE_EARTH     = internal.SE_EARTH
+	SE_CHIRON    = internal.SE_CHIRON
+	SE_PHOLUS    = internal.SE_PHOLUS
+	SE_CERES     = internal.SE_CERES
+	SE_PALLAS    = internal.SE_PALLAS
+	SE_JUNO      = internal.SE_JUNO
+	SE_VESTA     = internal.SE_VESTA
+	SE_INTP_APOG = internal.SE_INTP_APOG
+	SE_INTP_PERG = internal.SE_INTP_PERG
+)
+
+// Standard epochs (Julian day)
+const (
+	J2000 = internal.J2000
+	B1950 = internal.B1950
+	J1900 = internal.J1900
+	B1850 = internal.B1850
+)
+
+// Flags in the header of Swiss Ephemeris files, FileInfo.Iflg
+const (
+	SEI_FILE_LITENDIAN = internal.SEI_FILE_LITENDIAN
+	SEI_FILE_REORD     = internal.SEI_FILE_REORD
+)
+
+// Flags for the planets in Swiss Ephemeris files, PlanetInfo.Iflg
+const (
+	SEI_FLG_HELIO   = internal.SEI_FLG_HELIO
+	SEI_FLG_ROTATE  = internal.SEI_FLG_ROTATE
+	SEI_FLG_ELLIPSE = internal.SEI_FLG_ELLIPSE
+	SEI_FLG_EMBHEL  = internal.SEI_FLG_EMBHEL
+)
+
+// Body numbers in Swiss Ephemeris files, PlanetInfo.Ibdy and EphemerisBody.Ipl
+const (
+	SEI_EMB     = internal.SEI_EMB
+	SEI_MOON    = internal.SEI_MOON
+	SEI_MERCURY = internal.SEI_MERCURY
+	SEI_VENUS   = internal.SEI_VENUS
+	SEI_MARS    = internal.SEI_MARS
+	SEI_JUPITER = internal.SEI_JUPITER
+	SEI_SATURN  = internal.SEI_SATURN
+	SEI_URANUS  = internal.SEI_URANUS
+	SEI_NEPTUNE = internal.SEI_NEPTUNE
+	SEI_PLUTO   = internal.SEI_PLUTO
+	SEI_SUNBARY = internal.SEI_SUNBARY
+	SEI_ANYBODY = internal.SEI_ANYBODY
+	SEI_CHIRON  = internal.SEI_CHIRON
+	SEI_PHOLUS  = internal.SEI_PHOLUS
+	SEI_CERES   = internal.SEI_CERES
+	SEI_PALLAS  = internal.SEI_PALLAS
+	SEI_JUNO    = internal.SEI_JUNO
+	SEI_VESTA   = internal.SEI_VESTA
+)
+
+// Offsets of the numbers of planetary moons, asteroids, fictitious bodies and the objects of LoadMpcFile
+const (
+	SE_PLMOON_OFFSET = internal.SE_PLMOON_OFFSET // + the number of the file, e.g. 9501 for Io
+	SE_AST_OFFSET    = internal.SE_AST_OFFSET    // + the MPC number
+	SE_FICT_OFFSET   = internal.SE_FICT_OFFSET   // + n for the bodies of seorbel.txt, up to SE_FICT_REG - 1
+	SE_FICT_MAX      = internal.SE_FICT_MAX      // last number of the fictitious bodies
+	SE_FICT_REG      = internal.SE_FICT_REG      // + n for the bodies of RegisterElements, up to SE_FICT_MAX
+	SE_COMET_OFFSET  = internal.SE_COMET_OFFSET  // + n for the objects of FindMpcObject
+)
+
+// Body numbers of the Uranian planets for Calc, SE_FICT_OFFSET + 0 .. 7
+const (
+	SE_CUPIDO   = internal.SE_CUPIDO
+	SE_HADES    = internal.SE_HADES
+	SE_ZEUS     = internal.SE_ZEUS
+	SE_KRONOS   = internal.SE_KRONOS
+	SE_APOLLON  = internal.SE_APOLLON
+	SE_ADMETOS  = internal.SE_ADMETOS
+	SE_VULKANUS = internal.SE_VULKANUS
+	SE_POSEIDON = internal.SE_POSEIDON
+)
+
+// Built-in elements of the Uranian planets Cupido .. Poseidon, see SetUranianElements
+const (
+	SE_FICTEL_NEELY   = internal.SE_FICTEL_NEELY
+	SE_FICTEL_CLASSIC = internal.SE_FICTEL_CLASSIC
+)
@@ -7,12 +7,12 @@ var swed SweData
 // swe_data sweph.h-0790
 // if this is changed, then also update initialisation in sweph.c
 type SweData struct {
-	EphePathIsSet bool
-	JplFileIsOpen bool
-	FixFp         *os.File // Fixed stars file pointer
-	EphePath      string
-	// Port: ignored JplFnam            [AS_MAXCH]byte
-	// Port: ignored JplDenum           int32
+	EphePathIsSet      bool
+	JplFileIsOpen      bool
+	FixFp              *os.File // Fixed stars file pointer
+	EphePath           string
+	JplFnam            string // name of the JPL file, see SweSetJplFile
+	JplDenum           int32  // DE number of the open JPL file
 	LastEpheFlag       int32
 	GeoposIsSet        bool
 	AyanaIsSet         bool
@@ -52,10 +52,18 @@ type SweData struct {
 	Nutv               Nut
 	Topd               TopoData
 	Sidd               SidData
-	NFixstarsReal      bool // real number of fixed stars in sefstars.txt
-	NFixstarsNamed     bool // number of fixed stars with tradtional name
-	NFixstarsRecords   bool // number of fixed stars records in fixed_stars
+	NFixstarsReal      int // real number of fixed stars in sefstars.txt
+	NFixstarsNamed     int // number of fixed stars with tradtional name
+	NFixstarsRecords   int // number of fixed stars records in fixed_stars
 	FixedStars         []FixedStar
+	AstIndex           *AsteroidIndex // Port: added, index of the asteroid files in the ephemeris path
+	EpheChain          []int32        // Port: added, fallback order of the ephemerides, see SweSetEpheChain
+	FictElements       []fictElements // Port: added, bodies registered with SweRegisterElements
+	MpcObjects         []MpcObject    // Port: added, comets and asteroids of SweLoadMpcFile
+	MpcIndex           map[string]int // Port: added, index in MpcObjects by lower case name, see SweFindMpcObject
+	MpcBodies          []int          // Port: added, index in MpcObjects of the body SE_COMET_OFFSET + n
+	FileErr            error          // Port: added, typed error of the last damaged ephemeris file, see SweCalc
+	FictFnam           string         // Port: added, path of seorbel.txt of the last readElementsFile, see calcProvenance
 }
 
 var sweData SweData
@@ -70,6 +78,8 @@ type Interpol struct {
 	NutDeps0 float64
 	NutDeps1 float64
 	NutDeps2 float64
+	NutModel int32 // Port: added, nutation model and flags of the cached values
+	NutIflag int32
 }
 
 // file_data sweph.h-0708
@@ -140,6 +150,31 @@ type PlanData struct {
 	// xreturn[18:24] equatorial cartesian coordinates
 }
 
+// FileHeader contains the constants that are read from the header of a Swiss Ephemeris file.
+// Port: not in the C version, where read_const() stores the constants directly in swed. Keeping them in a separate
+// structure allows to inspect a file without changing the state of the files that are in use.
+type FileHeader struct {
+	File    FileData   // file data, Fptr is not set
+	Gcdat   GenConst   // general constants
+	Planets []PlanData // constants for the planets in File.Ipl[0:File.Npl], in the same order
+	Astelem string     // orbital elements record, only for files with a single asteroid or planetary moon
+	AstH    float64    // magnitude parameters H and G and diameter in km, from the elements record
+	AstG    float64
+	AstDiam float64
+}
+
+// plantbl sweph.h-0698
+// PlanTbl contains the series of the Moshier planetary theory for one planet, see swemptab.go.
+type PlanTbl struct {
+	MaxHarmonic [9]int8
+	MaxPowerOfT int8
+	ArgTbl      []int8
+	LonTbl      []float64
+	LatTbl      []float64
+	RadTbl      []float64
+	Distance    float64
+}
+
 // save_positions sweph.h-730
 type SavePositions struct {
 	Ipl      int
@@ -152,6 +187,8 @@ type SavePositions struct {
 	//    and equatorial cartesian coordinates (offset 18).
 	// 6 doubles each for position and speed coordinates.
 	Xsaves [24]float64
+	Prov   Provenance // Port: added, source of the saved position
+	FictEl int32      // Port: added, built-in elements of the Uranian planets of the saved position
 }
 
 // epsilon sweph.h-0600
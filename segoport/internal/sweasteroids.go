@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Port: the functions in this file are not part of the C version. They make an index of the asteroid files in the
+// ephemeris path, e.g. to let the user choose an asteroid by name.
+
+// AsteroidFile describes an asteroid file in the ephemeris path.
+type AsteroidFile struct {
+	Number  int     // MPC number, the body number is SE_AST_OFFSET + Number
+	Name    string  // name of the asteroid as given in the file
+	Fnam    string  // path and name of the file
+	Tfstart float64 // start of the ephemeris, Julian day (TT)
+	Tfend   float64 // end of the ephemeris, Julian day (TT)
+}
+
+// AsteroidIndex contains the asteroid files in the ephemeris path, sorted by MPC number.
+// Err contains the errors for files that could not be read, these files are not in the index.
+type AsteroidIndex struct {
+	Asteroids []AsteroidFile
+	Err       error
+}
+
+// asteroidFileName matches the names of asteroid files as built by swiGenFilename, e.g. se00433.se1, s130000.se1 or
+// s1000000.se1, and of the files with a shorter time range, e.g. se00433s.se1.
+var asteroidFileName = regexp.MustCompile(`^s(?:e(\d{5})|(\d{6,}))(s?)\.` + SE_FILE_SUFFIX + `$`)
+
+// SweListAsteroids returns the asteroid files in the ephemeris path (see SweSetEphePath): files ast*/se*.se1 and
+// s*.se1 in each directory of the path. If there are several files for an asteroid, the first one in the path is used,
+// and in the same directory the one with the longer time range. The index is built when it is first needed and
+// rebuilt after the ephemeris path is changed.
+// The error is not nil if some files could not be read, the other files are still listed.
+func SweListAsteroids() ([]AsteroidFile, error) {
+	swiInitSwedIfStart()
+	if swed.AstIndex == nil {
+		swed.AstIndex = buildAsteroidIndex(swed.EphePath)
+	}
+	return swed.AstIndex.Asteroids, swed.AstIndex.Err
+}
+
+// SweFindAsteroid returns the asteroid file for the asteroid with the given name, which is compared without regard
+// to case. The second return value is false if there is no file for the asteroid in the ephemeris path.
+func SweFindAsteroid(name string) (AsteroidFile, bool) {
+	asteroids, _ := SweListAsteroids()
+	name = strings.TrimSpace(name)
+	for _, af := range asteroids {
+		if strings.EqualFold(af.Name, name) {
+			return af, true
+		}
+	}
+	return AsteroidFile{}, false
+}
+
+// buildAsteroidIndex scans the directories of ephepath for asteroid files and reads their headers.
+func buildAsteroidIndex(ephepath string) *AsteroidIndex {
+	cpos := make([]string, 20)
+	np := swiCutstr(ephepath, PATH_SEPARATOR, cpos, 20)
+	seen := make(map[int]bool)
+	index := &AsteroidIndex{}
+	var errs []error
+	for _, dir := range cpos[:np] {
+		if dir == "" {
+			continue
+		}
+		var fnams []string
+		for _, pattern := range []string{"s*." + SE_FILE_SUFFIX, "ast*/s*." + SE_FILE_SUFFIX} {
+			// the patterns are valid, Glob does not fail
+			m, _ := filepath.Glob(filepath.Join(dir, pattern))
+			fnams = append(fnams, m...)
+		}
+		found := make(map[int]string)
+		for _, fnam := range fnams {
+			nr, short, ok := asteroidFileNumber(fnam)
+			if !ok || seen[nr] {
+				continue
+			}
+			if other, ok := found[nr]; ok {
+				if _, otherShort, _ := asteroidFileNumber(other); !otherShort || short {
+					continue
+				}
+			}
+			found[nr] = fnam
+		}
+		nrs := make([]int, 0, len(found))
+		for nr := range found {
+			nrs = append(nrs, nr)
+		}
+		sort.Ints(nrs)
+		for _, nr := range nrs {
+			fnam := found[nr]
+			h, err := ReadFileHeader(fnam)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			seen[nr] = true
+			index.Asteroids = append(index.Asteroids, AsteroidFile{Number: nr, Name: h.File.Astnam, Fnam: fnam,
+				Tfstart: h.File.Tfstart, Tfend: h.File.Tfend})
+		}
+	}
+	sort.Slice(index.Asteroids, func(i, j int) bool {
+		return index.Asteroids[i].Number < index.Asteroids[j].Number
+	})
+	index.Err = errors.Join(errs...)
+	return index
+}
+
+// asteroidFileNumber returns the MPC number for the name of an asteroid file and whether it is a file with a shorter
+// time range. The last return value is false if fnam is not the name of an asteroid file.
+func asteroidFileNumber(fnam string) (int, bool, bool) {
+	m := asteroidFileName.FindStringSubmatch(strings.ToLower(filepath.Base(fnam)))
+	if m == nil {
+		return 0, false, false
+	}
+	nr, err := strconv.Atoi(m[1] + m[2])
+	if err != nil {
+		return 0, false, false
+	}
+	return nr, m[3] != "", true
+}
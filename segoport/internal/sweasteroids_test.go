@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeAsteroidFile writes a small asteroid file for the test of the asteroid index.
+func writeAsteroidFile(t *testing.T, fnam string, nr int, name string) {
+	bd := EphemerisBody{Ipl: SE_AST_OFFSET + nr, Iflg: SEI_FLG_HELIO, Ncoe: 6, Tfstart: 2451536.5, Dseg: 32}
+	bd.Segments = FitChebyshev(inclinedOrbit(2.77, 1680, 0.1), bd.Tfstart, bd.Dseg, 2, bd.Ncoe)
+	ef := EphemerisFile{Fversion: 2, Copyright: "test", SwephDenum: 431, Astnam: name, Bodies: []EphemerisBody{bd}}
+	if err := os.MkdirAll(filepath.Dir(fnam), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteEphemerisFile(fnam, &ef); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListAsteroids(t *testing.T) {
+	dir := t.TempDir()
+	writeAsteroidFile(t, filepath.Join(dir, "ast0", "se00001.se1"), 1, "Ceres")
+	writeAsteroidFile(t, filepath.Join(dir, "ast0", "se00001s.se1"), 1, "Ceres short")
+	writeAsteroidFile(t, filepath.Join(dir, "ast0", "se00433s.se1"), 433, "Eros short")
+	writeAsteroidFile(t, filepath.Join(dir, "s130000.se1"), 130000, "Test")
+	writeAsteroidFile(t, filepath.Join(dir, "s1000001.se1"), 1000001, "Seven digits")
+	if err := os.WriteFile(filepath.Join(dir, "ast0", "se00002.se1"), []byte("damaged"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SweSetEphePath("testdata/sweph" + ":" + dir)
+	defer SweSetEphePath("")
+	asteroids, err := SweListAsteroids()
+	if err == nil {
+		t.Errorf("SweListAsteroids: no error for damaged file")
+	}
+	want := []struct {
+		nr   int
+		name string
+		fnam string
+	}{
+		{1, "Ceres", filepath.Join(dir, "ast0", "se00001.se1")},
+		{433, "Eros", "testdata/sweph/ast0/se00433.se1"},
+		{130000, "Test", filepath.Join(dir, "s130000.se1")},
+		{1000001, "Seven digits", filepath.Join(dir, "s1000001.se1")},
+	}
+	if len(asteroids) != len(want) {
+		t.Fatalf("SweListAsteroids = %+v; want %d asteroids", asteroids, len(want))
+	}
+	for i, w := range want {
+		af := asteroids[i]
+		if af.Number != w.nr || af.Name != w.name || filepath.Clean(af.Fnam) != filepath.Clean(w.fnam) {
+			t.Errorf("SweListAsteroids[%d] = %+v; want %d %s in %s", i, af, w.nr, w.name, w.fnam)
+		}
+	}
+	if af, ok := SweFindAsteroid("eros"); !ok || af.Number != 433 || af.Tfstart >= af.Tfend {
+		t.Errorf("SweFindAsteroid(eros) = %+v, %v; want asteroid 433", af, ok)
+	}
+	if _, ok := SweFindAsteroid("Pallas"); ok {
+		t.Errorf("SweFindAsteroid(Pallas): found")
+	}
+}
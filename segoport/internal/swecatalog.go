@@ -0,0 +1,171 @@
+package internal
+
+import "math"
+
+// Port: the functions in this file are not part of the C version. They convert star catalogue positions between the
+// FK4 (B1950) and FK5 (J2000) systems and between equinoxes of arbitrary epochs.
+
+// CatalogPosition is a star position as given in a catalogue.
+// Ra and Dec are right ascension and declination in degrees.
+// PmRa and PmDec are the proper motions in arcsec per year; PmRa is dRA/dt, i.e. not multiplied by cos(Dec). The year
+// is a tropical year for FK4 and a Julian year for FK5.
+// Parallax is in arcsec and RadVel is the radial velocity in km/s, positive if the star recedes.
+type CatalogPosition struct {
+	Ra, Dec     float64
+	PmRa, PmDec float64
+	Parallax    float64
+	RadVel      float64
+}
+
+// Constants for the conversion FK4 <-> FK5 according to Standish (1982) and Aoki et al. (1983), see Expl.Suppl.
+// (1992), p. 184ff., in the form used in SLALIB (P.T. Wallace, sla_FK425 and sla_FK524).
+const (
+	// km/s to AU per tropical century
+	fk4Vf = 21.095
+	// smallest distance and parallax that are handled
+	fk4Tiny = 1e-30
+)
+
+// fk4Eterms are the E-terms of aberration (radians) and their change (arcsec per tropical century).
+var (
+	fk4Eterms    = [3]float64{-1.62557e-6, -0.31919e-6, -0.13843e-6}
+	fk4EtermsDot = [3]float64{1.245e-3, -1.580e-3, -0.659e-3}
+)
+
+// fk4ToFk5Matrix converts position (radians) and velocity (arcsec per century) from FK4 B1950 to FK5 J2000.
+var fk4ToFk5Matrix = [6][6]float64{
+	{0.9999256782, -0.0111820611, -0.0048579477, 0.00000242395018, -0.00000002710663, -0.00000001177656},
+	{0.0111820610, 0.9999374784, -0.0000271765, 0.00000002710663, 0.00000242397878, -0.00000000006587},
+	{0.0048579479, -0.0000271474, 0.9999881997, 0.00000001177656, -0.00000000006582, 0.00000242410173},
+	{-0.000551, -0.238565, 0.435739, 0.99994704, -0.01118251, -0.00485767},
+	{0.238514, -0.002667, -0.008541, 0.01118251, 0.99995883, -0.00002718},
+	{-0.435623, 0.012254, 0.002117, 0.00485767, -0.00002714, 1.00000956},
+}
+
+// fk5ToFk4Matrix converts position (radians) and velocity (arcsec per century) from FK5 J2000 to FK4 B1950.
+var fk5ToFk4Matrix = [6][6]float64{
+	{0.9999256795, 0.0111814828, 0.0048590039, -0.00000242389840, -0.00000002710544, -0.00000001177742},
+	{-0.0111814828, 0.9999374849, -0.0000271771, 0.00000002710544, -0.00000242392702, 0.00000000006585},
+	{-0.0048590040, -0.0000271557, 0.9999881946, 0.00000001177742, 0.00000000006585, -0.00000242404995},
+	{-0.000551, 0.238509, -0.435614, 0.99990432, 0.01118145, 0.00485852},
+	{-0.238560, -0.002667, 0.012254, -0.01118145, 0.99991613, -0.00002717},
+	{0.435730, -0.008541, 0.002117, -0.00485852, -0.00002716, 0.99996684},
+}
+
+// catalogToVector converts a catalogue position to a cartesian position (unit vector) and velocity in arcsec per
+// century.
+func catalogToVector(pos CatalogPosition) [6]float64 {
+	ra := pos.Ra * DEGTORAD
+	dec := pos.Dec * DEGTORAD
+	ur := pos.PmRa * 100
+	ud := pos.PmDec * 100
+	sr, cr := math.Sin(ra), math.Cos(ra)
+	sd, cd := math.Sin(dec), math.Cos(dec)
+	x := cr * cd
+	y := sr * cd
+	z := sd
+	w := fk4Vf * pos.RadVel * pos.Parallax
+	return [6]float64{x, y, z, -ur*y - cr*sd*ud + w*x, ur*x - sr*sd*ud + w*y, cd*ud + w*z}
+}
+
+// vectorToCatalog converts a cartesian position and velocity in arcsec per century back to a catalogue position.
+// parallax is the parallax of the input position, the length of the position vector gives its change.
+func vectorToCatalog(v [6]float64, parallax float64) CatalogPosition {
+	var pos CatalogPosition
+	x, y, z := v[0], v[1], v[2]
+	xd, yd, zd := v[3], v[4], v[5]
+	rxysq := x*x + y*y
+	rxy := math.Sqrt(rxysq)
+	rxyz := math.Sqrt(rxysq + z*z)
+	if x != 0 || y != 0 {
+		pos.Ra = SweDegnorm(math.Atan2(y, x) * RADTODEG)
+	}
+	pos.Dec = math.Atan2(z, rxy) * RADTODEG
+	if rxy > fk4Tiny {
+		pos.PmRa = (x*yd - y*xd) / rxysq / 100
+		pos.PmDec = (zd*rxysq - z*(x*xd+y*yd)) / ((rxysq + z*z) * rxy) / 100
+	}
+	if parallax > fk4Tiny {
+		pos.RadVel = (x*xd + y*yd + z*zd) / (parallax * fk4Vf * rxyz)
+		pos.Parallax = parallax / rxyz
+	}
+	return pos
+}
+
+// SweFk4ToFk5 converts a catalogue position for the mean equator and equinox B1950 in the FK4 system to the mean
+// equator and equinox J2000 in the FK5 system, including the removal of the E-terms of aberration. The epoch of the
+// position is changed from B1950 to J2000 as well, using the proper motions.
+// If the proper motions in FK4 are not known, use 0. Note that this is not the same as zero proper motion in FK5,
+// because the FK4 system rotates with respect to FK5.
+func SweFk4ToFk5(pos CatalogPosition) CatalogPosition {
+	r0 := catalogToVector(pos)
+	// remove the E-terms
+	var w, wd float64
+	for i := 0; i < 3; i++ {
+		w += r0[i] * fk4Eterms[i]
+		wd += r0[i] * fk4EtermsDot[i]
+	}
+	var v1 [6]float64
+	for i := 0; i < 3; i++ {
+		v1[i] = r0[i] - fk4Eterms[i] + w*r0[i]
+		v1[i+3] = r0[i+3] - fk4EtermsDot[i] + wd*r0[i]
+	}
+	var v2 [6]float64
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 6; j++ {
+			v2[i] += fk4ToFk5Matrix[i][j] * v1[j]
+		}
+	}
+	return vectorToCatalog(v2, pos.Parallax)
+}
+
+// SweFk5ToFk4 converts a catalogue position for the mean equator and equinox J2000 in the FK5 system to the mean
+// equator and equinox B1950 in the FK4 system, including the E-terms of aberration. This is the inverse of
+// SweFk4ToFk5.
+func SweFk5ToFk4(pos CatalogPosition) CatalogPosition {
+	v1 := catalogToVector(pos)
+	var v2 [6]float64
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 6; j++ {
+			v2[i] += fk5ToFk4Matrix[i][j] * v1[j]
+		}
+	}
+	// the E-terms depend on the length of the position vector, which is found by a first approximation
+	x, y, z := v2[0], v2[1], v2[2]
+	rxyz := math.Sqrt(x*x + y*y + z*z)
+	w := x*fk4Eterms[0] + y*fk4Eterms[1] + z*fk4Eterms[2]
+	x += fk4Eterms[0]*rxyz - w*x
+	y += fk4Eterms[1]*rxyz - w*y
+	z += fk4Eterms[2]*rxyz - w*z
+	rxyz = math.Sqrt(x*x + y*y + z*z)
+	// apply the E-terms to position and velocity
+	x, y, z = v2[0], v2[1], v2[2]
+	w = x*fk4Eterms[0] + y*fk4Eterms[1] + z*fk4Eterms[2]
+	wd := x*fk4EtermsDot[0] + y*fk4EtermsDot[1] + z*fk4EtermsDot[2]
+	x += fk4Eterms[0]*rxyz - w*x
+	y += fk4Eterms[1]*rxyz - w*y
+	z += fk4Eterms[2]*rxyz - w*z
+	xd := v2[3] + fk4EtermsDot[0]*rxyz - wd*x
+	yd := v2[4] + fk4EtermsDot[1]*rxyz - wd*y
+	zd := v2[5] + fk4EtermsDot[2]*rxyz - wd*z
+	return vectorToCatalog([6]float64{x, y, z, xd, yd, zd}, pos.Parallax)
+}
+
+// PrecessionMatrixEpochs returns the matrix that precesses cartesian equatorial coordinates from the mean equator and
+// equinox of Julian day tjdFrom to the mean equator and equinox of Julian day tjdTo (both TT), using precession model
+// precModel (SEMOD_PREC_*, 0 for the default). For catalogues based on Newcomb's precession (FK4 and older), use
+// SEMOD_PREC_NEWCOMB.
+func PrecessionMatrixEpochs(tjdFrom, tjdTo float64, precModel int32) Matrix3 {
+	from := PrecessionMatrix(tjdFrom, precModel)
+	to := PrecessionMatrix(tjdTo, precModel)
+	return to.Mul(from.Transpose())
+}
+
+// SwePrecessEpochs precesses polar equatorial coordinates from the mean equator and equinox of tjdFrom to the mean
+// equator and equinox of tjdTo (both TT), using precession model precModel (SEMOD_PREC_*, 0 for the default).
+// xpo = right ascension, declination (degrees), distance and their speeds. The unit of time of the speeds does not
+// matter, because both equinoxes are fixed. The epoch of the position is not changed, i.e. no proper motion is
+// applied.
+func SwePrecessEpochs(xpo [6]float64, tjdFrom, tjdTo float64, precModel int32) [6]float64 {
+	return PrecessionMatrixEpochs(tjdFrom, tjdTo, precModel).ApplyPolarSp(xpo)
+}
@@ -0,0 +1,41 @@
+package internal
+
+// ===== 2788 ===== swe_azalt swecl.c-2788 ===========================================================================
+
+// SweEquToHor computes azimuth and true altitude from equatorial coordinates.
+// xin = right ascension and declination of date, in degrees
+// armc = sidereal time in degrees (local sidereal time in hours * 15)
+// geolat = geographic latitude in degrees
+// Returns the azimuth (measured from the south point, clockwise via west) and the true altitude, in degrees.
+// Port: based on swe_azalt(), with the sidereal time as parameter and without refraction. Ecliptic coordinates can be
+// converted to equatorial coordinates with SweCotrans and a negative obliquity.
+func SweEquToHor(xin [2]float64, armc, geolat float64) [2]float64 {
+	var xaz [2]float64
+	mdd := SweDegnorm(xin[0] - armc)
+	x := [3]float64{SweDegnorm(mdd - 90), xin[1], 1}
+	// azimuth from east, counterclock
+	x = SweCotrans(x, 90-geolat)
+	// azimuth from south to west
+	x[0] = SweDegnorm(x[0] + 90)
+	xaz[0] = SweDegnorm(360 - x[0])
+	xaz[1] = x[1] // true height
+	return xaz
+}
+
+// ===== 2850 ===== swe_azalt_rev swecl.c-2850 =======================================================================
+
+// SweHorToEqu computes equatorial coordinates from azimuth and true altitude in degrees.
+// xin = azimuth (from the south point, clockwise via west) and true altitude, in degrees
+// armc = sidereal time in degrees (local sidereal time in hours * 15)
+// geolat = geographic latitude in degrees
+// Returns right ascension and declination of date, in degrees.
+// Port: based on swe_azalt_rev(), with the sidereal time as parameter.
+func SweHorToEqu(xin [2]float64, armc, geolat float64) [2]float64 {
+	// azimuth is from south, clockwise. we need it from east, counterclock
+	xaz := [3]float64{SweDegnorm(360 - xin[0] - 90), xin[1], 1}
+	// equatorial positions
+	dang := geolat - 90
+	xaz = SweCotrans(xaz, dang)
+	xaz[0] = SweDegnorm(xaz[0] + armc + 90)
+	return [2]float64{xaz[0], xaz[1]}
+}
@@ -0,0 +1,189 @@
+package internal
+
+import "fmt"
+
+// Port: the functions in this file are not part of the C version. C always falls back from the JPL ephemeris to the
+// Swiss Ephemeris and from the Swiss Ephemeris to the Moshier ephemeris, if the files are not available. Here the
+// order can be configured, and each position carries the ephemeris, file and DE number that produced it. The SPK
+// kernels (SEFLG_SPKEPH) precede the JPL ephemeris in the default order.
+
+// Provenance describes the source of a calculated position: the ephemeris that was actually used, the file that
+// contains the body and the JPL DE number on which the ephemeris is based.
+type Provenance struct {
+	Iephe    int32  // ephemeris: SEFLG_SPKEPH, SEFLG_JPLEPH, SEFLG_SWIEPH or SEFLG_MOSEPH
+	Fnam     string // path of the file, with planetary moons the file of the moon, with fictitious bodies seorbel.txt
+	Denum    int32  // JPL DE number, 0 for SPK kernels, SE_ECL_NUT, the mean lunar node and apogee, the interp. apsides
+	FictEl   int32  // built-in elements of Cupido .. Poseidon: SE_FICTEL_NEELY or SE_FICTEL_CLASSIC; 0 for other bodies
+	Fallback bool   // another ephemeris than the requested one was used
+}
+
+// defaultEpheChain is the fallback order of the C version, preceded by the SPK kernels.
+var defaultEpheChain = []int32{SEFLG_SPKEPH, SEFLG_JPLEPH, SEFLG_SWIEPH, SEFLG_MOSEPH}
+
+// SweSetEpheChain sets the order in which the ephemerides are tried. A calculation starts with the ephemeris in iflag
+// (SEFLG_SWIEPH if none is given) and continues with the ephemerides that follow it in the chain, if the files are
+// not available. If the requested ephemeris is not in the chain, there is no fallback. An empty chain restores the
+// default: SEFLG_SPKEPH, SEFLG_JPLEPH, SEFLG_SWIEPH, SEFLG_MOSEPH. A chain with only SEFLG_SWIEPH is strict: an error
+// is returned instead of a position from another ephemeris.
+// Returns an error, and keeps the current chain, if the chain contains other flags or an ephemeris more than once.
+func SweSetEpheChain(chain []int32) error {
+	var seen int32
+	for _, epheflag := range chain {
+		if epheflag != SEFLG_SPKEPH && epheflag != SEFLG_JPLEPH && epheflag != SEFLG_SWIEPH &&
+			epheflag != SEFLG_MOSEPH {
+			return fmt.Errorf("invalid ephemeris flag %d in ephemeris chain", epheflag)
+		}
+		if seen&epheflag != 0 {
+			return fmt.Errorf("ephemeris flag %d occurs more than once in ephemeris chain", epheflag)
+		}
+		seen |= epheflag
+	}
+	if len(chain) == 0 {
+		swed.EpheChain = nil
+	} else {
+		swed.EpheChain = append([]int32{}, chain...)
+	}
+	// positions of the save area may have been computed with another chain
+	swiForceAppPosEtc()
+	return nil
+}
+
+// SweGetEpheChain returns the order in which the ephemerides are tried, see SweSetEpheChain.
+func SweGetEpheChain() []int32 {
+	if swed.EpheChain == nil {
+		return append([]int32{}, defaultEpheChain...)
+	}
+	return append([]int32{}, swed.EpheChain...)
+}
+
+// nextEphe returns the ephemeris that follows epheflag in the chain, or 0 if there is none.
+func nextEphe(epheflag int32) int32 {
+	chain := swed.EpheChain
+	if chain == nil {
+		chain = defaultEpheChain
+	}
+	for i, e := range chain {
+		if e == epheflag && i+1 < len(chain) {
+			return chain[i+1]
+		}
+	}
+	return 0
+}
+
+// epheFallback returns iflag with the ephemeris that follows the ephemeris of iflag in the chain, and appends a note
+// to serr as C does. Returns 0 if there is no further ephemeris.
+func epheFallback(iflag int32, serr *string) int32 {
+	next := nextEphe(iflag & SEFLG_EPHMASK)
+	if next == 0 {
+		return 0
+	}
+	if serr != nil && len(*serr)+30 < AS_MAXCH {
+		*serr += " \n" + fallbackNote(next) + " "
+	}
+	return iflag&^SEFLG_EPHMASK | next
+}
+
+// fallbackNote returns the note of C about the fallback to ephemeris epheflag.
+func fallbackNote(epheflag int32) string {
+	switch epheflag {
+	case SEFLG_SPKEPH:
+		return "trying SPK kernels;"
+	case SEFLG_JPLEPH:
+		return "trying JPL Eph;"
+	case SEFLG_SWIEPH:
+		return "trying Swiss Eph;"
+	default:
+		return "using Moshier eph.;"
+	}
+}
+
+// calcProvenance returns the source of the position of body ipl (and planetary moon iplmoon) at tjd that swecalc()
+// computed with the flags iflag, while iflgsave contains the flags of the caller. fictEl are the built-in elements of
+// the Uranian planets.
+func calcProvenance(tjd float64, ipl, iplmoon int, iflag, iflgsave, fictEl int32) Provenance {
+	// requested ephemeris, as in plausIflag()
+	requested := int32(SEFLG_DEFAULTEPH)
+	switch {
+	case iflgsave&SEFLG_SPKEPH != 0:
+		requested = SEFLG_SPKEPH
+	case iflgsave&SEFLG_JPLEPH != 0:
+		requested = SEFLG_JPLEPH
+	case iflgsave&SEFLG_SWIEPH != 0:
+		requested = SEFLG_SWIEPH
+	case iflgsave&SEFLG_MOSEPH != 0:
+		requested = SEFLG_MOSEPH
+	}
+	prov := Provenance{Iephe: iflag & SEFLG_EPHMASK}
+	prov.Fallback = prov.Iephe != requested
+	// the mean lunar node and apogee and the interpolated apsides are computed from the lunar theory, without an
+	// ephemeris
+	if ipl == SE_ECL_NUT || ipl == SE_MEAN_NODE || ipl == SE_MEAN_APOG || ipl == SE_INTP_APOG || ipl == SE_INTP_PERG {
+		return prov
+	}
+	// fictitious bodies are computed from elements, built in or from seorbel.txt, and the earth and the sun
+	if ipl >= SE_FICT_OFFSET && ipl <= SE_FICT_MAX {
+		if prov.Iephe != SEFLG_SPKEPH {
+			prov.Denum = swiGetDenum(SEI_EARTH, prov.Iephe)
+		}
+		if ipl >= SE_FICT_REG {
+			// registered with SweRegisterElements
+			return prov
+		}
+		// the file that readElementsFile has read
+		if swed.FictFnam != "" {
+			prov.Fnam = swed.FictFnam
+		} else if ipl <= SE_POSEIDON {
+			prov.FictEl = fictEl
+		}
+		return prov
+	}
+	// comets and asteroids of SweLoadMpcFile are computed from their elements and the earth and the sun
+	if ipl >= SE_COMET_OFFSET && ipl < SE_PLMOON_OFFSET {
+		if prov.Iephe != SEFLG_SPKEPH {
+			prov.Denum = swiGetDenum(SEI_EARTH, prov.Iephe)
+		}
+		if obj := mpcBody(ipl); obj != nil {
+			prov.Fnam = obj.Fnam
+		}
+		return prov
+	}
+	// internal body number and file
+	ipli, ifno := ipl, SEI_FILE_PLANET
+	switch {
+	case iplmoon > 0:
+		ipli, ifno = iplmoon, SEI_FILE_ANY_AST
+	case ipl == SE_MOON || ipl == SE_TRUE_NODE || ipl == SE_OSCU_APOG:
+		// the true node and the osculating apogee are computed from the moon
+		ipli, ifno = SEI_MOON, SEI_FILE_MOON
+	case ipl >= SE_CHIRON && ipl <= SE_VESTA:
+		ipli, ifno = PNOEXT2INT[ipl], SEI_FILE_MAIN_AST
+	case ipl > SE_AST_OFFSET && ipl <= SE_AST_OFFSET+MPC_VESTA:
+		ipli, ifno = SEI_CERES+ipl-SE_AST_OFFSET-1, SEI_FILE_MAIN_AST
+	case ipl > SE_PLMOON_OFFSET:
+		ifno = SEI_FILE_ANY_AST
+	case ipl < SE_NPLANETS:
+		ipli = PNOEXT2INT[ipl]
+	}
+	if prov.Iephe == SEFLG_SPKEPH && iplmoon == 0 {
+		// the kernel that provides the body; the earth for the sun
+		prov.Fnam = spkFileName(ipli, tjd)
+		return prov
+	}
+	prov.Denum = swiGetDenum(int32(ipli), prov.Iephe)
+	if prov.Iephe == SEFLG_SWIEPH || ifno != SEI_FILE_PLANET && ifno != SEI_FILE_MOON {
+		// asteroids and planetary moons are always read from files
+		prov.Fnam = swed.Fidat[ifno].Fnam
+	} else if prov.Iephe == SEFLG_JPLEPH && js != nil && js.jplfptr != nil {
+		prov.Fnam = js.jplfptr.Name()
+	}
+	return prov
+}
+
+// notSupportedEphe appends an error for an ephemeris that is not supported (the Moshier ephemeris for barycentric
+// positions) to serr and returns NOT_AVAILABLE, so that the next ephemeris of the chain is tried.
+func notSupportedEphe(epheflag int32, serr *string) int {
+	if serr != nil && epheflag == SEFLG_MOSEPH {
+		*serr += "Moshier ephemeris is not supported."
+	}
+	return NOT_AVAILABLE
+}
@@ -0,0 +1,393 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Port: the functions in this file are not part of the C version. They integrate the orbit of a comet or asteroid
+// from its MPC elements (see SweLoadMpcFile) with the perturbations of the planets, with the integrator RADAU of
+// Everhart (Everhart, An efficient integrator that uses Gauss-Radau spacings, 1985) and the step size control of
+// IAS15 (Rein and Spiegel, 2015). The positions of the planets are computed with SweCalc; the orbit can be written as
+// an asteroid file of the Swiss Ephemeris, see SweWriteOrbitFile.
+
+// NBodyOrbit contains an orbit of SweIntegrateOrbit.
+type NBodyOrbit struct {
+	Name   string  // name of the comet or asteroid
+	Tstart float64 // start of the orbit, Julian day (TT)
+	Tend   float64 // end of the orbit, Julian day (TT)
+	Iephe  int32   // ephemeris of the planets; with a fallback during the integration, several flags are set
+	Denum  int32   // JPL DE number of the ephemeris of the planets
+	Nsteps int     // number of steps of the integrator
+	steps  []radauStep
+}
+
+// radauStep contains a step of the integrator: time, step size (negative for backward integration), heliocentric
+// position, speed and acceleration at the start of the step, and the coefficients of the acceleration polynomial.
+type radauStep struct {
+	t, dt   float64
+	x, v, a [3]float64
+	b       [7][3]float64
+}
+
+// radauH are the Gauss-Radau spacings of the substeps.
+var radauH = [8]float64{0, 0.0562625605369221464656521910318, 0.180240691736892364987579942780,
+	0.352624717113169637373907769648, 0.547153626330555383001448554766, 0.734210177215410531523210605558,
+	0.885320946839095768090359771030, 0.977520613561287501891174488626}
+
+// radauD[k][m] is the coefficient of s^(m+1) of the polynomial s * (s - h1) * ... * (s - hk), with which the divided
+// differences g of the accelerations at the substeps are converted to the coefficients b of the acceleration
+// a(s) = a0 + b0 * s + b1 * s^2 + ... + b6 * s^7.
+var radauD = func() (d [7][7]float64) {
+	for k := 0; k < 7; k++ {
+		p := []float64{1}
+		for j := 1; j <= k; j++ {
+			q := make([]float64, len(p)+1)
+			for m, c := range p {
+				q[m+1] += c
+				q[m] -= radauH[j] * c
+			}
+			p = q
+		}
+		copy(d[k][:], p)
+	}
+	return d
+}()
+
+const (
+	radauEpsilon = 1e-9 // accuracy parameter of IAS15, results in errors at the level of the rounding errors
+	radauSafety  = 0.25 // a step is repeated if the new step size is smaller by this factor
+)
+
+// integBody is a perturbing body: body number and ratio of the mass of the sun to the mass of the body.
+type integBody struct {
+	ipl  int
+	mrat float64
+}
+
+// integBodies are the perturbing bodies, the masses from the IAU 2009 System of Astronomical Constants (the moon
+// from the earth and EARTH_MOON_MRAT).
+var integBodies = []integBody{
+	{SE_MERCURY, 6.0236e6},
+	{SE_VENUS, 4.08523719e5},
+	{SE_EARTH, 3.32946048e5},
+	{SE_MOON, 3.32946048e5 * EARTH_MOON_MRAT},
+	{SE_MARS, 3.09870359e6},
+	{SE_JUPITER, 1.047348644e3},
+	{SE_SATURN, 3.4979018e3},
+	{SE_URANUS, 2.290298e4},
+	{SE_NEPTUNE, 1.941226e4},
+	{SE_PLUTO, 1.36566e8},
+}
+
+// integrator contains the state of SweIntegrateOrbit.
+type integrator struct {
+	epheflag int32
+	orbit    *NBodyOrbit
+	bodies   []integBody
+	// heliocentric positions of the perturbing bodies, for the times of the substeps of the current step
+	planets map[float64][][3]float64
+}
+
+// SweIntegrateOrbit integrates the orbit of the comet or asteroid obj (see SweLoadMpcFile) from the epoch of its
+// elements (the perihelion passage for comets without an epoch) to tjdStart and tjdEnd (TT). The orbit is
+// heliocentric and perturbed by Mercury .. Pluto and the moon, which are computed with the ephemeris of iflag
+// (SEFLG_JPLEPH, SEFLG_SWIEPH, SEFLG_MOSEPH or SEFLG_SPKEPH, with the fallback of SweCalc). The equations of motion
+// are Newtonian; the perturbations by other asteroids and the nongravitational forces of comets are neglected.
+// Returns the orbit, see NBodyOrbit.Position and SweWriteOrbitFile, or an error if a planet cannot be computed.
+func SweIntegrateOrbit(obj MpcObject, tjdStart, tjdEnd float64, iflag int32) (*NBodyOrbit, error) {
+	if !(tjdStart < tjdEnd) || math.IsInf(tjdStart, 0) || math.IsInf(tjdEnd, 0) {
+		return nil, fmt.Errorf("invalid time range %f - %f", tjdStart, tjdEnd)
+	}
+	swiInitSwedIfStart()
+	epheflag := iflag & SEFLG_EPHMASK
+	if epheflag == 0 {
+		epheflag = SEFLG_DEFAULTEPH
+	}
+	in := integrator{epheflag: epheflag, orbit: &NBodyOrbit{Name: obj.Name, Tstart: tjdStart, Tend: tjdEnd},
+		bodies: integBodies}
+	epoch := obj.Epoch
+	if epoch == 0 {
+		epoch = obj.Tperi
+	}
+	var x0 [6]float64
+	mpcHelio(&obj, epoch, x0[:])
+	// the orbit refers to the ICRS, as the ephemerides from DE403 on (see appPosEtcPlan); the elements to J2000
+	if _, err := in.accel(epoch, [3]float64{x0[0], x0[1], x0[2]}); err != nil {
+		return nil, err
+	}
+	if in.orbit.Denum >= 403 {
+		SwiBias(x0[:], J2000, SEFLG_SPEED, true)
+	}
+	var forward, backward []radauStep
+	var err error
+	if tjdEnd > epoch {
+		if forward, err = in.integrate(epoch, math.Max(tjdEnd, epoch), x0); err != nil {
+			return nil, err
+		}
+	}
+	if tjdStart < epoch {
+		if backward, err = in.integrate(epoch, math.Min(tjdStart, epoch), x0); err != nil {
+			return nil, err
+		}
+	}
+	// the steps in the order of time
+	for i := len(backward) - 1; i >= 0; i-- {
+		in.orbit.steps = append(in.orbit.steps, backward[i])
+	}
+	in.orbit.steps = append(in.orbit.steps, forward...)
+	in.orbit.Nsteps = len(in.orbit.steps)
+	return in.orbit, nil
+}
+
+// Position returns the heliocentric position and speed at tjd (TT) in AU and AU/day, for the ICRS (equator J2000 for
+// ephemerides before DE403), or an error if tjd is outside the orbit.
+func (o *NBodyOrbit) Position(tjd float64) ([6]float64, error) {
+	var xx [6]float64
+	if !(tjd >= o.Tstart && tjd <= o.Tend) {
+		return xx, fmt.Errorf("jd %f outside orbit %f - %f", tjd, o.Tstart, o.Tend)
+	}
+	// the first step that ends at or after tjd
+	i := sort.Search(len(o.steps), func(i int) bool {
+		st := &o.steps[i]
+		return math.Max(st.t, st.t+st.dt) >= tjd
+	})
+	if i == len(o.steps) {
+		i--
+	}
+	st := &o.steps[i]
+	s := (tjd - st.t) / st.dt
+	x, v := st.state(s)
+	copy(xx[:3], x[:])
+	copy(xx[3:], v[:])
+	return xx, nil
+}
+
+// SweWriteOrbitFile writes the orbit o as the file of the asteroid number nr, e.g. ast0/se00433.se1 in the
+// ephemeris path, with Chebyshev series of ncoe coefficients for segments of dseg days, e.g. 32 days and 13
+// coefficients for main belt asteroids; the packing of the coefficients limits the accuracy to about 1e-8 AU. The
+// positions are then computed by SweCalc for SE_AST_OFFSET + nr with the Swiss Ephemeris.
+func SweWriteOrbitFile(o *NBodyOrbit, fnam string, nr int, dseg float64, ncoe int) error {
+	if !(dseg > 0) {
+		return fmt.Errorf("invalid segment size %f", dseg)
+	}
+	nseg := int((o.Tend - o.Tstart) / dseg)
+	if nseg < 1 {
+		return fmt.Errorf("orbit %f - %f shorter than a segment of %f days", o.Tstart, o.Tend, dseg)
+	}
+	bd := EphemerisBody{Ipl: SE_AST_OFFSET + nr, Iflg: SEI_FLG_HELIO, Ncoe: ncoe, Tfstart: o.Tstart, Dseg: dseg}
+	bd.Segments = FitChebyshev(func(tjd float64) [3]float64 {
+		// the nodes of the segments are within the orbit
+		xx, _ := o.Position(tjd)
+		return [3]float64{xx[0], xx[1], xx[2]}
+	}, bd.Tfstart, bd.Dseg, nseg, bd.Ncoe)
+	ef := EphemerisFile{Fversion: 2, Copyright: "orbit integrated from MPC elements", SwephDenum: o.Denum,
+		Astnam: o.Name, Bodies: []EphemerisBody{bd}}
+	return WriteEphemerisFile(fnam, &ef)
+}
+
+// integrate integrates the heliocentric position and speed x0 from t0 to t1 and returns the steps.
+func (in *integrator) integrate(t0, t1 float64, x0 [6]float64) ([]radauStep, error) {
+	var steps []radauStep
+	st := radauStep{t: t0}
+	copy(st.x[:], x0[:3])
+	copy(st.v[:], x0[3:])
+	var err error
+	if st.a, err = in.accel(t0, st.x); err != nil {
+		return nil, err
+	}
+	// a small fraction of the orbital period as first step
+	r := math.Sqrt(dotProd(st.x, st.x))
+	st.dt = math.Copysign(0.01*r*math.Sqrt(r)/KGAUSS, t1-t0)
+	for st.t != t1 {
+		if math.Abs(st.dt) >= math.Abs(t1-st.t) {
+			st.dt = t1 - st.t
+		}
+		dtNew, err := in.step(&st)
+		if err != nil {
+			return nil, err
+		}
+		if math.Abs(dtNew) < radauSafety*math.Abs(st.dt) {
+			// repeat with the smaller step size
+			st.rescale(dtNew / st.dt)
+			st.dt = dtNew
+			continue
+		}
+		steps = append(steps, st)
+		// start of the next step
+		x, v := st.state(1)
+		next := radauStep{t: st.t + st.dt, x: x, v: v, b: st.b}
+		if math.Abs(t1-next.t) < 1e-9*math.Abs(st.dt) {
+			next.t = t1
+		}
+		if next.a, err = in.accel(next.t, next.x); err != nil {
+			return nil, err
+		}
+		next.predict(dtNew / st.dt)
+		next.dt = dtNew
+		st = next
+		if len(steps) > 10000000 {
+			return nil, errors.New("too many steps of the integrator")
+		}
+	}
+	return steps, nil
+}
+
+// iterate computes the coefficients b of the step st, starting from the predicted b, by the predictor-corrector
+// iteration of Everhart, with the accelerations of accel, and returns the step size for the next step.
+func (st *radauStep) iterate(accel func(t float64, x [3]float64) ([3]float64, error)) (float64, error) {
+	var g [7][3]float64
+	for i := 0; i < 3; i++ {
+		// divided differences from the predicted b
+		for k := 6; k >= 0; k-- {
+			g[k][i] = st.b[k][i]
+			for j := k + 1; j <= 6; j++ {
+				g[k][i] -= radauD[j][k] * g[j][i]
+			}
+		}
+	}
+	var amax float64
+	b6prev := st.b[6]
+	for iter := 0; iter < 12; iter++ {
+		amax = math.Max(math.Abs(st.a[0]), math.Max(math.Abs(st.a[1]), math.Abs(st.a[2])))
+		for n := 1; n <= 7; n++ {
+			s := radauH[n]
+			x, _ := st.state(s)
+			a, err := accel(st.t+s*st.dt, x)
+			if err != nil {
+				return 0, err
+			}
+			for i := 0; i < 3; i++ {
+				amax = math.Max(amax, math.Abs(a[i]))
+				// divided difference g[n-1] with the new acceleration
+				tmp := (a[i] - st.a[i]) / s
+				for j := 1; j < n; j++ {
+					tmp = (tmp - g[j-1][i]) / (s - radauH[j])
+				}
+				g[n-1][i] = tmp
+				for m := 0; m < n; m++ {
+					sum := 0.0
+					for k := m; k <= 6; k++ {
+						sum += radauD[k][m] * g[k][i]
+					}
+					st.b[m][i] = sum
+				}
+			}
+		}
+		// convergence of the highest coefficient
+		var db6 float64
+		for i := 0; i < 3; i++ {
+			db6 = math.Max(db6, math.Abs(st.b[6][i]-b6prev[i]))
+		}
+		b6prev = st.b[6]
+		if db6 <= 1e-16*amax {
+			break
+		}
+	}
+	// new step size from the highest coefficient (IAS15)
+	b6max := math.Max(math.Abs(st.b[6][0]), math.Max(math.Abs(st.b[6][1]), math.Abs(st.b[6][2])))
+	if b6max == 0 || amax == 0 {
+		return st.dt / radauSafety, nil
+	}
+	f := math.Pow(radauEpsilon/(b6max/amax), 1.0/7)
+	return st.dt * math.Min(f, 1/radauSafety), nil
+}
+
+// step computes the step st, see iterate, with the accelerations of integrator in.
+func (in *integrator) step(st *radauStep) (float64, error) {
+	in.planets = make(map[float64][][3]float64, 8)
+	return st.iterate(in.accel)
+}
+
+// state returns the position and speed at the fraction s of the step.
+func (st *radauStep) state(s float64) ([3]float64, [3]float64) {
+	var x, v [3]float64
+	h := s * st.dt
+	for i := 0; i < 3; i++ {
+		b := &st.b
+		x[i] = st.x[i] + h*st.v[i] + h*h*(st.a[i]/2+s*(b[0][i]/6+s*(b[1][i]/12+s*(b[2][i]/20+s*(b[3][i]/30+
+			s*(b[4][i]/42+s*(b[5][i]/56+s*b[6][i]/72)))))))
+		v[i] = st.v[i] + h*(st.a[i]+s*(b[0][i]/2+s*(b[1][i]/3+s*(b[2][i]/4+s*(b[3][i]/5+s*(b[4][i]/6+
+			s*(b[5][i]/7+s*b[6][i]/8)))))))
+	}
+	return x, v
+}
+
+// predict converts the coefficients b of the step st for the next step, which is q times the size, by extrapolation
+// of the acceleration polynomial. The position, speed and acceleration of st must be those of the next step.
+func (st *radauStep) predict(q float64) {
+	var b [7][3]float64
+	for m := 1; m <= 7; m++ {
+		qm := math.Pow(q, float64(m))
+		for j := m; j <= 7; j++ {
+			c := binomial(j, m) * qm
+			for i := 0; i < 3; i++ {
+				b[m-1][i] += c * st.b[j-1][i]
+			}
+		}
+	}
+	st.b = b
+}
+
+// rescale converts the coefficients b of the step st to a step of q times the size from the same start.
+func (st *radauStep) rescale(q float64) {
+	qm := 1.0
+	for m := 0; m < 7; m++ {
+		qm *= q
+		for i := 0; i < 3; i++ {
+			st.b[m][i] *= qm
+		}
+	}
+}
+
+// binomial returns the binomial coefficient n over k.
+func binomial(n, k int) float64 {
+	c := 1.0
+	for i := 1; i <= k; i++ {
+		c = c * float64(n-k+i) / float64(i)
+	}
+	return c
+}
+
+// accel returns the heliocentric acceleration at x and tjd from the sun and the perturbing bodies.
+func (in *integrator) accel(tjd float64, x [3]float64) ([3]float64, error) {
+	var a [3]float64
+	planets, ok := in.planets[tjd]
+	if !ok && len(in.bodies) > 0 {
+		planets = make([][3]float64, len(in.bodies))
+		iflag := in.epheflag | SEFLG_HELCTR | SEFLG_XYZ | SEFLG_EQUATORIAL | SEFLG_J2000 | SEFLG_ICRS |
+			SEFLG_TRUEPOS | SEFLG_NONUT | SEFLG_NOABERR | SEFLG_NOGDEFL
+		for i, bd := range in.bodies {
+			xx, iflgret, prov, err := SweCalc(tjd, bd.ipl, iflag)
+			if iflgret == ERR {
+				return a, err
+			}
+			if in.orbit.Iephe == 0 {
+				in.orbit.Denum = prov.Denum
+			}
+			in.orbit.Iephe |= iflgret & SEFLG_EPHMASK
+			planets[i] = [3]float64{xx[0], xx[1], xx[2]}
+		}
+		if in.planets != nil {
+			in.planets[tjd] = planets
+		}
+	}
+	mu := KGAUSS * KGAUSS
+	r := math.Sqrt(dotProd(x, x))
+	for i := 0; i < 3; i++ {
+		a[i] = -mu * x[i] / (r * r * r)
+	}
+	// direct and indirect perturbations, the latter from the acceleration of the sun
+	for j, bd := range in.bodies {
+		xp := planets[j]
+		d := [3]float64{xp[0] - x[0], xp[1] - x[1], xp[2] - x[2]}
+		rd := math.Sqrt(dotProd(d, d))
+		rp := math.Sqrt(dotProd(xp, xp))
+		for i := 0; i < 3; i++ {
+			a[i] += mu / bd.mrat * (d[i]/(rd*rd*rd) - xp[i]/(rp*rp*rp))
+		}
+	}
+	return a, nil
+}
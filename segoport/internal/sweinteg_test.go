@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRadauH(t *testing.T) {
+	// the spacings are the roots of P7 + P8 on [0, 1], with the Legendre polynomials Pn
+	for _, h := range radauH[1:] {
+		x := 2*h - 1
+		p0, p1 := 1.0, x
+		var p7 float64
+		for n := 1; n < 8; n++ {
+			p7 = p1
+			p0, p1 = p1, (float64(2*n+1)*x*p1-float64(n)*p0)/float64(n+1)
+		}
+		if math.Abs(p7+p1) > 1e-14 {
+			t.Errorf("P7 + P8 = %g at h = %.15f", p7+p1, h)
+		}
+	}
+}
+
+func TestRadauKepler(t *testing.T) {
+	// without perturbing bodies the orbit is the two-body orbit of mpcKepler, here 50 revolutions of an ellipse with
+	// eccentricity 0.9, forwards and backwards
+	swiInitSwedIfStart()
+	obj := MpcObject{Tperi: J2000, Q: 0.5, Ecce: 0.9, Peri: 30, Node: 40, Incl: 50}
+	period := TWOPI / KGAUSS * math.Pow(obj.Q/(1-obj.Ecce), 1.5)
+	var x0, want [6]float64
+	mpcHelio(&obj, J2000+100, x0[:])
+	for _, t1 := range []float64{J2000 + 100 + 50*period, J2000 + 100 - 50*period} {
+		in := integrator{orbit: &NBodyOrbit{}}
+		steps, err := in.integrate(J2000+100, t1, x0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := NBodyOrbit{Tstart: math.Min(J2000+100, t1), Tend: math.Max(J2000+100, t1), steps: steps}
+		if t1 < J2000 {
+			for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+				steps[i], steps[j] = steps[j], steps[i]
+			}
+		}
+		for _, tjd := range []float64{t1, (J2000 + 100 + t1) / 2, J2000 + 100 + (t1-J2000-100)*1e-4} {
+			xx, err := o.Position(tjd)
+			mpcHelio(&obj, tjd, want[:])
+			for i := range xx {
+				if err != nil || math.Abs(xx[i]-want[i]) > 1e-9 {
+					t.Errorf("orbit at %.3f, %d steps: %v, %v; want %v", tjd, len(steps), xx, err, want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestIntegratePerturbations(t *testing.T) {
+	// Mars integrated with the perturbations of the other planets follows the ephemeris of Moshier within its
+	// accuracy, while the two-body orbit deviates by 7e-4 AU in a year
+	swiInitSwedIfStart()
+	iflag := int32(SEFLG_MOSEPH | SEFLG_SPEED | SEFLG_HELCTR | SEFLG_XYZ | SEFLG_EQUATORIAL | SEFLG_J2000 |
+		SEFLG_ICRS | SEFLG_TRUEPOS | SEFLG_NONUT | SEFLG_NOABERR | SEFLG_NOGDEFL)
+	x0, _, _, err := SweCalc(J2000, SE_MARS, iflag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := integrator{epheflag: SEFLG_MOSEPH, orbit: &NBodyOrbit{}}
+	for _, bd := range integBodies {
+		if bd.ipl != SE_MARS {
+			in.bodies = append(in.bodies, bd)
+		}
+	}
+	steps, err := in.integrate(J2000, J2000+365, x0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := NBodyOrbit{Tstart: J2000, Tend: J2000 + 365, steps: steps}
+	xx, _ := o.Position(J2000 + 365)
+	want, _, _, _ := SweCalc(J2000+365, SE_MARS, iflag)
+	if d := math.Sqrt(math.Pow(xx[0]-want[0], 2) + math.Pow(xx[1]-want[1], 2) + math.Pow(xx[2]-want[2], 2)); d > 3e-5 {
+		t.Errorf("Mars after a year: %v; want %v, difference %g AU", xx, want, d)
+	}
+}
+
+func TestSweIntegrateOrbit(t *testing.T) {
+	defer SweClearMpcObjects()
+	defer SweSetEphePath("")
+	dir := writeMpcFiles(t)
+	SweSetEphePath(dir)
+	if _, err := SweLoadMpcFile("MPCORB.DAT"); err != nil {
+		t.Fatal(err)
+	}
+	ceres, err := SweFindMpcObject("Ceres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := SweIntegrateOrbit(ceres, ceres.Epoch-200, ceres.Epoch+400, SEFLG_MOSEPH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Name != "(1) Ceres" || o.Iephe != SEFLG_MOSEPH || o.Denum != 403 || o.Nsteps < 10 {
+		t.Errorf("SweIntegrateOrbit = %+v", o)
+	}
+	// the elements are osculating at the epoch; the perturbations, mostly by Jupiter, grow with the time from the
+	// epoch, to about 1e-4 AU in a year
+	var want [6]float64
+	for _, tt := range []struct{ tjd, min, max float64 }{{ceres.Epoch, 0, 1e-14}, {ceres.Epoch + 10, 1e-8, 1e-6},
+		{ceres.Epoch - 200, 1e-6, 1e-3}, {ceres.Epoch + 400, 1e-5, 1e-3}} {
+		xx, err := o.Position(tt.tjd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mpcHelio(&ceres, tt.tjd, want[:])
+		SwiBias(want[:], J2000, SEFLG_SPEED, true)
+		d := math.Sqrt(math.Pow(xx[0]-want[0], 2) + math.Pow(xx[1]-want[1], 2) + math.Pow(xx[2]-want[2], 2))
+		if d < tt.min || d > tt.max {
+			t.Errorf("orbit at %.1f differs by %g AU from the two-body orbit; want %g .. %g", tt.tjd, d, tt.min, tt.max)
+		}
+	}
+	if _, err := o.Position(ceres.Epoch + 401); err == nil {
+		t.Errorf("Position outside the orbit: no error")
+	}
+	if _, err := SweIntegrateOrbit(ceres, ceres.Epoch, ceres.Epoch, SEFLG_MOSEPH); err == nil {
+		t.Errorf("SweIntegrateOrbit with empty time range: no error")
+	}
+	// the orbit as asteroid file in the ephemeris path
+	fnam := filepath.Join(dir, "ast99", "se99999.se1")
+	if err := os.Mkdir(filepath.Dir(fnam), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SweWriteOrbitFile(o, fnam, 99999, 32, 13); err != nil {
+		t.Fatal(err)
+	}
+	h, err := ReadFileHeader(fnam)
+	if err != nil || h.File.Astnam != "(1) Ceres" || h.File.Tfstart != o.Tstart || h.File.Tfend != o.Tstart+18*32 ||
+		h.File.SwephDenum != 403 {
+		t.Fatalf("ReadFileHeader: %+v, %v", h, err)
+	}
+	for iseg, segp := range readSegments(t, fnam, SEI_FILE_ANY_AST, SEI_ANYBODY) {
+		for x := -1.0; x <= 1; x += 0.25 {
+			xx, _ := o.Position(o.Tstart + float64(iseg)*32 + (x+1)*16)
+			for icoord := 0; icoord < 3; icoord++ {
+				// the coefficients are stored as multiples of rmax / 2e9 (rmax about 3 here)
+				if v := swiEcheb(x, segp[icoord*13:], 13); math.Abs(v-xx[icoord]) > 1e-8 {
+					t.Errorf("segment %d, x = %f: coordinate %d = %.12f; want %.12f", iseg, x, icoord, v, xx[icoord])
+				}
+			}
+		}
+	}
+	// SweCalc reads the file, the sun from Moshier without planetary files
+	helio := int32(SEFLG_SWIEPH | SEFLG_HELCTR | SEFLG_XYZ | SEFLG_EQUATORIAL | SEFLG_J2000 | SEFLG_ICRS |
+		SEFLG_TRUEPOS)
+	xx, _, prov, _ := SweCalc(ceres.Epoch+100, SE_AST_OFFSET+99999, helio)
+	want, _ = o.Position(ceres.Epoch + 100)
+	if prov.Fnam != fnam || math.Abs(xx[0]-want[0]) > 1e-8 ||
+		math.Abs(xx[1]-want[1]) > 1e-8 || math.Abs(xx[2]-want[2]) > 1e-8 {
+		t.Errorf("SweCalc of the orbit file = %.10f, %+v; want %.10f", xx[:3], prov, want[:3])
+	}
+	if err := SweWriteOrbitFile(o, fnam, 99999, 1000, 13); err == nil {
+		t.Errorf("SweWriteOrbitFile with a segment longer than the orbit: no error")
+	}
+}
@@ -1,91 +1,672 @@
 package internal
 
 import (
+	"encoding/binary"
 	"fmt"
+	"io"
+	"math"
 	"os"
 )
 
-// This package is probably obsolete and only used for the JPL ephemeris.
+// Reader for the binary files of the JPL planetary ephemerides DE102 - DE441.
 
-// line 910 swi_close_jpl_File
+// ===== 0093 ===== jpl_save swejpl.c-0093 ===========================================================================
 
 // JplSave represents the JPL ephemeris data and state
 type JplSave struct {
 	jplfname  string        // JPL file name
 	jplfpath  string        // JPL file path
 	jplfptr   *os.File      // File pointer
-	doReorder bool          // Reordering flag
+	doReorder bool          // the file has another byte order than little-endian
 	ehCval    [400]float64  // Constant values
-	ehSs      [3]float64    // Solar system barycenter data
+	ehSs      [3]float64    // start and end epoch, segment size in days
 	ehAu      float64       // Astronomical unit
 	ehEmrat   float64       // Earth-Moon mass ratio
 	ehDenum   int32         // DE number
 	ehNcon    int32         // Number of constants
-	ehIpt     [39]int32     // Integer parameters
+	ehIpt     [39]int32     // pointers to the coefficients of the bodies, see below
 	chCnam    [400][6]byte  // Constant names (6 chars each)
 	pv        [78]float64   // Position/velocity array
 	pvsun     [6]float64    // Sun position/velocity
-	buf       [1500]float64 // Working buffer
+	buf       [1500]float64 // coefficients of the current record
 	pc        [18]float64   // Position coefficients
 	vc        [18]float64   // Velocity coefficients
 	ac        [18]float64   // Acceleration coefficients
 	jc        [18]float64   // Jerk coefficients
-	doKm      bool          // Kilometer flag
+	doKm      bool          // return km and km/sec instead of au and au/day
+	// Port: static variables of interp() and state() in C
+	np, nv, nac, njk int
+	twot             float64
+	irecsz           int32 // record size in bytes
+	nrl              int32 // number of the record in buf
+	ncoeffs          int32 // number of coefficients in a record
 }
 
-// Global variable (equivalent to static TLS struct jpl_save *js)
+// js holds the open JPL file (static TLS struct jpl_save *js in C)
 var js *JplSave
 
-// state calculates state vectors for requested bodies
-func state(et float64, list []int32, doBary bool,
-	pv, pvsun, nut []float64, serr *string) error {
-	// Implementation goes here
-	return nil
+// information about ehIpt[] and buf[]
+// DE200	DE102		  	DE403
+// 3	3	  ipt[0] 	3	body 0 (mercury) starts at buf[2]
+// 12	15	  ipt[1]	14	body 0, ncf = coefficients per component
+// 4	2	  ipt[2]	4		na = nintervals, tot 14*4*3=168
+// 147	93	  ipt[3]	171	body 1 (venus) starts at buf[170]
+// 12	15	  ipt[4]	10		ncf = coefficients per component
+// 1	1	  ipt[5]	2		total 10*2*3=60
+// 183	138	  ipt[6]	231	body 2 (earth) starts at buf[230]
+// 15	15	  ipt[7]	13		ncf = coefficients per component
+// 2	2	  ipt[8]	2		total 13*2*3=78
+// 273	228	  ipt[9]	309	body 3 (mars) starts at buf[308]
+// 10	10	  ipt[10]	11		ncf = coefficients per component
+// 1	1	  ipt[11]	1		total 11*1*3=33
+// 303	258	  ipt[12]	342	body 4 (jupiter) at buf[341]
+// 9	9	  ipt[13]	8		total 8 * 1 * 3 = 24
+// 1	1	  ipt[14]	1
+// 330	285	  ipt[15]	366	body 5 (saturn) at buf[365]
+// 8	8	  ipt[16]	7		total 7 * 1 * 3 = 21
+// 1	1	  ipt[17]	1
+// 354	309	  ipt[18]	387	body 6 (uranus) at buf[386]
+// 8	8	  ipt[19]	6		total 6 * 1 * 3 = 18
+// 1	1	  ipt[20]	1
+// 378	333	  ipt[21]	405	body 7 (neptune) at buf[404]
+// 6	6	  ipt[22]	6		total 18
+// 1	1	  ipt[23]	1
+// 396	351	  ipt[24]	423	body 8 (pluto) at buf[422]
+// 6	6	  ipt[25]	6		total 18
+// 1	1	  ipt[26]	1
+// 414	369	  ipt[27]	441	body 9 (moon) at buf[440]
+// 12	15	  ipt[28]	13		total 13 * 8 * 3 = 312
+// 8	8	  ipt[29]	8
+// 702	729	  ipt[30]	753	SBARY SUN, starts at buf[752]
+// 15	15	  ipt[31]	11	SBARY SUN, ncf = coeff per component
+// 1	1	  ipt[32]	2		   total 11*2*3=66
+// 747	774	  ipt[33]	819	nutations, starts at buf[818]
+// 10	0	  ipt[34]	10		total 10 * 4 * 2 = 80
+// 4	0	  ipt[35]	4	(nutation only two coordinates)
+// 0	0	  ipt[36]	899	librations, start at buf[898]
+// 0	0	  ipt[37]	10		total 10 * 4 * 3 = 120
+// 0	0	  ipt[38]	4
+//
+// 					last element of buf[1017]
+// buf[0] contains start jd and buf[1] end jd of segment;
+// each segment is 32 days in de403, 64 days in DE102, 32 days in  DE200
+//
+// Length of blocks: DE406 = 1456*4=5824 bytes = 728 double
+//                   DE405 = 2036*4=8144 bytes = 1018 double
+//                   DE404 = 1456*4=5824 bytes = 728 double
+//                   DE403 = 2036*4=8144 bytes = 1018 double
+//                   DE200 = 1652*4=6608 bytes = 826 double
+//                   DE102 = 1546*4=6184 bytes = 773 double
+//                   each DE102 record has 53*8=424 fill bytes so that the records have the same length as DE200.
+
+// byteOrder returns the byte order of the open file.
+// Port: C reads the file in the byte order of the machine and reorders the bytes, if the segment size is implausible.
+// Here the files are read as little-endian, or as big-endian if the segment size is implausible.
+func (j *JplSave) byteOrder() binary.ByteOrder {
+	if j.doReorder {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
 }
 
-// interp performs interpolation of position/velocity
-func interp(buf []float64, t, intv float64,
-	ncfin, ncmin, nain int32, ifl int32,
-	pv []float64) error {
-	// Implementation goes here
-	return nil
+// read reads data (a fixed size value or a slice of fixed size values) from the current position of the file.
+func (j *JplSave) read(data any) bool {
+	return binary.Read(j.jplfptr, j.byteOrder(), data) == nil
 }
 
-// fsizer determines file size requirements
-func fsizer(serr *string) (int32, error) {
-	// Implementation goes here
-	return 0, nil
+// ===== 0181 ===== fsizer swejpl.c-0181 =============================================================================
+
+// fsizer opens the file jplfname, with a phony record length, reads the first record, and uses the info to compute
+// ksize, the number of single precision words in a record.
+// Returns ksize (record size of ephemeris data) or NOT_AVAILABLE. jplfptr is opened on return.
+// note 26-aug-2008: now record size is computed by fsizer(), not set to a fixed value depending as in previous
+// releases. The caller of fsizer() will verify by data comparison whether it computed correctly.
+func fsizer(serr *string) int32 {
+	var ttl [6 * 14 * 3]byte
+	var ss [3]float64
+	var ncon, numde int32
+	var au, emrat float64
+	var lpt [3]int32
+	var err error
+	// Port: C passes SEI_FILE_PLANET, which overwrites the name of the open planetary file of the Swiss Ephemeris
+	if js.jplfptr, err = SwiFopen(-1, js.jplfname, js.jplfpath); err != nil {
+		if serr != nil {
+			*serr = err.Error()
+		}
+		return NOT_AVAILABLE
+	}
+	// ttl = ephemeris title, e.g.
+	// "JPL Planetary Ephemeris DE404/LE404
+	//  Start Epoch: JED=   625296.5-3001 DEC 21 00:00:00
+	//  Final Epoch: JED=  2817168.5 3001 JAN 17 00:00:00c
+	if _, err = io.ReadFull(js.jplfptr, ttl[:]); err != nil {
+		return NOT_AVAILABLE
+	}
+	// cnam = names of constants
+	if !js.read(&js.chCnam) {
+		return NOT_AVAILABLE
+	}
+	// ss[0] = start epoch of ephemeris
+	// ss[1] = end epoch
+	// ss[2] = segment size in days
+	if !js.read(&ss) {
+		return NOT_AVAILABLE
+	}
+	// reorder ?
+	js.doReorder = ss[2] < 1 || ss[2] > 200
+	if js.doReorder {
+		js.jplfptr.Seek(int64(len(ttl)+len(js.chCnam)*6), io.SeekStart)
+		js.read(&ss)
+	}
+	js.ehSs = ss
+	// plausibility test of these constants. Start and end date must be between -20000 and +20000, segment size >= 1
+	// and <= 200
+	if js.ehSs[0] < -5583942 || js.ehSs[1] > 9025909 || js.ehSs[2] < 1 || js.ehSs[2] > 200 {
+		if serr != nil {
+			*serr = "alleged ephemeris file has invalid format."
+			if len(*serr)+len(js.jplfname)+3 < AS_MAXCH {
+				*serr = fmt.Sprintf("alleged ephemeris file (%s) has invalid format.", js.jplfname)
+			}
+		}
+		return NOT_AVAILABLE
+	}
+	// ncon = number of constants, au = astronomical unit, emrat = earth moon mass ratio
+	if !js.read(&ncon) || !js.read(&au) || !js.read(&emrat) {
+		return NOT_AVAILABLE
+	}
+	// ipt[i+0]: coefficients of planet i start at buf[ipt[i+0]-1]
+	// ipt[i+1]: number of coefficients (interpolation order - 1)
+	// ipt[i+2]: number of intervals in segment
+	if !js.read(js.ehIpt[:36]) {
+		return NOT_AVAILABLE
+	}
+	// numde = number of jpl ephemeris "404" with de404
+	if !js.read(&numde) {
+		return NOT_AVAILABLE
+	}
+	// read librations
+	if !js.read(&lpt) {
+		return NOT_AVAILABLE
+	}
+	// fill librations into ehIpt[36]..[38]
+	copy(js.ehIpt[36:], lpt[:])
+	js.jplfptr.Seek(0, io.SeekStart)
+	// find the number of ephemeris coefficients from the pointers
+	kmx, khi := int32(0), 0
+	for i := 0; i < 13; i++ {
+		if js.ehIpt[i*3] > kmx {
+			kmx = js.ehIpt[i*3]
+			khi = i + 1
+		}
+	}
+	nd := int32(3)
+	if khi == 12 {
+		nd = 2
+	}
+	ksize := (js.ehIpt[khi*3-3] + nd*js.ehIpt[khi*3-2]*js.ehIpt[khi*3-1] - 1) * 2
+	// de102 files give wrong ksize, because they contain 424 empty bytes per record. Fixed by hand!
+	if ksize == 1546 {
+		ksize = 1652
+	}
+	if ksize < 1000 || ksize > 5000 {
+		if serr != nil {
+			*serr = fmt.Sprintf("JPL ephemeris file does not provide valid ksize (%d)", ksize)
+		}
+		return NOT_AVAILABLE
+	}
+	return ksize
 }
 
-// reorder reorders bytes in the buffer
-func reorder(x []byte, size, number int) {
-	// Implementation goes here
+// ===== 0365 ===== swi_pleph swejpl.c-0365 ==========================================================================
+
+// swiPleph reads the jpl planetary ephemeris and gives the position and velocity of the point ntarg with respect
+// to ncent.
+// et		julian ephemeris date at which interpolation is wanted
+// ntarg	integer number of 'target' point
+// ncent	integer number of center point
+// The numbering convention for ntarg and ncent is:
+//
+//	0 = mercury           7 = neptune
+//	1 = venus             8 = pluto
+//	2 = earth             9 = moon
+//	3 = mars             10 = sun
+//	4 = jupiter          11 = solar-system barycenter
+//	5 = saturn           12 = earth-moon barycenter
+//	6 = uranus           13 = nutations (longitude and obliq)
+//	                     14 = librations, if on eph file
+//
+// (if nutations are wanted, set ntarg = 13. for librations, set ntarg = 14. set ncent=0.)
+// rrd		output 6-word array containing position and velocity of point ntarg relative to ncent. the units
+// are au and au/day. for librations the units are radians and radians per day. in the case of nutations the first
+// four words of rrd will be set to nutations and rates, having units of radians and radians/day.
+// The option is available to have the units in km and km/sec. For this, set doKm = true (default false).
+func swiPleph(et float64, ntarg, ncent int, rrd []float64, serr *string) int {
+	var list [12]int32
+	pv := js.pv[:]
+	pvsun := js.pvsun[:]
+	clear(rrd[:6])
+	if ntarg == ncent {
+		return 0
+	}
+	// check for nutation call
+	if ntarg == J_NUT {
+		if js.ehIpt[34] > 0 {
+			list[10] = 2
+			return state(et, list[:], false, pv, pvsun, rrd, serr)
+		}
+		if serr != nil {
+			*serr = "No nutations on the JPL ephemeris file;"
+		}
+		return NOT_AVAILABLE
+	}
+	if ntarg == J_LIB {
+		if js.ehIpt[37] > 0 {
+			list[11] = 2
+			if retc := state(et, list[:], false, pv, pvsun, rrd, serr); retc != OK {
+				return retc
+			}
+			copy(rrd[:6], pv[60:66])
+			return 0
+		}
+		if serr != nil {
+			*serr = "No librations on the ephemeris file;"
+		}
+		return NOT_AVAILABLE
+	}
+	// set up proper entries in 'list' array for state call
+	for _, n := range []int{ntarg, ncent} {
+		if n < J_SUN {
+			list[n] = 2
+		}
+		if n == J_MOON { // Moon needs Earth
+			list[J_EARTH] = 2
+		}
+		if n == J_EARTH { // Earth needs Moon
+			list[J_MOON] = 2
+		}
+		if n == J_EMB { // EMB needs Earth
+			list[J_EARTH] = 2
+		}
+	}
+	if retc := state(et, list[:], true, pv, pvsun, rrd, serr); retc != OK {
+		return retc
+	}
+	if ntarg == J_SUN || ncent == J_SUN {
+		copy(pv[6*J_SUN:6*J_SUN+6], pvsun)
+	}
+	if ntarg == J_SBARY || ncent == J_SBARY {
+		clear(pv[6*J_SBARY : 6*J_SBARY+6])
+	}
+	if ntarg == J_EMB || ncent == J_EMB {
+		copy(pv[6*J_EMB:6*J_EMB+6], pv[6*J_EARTH:6*J_EARTH+6])
+	}
+	if ntarg == J_EARTH && ncent == J_MOON || ntarg == J_MOON && ncent == J_EARTH {
+		clear(pv[6*J_EARTH : 6*J_EARTH+6])
+	} else {
+		if list[J_EARTH] == 2 {
+			for i := 0; i < 6; i++ {
+				pv[i+6*J_EARTH] -= pv[i+6*J_MOON] / (js.ehEmrat + 1.)
+			}
+		}
+		if list[J_MOON] == 2 {
+			for i := 0; i < 6; i++ {
+				pv[i+6*J_MOON] += pv[i+6*J_EARTH]
+			}
+		}
+	}
+	for i := 0; i < 6; i++ {
+		rrd[i] = pv[i+ntarg*6] - pv[i+ncent*6]
+	}
+	return OK
 }
 
-// readConstJpl reads constants from JPL file
-func readConstJpl(ss []float64, serr *string) error {
-	// Implementation goes here
-	return nil
+// ===== 0495 ===== interp swejpl.c-0495 =============================================================================
+
+// interp differentiates and interpolates a set of chebyshev coefficients to give pos, vel, acc, and jerk.
+// buf		chebyshev coefficients of position, starting with the first
+// t		fractional time in interval covered by coefficients at which interpolation is wanted, 0 <= t <= 1
+// intv		length of whole interval in input time units
+// ncf		number of coefficients per component
+// ncm		number of components per set of coefficients
+// na		number of sets of coefficients in full array (i.e., number of sub-intervals in full interval)
+// ifl		=1 for positions only, =2 for pos and vel, =3 for pos, vel, and acc, =4 for pos, vel, acc, and jerk
+// pv		interpolated quantities requested. assumed dimension is pv(ncm,fl).
+func interp(buf []float64, t, intv float64, ncfin, ncmin, nain, ifl int32, pv []float64) int {
+	pc, vc, ac, jc := js.pc[:], js.vc[:], js.ac[:], js.jc[:]
+	ncf := int(ncfin)
+	ncm := int(ncmin)
+	na := int(nain)
+	// get correct sub-interval number for this set of coefficients and then get normalized chebyshev time within
+	// that subinterval.
+	var dt1 float64
+	if t >= 0 {
+		dt1 = math.Floor(t)
+	} else {
+		dt1 = -math.Floor(-t)
+	}
+	temp := float64(na) * t
+	ni := int(temp - dt1)
+	// tc is the normalized chebyshev time (-1 <= tc <= 1)
+	tc := (math.Mod(temp, 1.0)+dt1)*2. - 1.
+	// check to see whether chebyshev time has changed, and compute new polynomial values if it has. (the element
+	// pc[1] is the value of t1(tc) and hence contains the value of tc on the previous call.)
+	if tc != pc[1] {
+		js.np = 2
+		js.nv = 3
+		js.nac = 4
+		js.njk = 5
+		pc[1] = tc
+		js.twot = tc + tc
+	}
+	twot := js.twot
+	// be sure that at least 'ncf' polynomials have been evaluated and are stored in the array 'pc'.
+	if js.np < ncf {
+		for i := js.np; i < ncf; i++ {
+			pc[i] = twot*pc[i-1] - pc[i-2]
+		}
+		js.np = ncf
+	}
+	// interpolate to get position for each component
+	for i := 0; i < ncm; i++ {
+		pv[i] = 0.
+		for j := ncf - 1; j >= 0; j-- {
+			pv[i] += pc[j] * buf[j+(i+ni*ncm)*ncf]
+		}
+	}
+	if ifl <= 1 {
+		return 0
+	}
+	// if velocity interpolation is wanted, be sure enough derivative polynomials have been generated and stored.
+	bma := float64(na+na) / intv
+	vc[2] = twot + twot
+	if js.nv < ncf {
+		for i := js.nv; i < ncf; i++ {
+			vc[i] = twot*vc[i-1] + pc[i-1] + pc[i-1] - vc[i-2]
+		}
+		js.nv = ncf
+	}
+	// interpolate to get velocity for each component
+	for i := 0; i < ncm; i++ {
+		pv[i+ncm] = 0.
+		for j := ncf - 1; j >= 1; j-- {
+			pv[i+ncm] += vc[j] * buf[j+(i+ni*ncm)*ncf]
+		}
+		pv[i+ncm] *= bma
+	}
+	if ifl == 2 {
+		return 0
+	}
+	// check acceleration polynomial values, and re-do if necessary
+	// Port: C sets nac to ncf before the loop, so the polynomials are never computed; this is kept.
+	bma2 := bma * bma
+	ac[3] = pc[1] * 24.
+	if js.nac < ncf {
+		js.nac = ncf
+		for i := js.nac; i < ncf; i++ {
+			ac[i] = twot*ac[i-1] + vc[i-1]*4. - ac[i-2]
+		}
+	}
+	// get acceleration for each component
+	for i := 0; i < ncm; i++ {
+		pv[i+ncm*2] = 0.
+		for j := ncf - 1; j >= 2; j-- {
+			pv[i+ncm*2] += ac[j] * buf[j+(i+ni*ncm)*ncf]
+		}
+		pv[i+ncm*2] *= bma2
+	}
+	if ifl == 3 {
+		return 0
+	}
+	// check jerk polynomial values, and re-do if necessary
+	bma3 := bma * bma2
+	jc[4] = pc[1] * 192.
+	if js.njk < ncf {
+		js.njk = ncf
+		for i := js.njk; i < ncf; i++ {
+			jc[i] = twot*jc[i-1] + ac[i-1]*6. - jc[i-2]
+		}
+	}
+	// get jerk for each component
+	for i := 0; i < ncm; i++ {
+		pv[i+ncm*3] = 0.
+		for j := ncf - 1; j >= 3; j-- {
+			pv[i+ncm*3] += jc[j] * buf[j+(i+ni*ncm)*ncf]
+		}
+		pv[i+ncm*3] *= bma3
+	}
+	return 0
 }
 
-// Helper types and constants if needed
-const (
-	JPL_MAXEPH = 400 // Maximum number of constants
-	// Add other constants as needed
-)
+// ===== 0697 ===== state swejpl.c-0697 ==============================================================================
+
+// state reads and interpolates the jpl planetary ephemeris file.
+// et		julian ephemeris epoch at which interpolation is wanted
+// list		12-word integer array specifying what interpolation is wanted for each of the bodies on the file.
+// list[i]=0, no interpolation for body i, =1, position only, =2, position and velocity.
+// The designation of the astronomical bodies by i is: 0: mercury, 1: venus, 2: earth-moon barycenter, NOT earth!,
+// 3: mars, 4: jupiter, 5: saturn, 6: uranus, 7: neptune, 8: pluto, 9: geocentric moon, 10: nutations in longitude
+// and obliquity, 11: lunar librations (if on file).
+// If called with list = nil, only the header records are read and stored in js.
+// doBary	if true, barycentric, if false, heliocentric. only the 9 planets 0..8 are affected by it.
+// pv		6 x 11 array that will contain requested interpolated quantities. the body specified by list[i] will
+// have its state in the array starting at pv[6*i]. all output vectors are referenced to the earth mean equator and
+// equinox of epoch. the moon state is always geocentric; the other nine states are either heliocentric or
+// solar-system barycentric. lunar librations, if on file, are put into pv[60:66].
+// pvsun	6-word array containing the barycentric position and velocity of the sun
+// nut		4-word array that will contain nutations and rates: d psi, d epsilon, d psi dot, d epsilon dot
+// js.doKm defines the physical units of the output states: true = km and km/sec, false = au and au/day.
+func state(et float64, list []int32, doBary bool, pv, pvsun, nut []float64, serr *string) int {
+	buf := js.buf[:]
+	ipt := js.ehIpt[:]
+	if js.jplfptr == nil {
+		ksize := fsizer(serr) // the number of single precision words in a record
+		nrecl := int32(4)
+		if ksize == NOT_AVAILABLE {
+			return NOT_AVAILABLE
+		}
+		js.irecsz = nrecl * ksize // record size in bytes
+		js.ncoeffs = ksize / 2    // # of coefficients, doubles
+		var chTtl [252]byte
+		var lpt [3]int32
+		// ttl = ephemeris title
+		if _, err := io.ReadFull(js.jplfptr, chTtl[:]); err != nil {
+			return NOT_AVAILABLE
+		}
+		// cnam = names of constants
+		// ss[0] = start epoch of ephemeris, ss[1] = end epoch, ss[2] = segment size in days
+		// ncon = number of constants, au = astronomical unit, emrat = earth moon mass ratio
+		if !js.read(&js.chCnam) || !js.read(&js.ehSs) || !js.read(&js.ehNcon) || !js.read(&js.ehAu) ||
+			!js.read(&js.ehEmrat) {
+			return NOT_AVAILABLE
+		}
+		// ipt[i+0]: coefficients of planet i start at buf[ipt[i+0]-1]
+		// ipt[i+1]: number of coefficients (interpolation order - 1)
+		// ipt[i+2]: number of intervals in segment
+		if !js.read(ipt[:36]) {
+			return NOT_AVAILABLE
+		}
+		// numde = number of jpl ephemeris "404" with de404
+		if !js.read(&js.ehDenum) || !js.read(&lpt) {
+			return NOT_AVAILABLE
+		}
+		// cval[]:  other constants in next record
+		js.jplfptr.Seek(int64(js.irecsz), io.SeekStart)
+		if !js.read(&js.ehCval) {
+			return NOT_AVAILABLE
+		}
+		// new 26-aug-2008: verify correct block size
+		copy(ipt[36:], lpt[:])
+		js.nrl = 0
+		// is file length correct?
+		flen, _ := js.jplfptr.Seek(0, io.SeekEnd)
+		// # of segments in file
+		nseg := int32((js.ehSs[1] - js.ehSs[0]) / js.ehSs[2])
+		// sum of all cheby coeffs of all planets and segments
+		var nb int64
+		for i := 0; i < 13; i++ {
+			k := int64(3)
+			if i == 11 {
+				k = 2
+			}
+			nb += int64(ipt[i*3+1]*ipt[i*3+2]) * k * int64(nseg)
+		}
+		// add start and end epochs of segments
+		nb += 2 * int64(nseg)
+		// doubles to bytes
+		nb *= 8
+		// add size of header and constants section
+		nb += 2 * int64(ksize) * int64(nrecl)
+		// some of our files are one record too long
+		if flen != nb && flen-nb != int64(ksize)*int64(nrecl) {
+			if serr != nil {
+				*serr = fmt.Sprintf("JPL ephemeris file is mutilated; length = %d instead of %d.", flen, nb)
+				if len(*serr)+len(js.jplfname) < AS_MAXCH-1 {
+					*serr = fmt.Sprintf("JPL ephemeris file %s is mutilated; length = %d instead of %d.",
+						js.jplfname, flen, nb)
+				}
+			}
+			return NOT_AVAILABLE
+		}
+		// check if start and end dates in segments are the same as in file header
+		var ts [4]float64
+		js.jplfptr.Seek(2*int64(js.irecsz), io.SeekStart)
+		if !js.read(ts[:2]) {
+			return NOT_AVAILABLE
+		}
+		js.jplfptr.Seek(int64(nseg+2-1)*int64(js.irecsz), io.SeekStart)
+		if !js.read(ts[2:]) {
+			return NOT_AVAILABLE
+		}
+		if ts[0] != js.ehSs[0] || ts[3] != js.ehSs[1] {
+			if serr != nil {
+				*serr = fmt.Sprintf("JPL ephemeris file is corrupt; start/end date check failed. %.1f != %.1f || "+
+					"%.1f != %.1f", ts[0], js.ehSs[0], ts[3], js.ehSs[1])
+			}
+			return NOT_AVAILABLE
+		}
+	}
+	if list == nil {
+		return 0
+	}
+	s := et - .5
+	etMn := math.Floor(s)
+	etFr := s - etMn // fraction of days since previous midnight
+	etMn += .5       // midnight before epoch
+	// error return for epoch out of range
+	if et < js.ehSs[0] || et > js.ehSs[1] {
+		if serr != nil {
+			*serr = fmt.Sprintf("jd %f outside JPL eph. range %.2f .. %.2f;", et, js.ehSs[0], js.ehSs[1])
+		}
+		return BEYOND_EPH_LIMITS
+	}
+	// calculate record # and relative time in interval
+	nr := int32((etMn-js.ehSs[0])/js.ehSs[2]) + 2
+	if etMn == js.ehSs[1] {
+		nr-- // end point of ephemeris, use last record
+	}
+	t := (etMn - (float64(nr-2)*js.ehSs[2] + js.ehSs[0]) + etFr) / js.ehSs[2]
+	// read correct record if not in core
+	if nr != js.nrl {
+		js.nrl = nr
+		if _, err := js.jplfptr.Seek(int64(nr)*int64(js.irecsz), io.SeekStart); err != nil ||
+			!js.read(buf[:js.ncoeffs]) {
+			// Port: the record is read again at the next call
+			js.nrl = 0
+			if serr != nil {
+				*serr = fmt.Sprintf("Read error in JPL eph. at %f\n", et)
+			}
+			return NOT_AVAILABLE
+		}
+	}
+	var intv, aufac float64
+	if js.doKm {
+		intv = js.ehSs[2] * 86400.
+		aufac = 1.
+	} else {
+		intv = js.ehSs[2]
+		aufac = 1. / js.ehAu
+	}
+	// interpolate ssbary sun
+	interp(buf[ipt[30]-1:], t, intv, ipt[31], 3, ipt[32], 2, pvsun)
+	for i := 0; i < 6; i++ {
+		pvsun[i] *= aufac
+	}
+	// check and interpolate whichever bodies are requested
+	for i := 0; i < 10; i++ {
+		if list[i] > 0 {
+			interp(buf[ipt[i*3]-1:], t, intv, ipt[i*3+1], 3, ipt[i*3+2], list[i], pv[i*6:])
+			for j := 0; j < 6; j++ {
+				if i < 9 && !doBary {
+					pv[j+i*6] = pv[j+i*6]*aufac - pvsun[j]
+				} else {
+					pv[j+i*6] *= aufac
+				}
+			}
+		}
+	}
+	// do nutations if requested (and if on file)
+	if list[10] > 0 && ipt[34] > 0 {
+		interp(buf[ipt[33]-1:], t, intv, ipt[34], 2, ipt[35], list[10], nut)
+	}
+	// get librations if requested (and if on file)
+	if list[11] > 0 && ipt[37] > 0 {
+		interp(buf[ipt[36]-1:], t, intv, ipt[37], 3, ipt[38], list[1], pv[60:])
+	}
+	return OK
+}
+
+// ===== 0898 ===== read_const_jpl swejpl.c-0898 =====================================================================
 
-// line 910 swi_close_jpl_File
+// readConstJpl calls state() to initialize the ephemeris and read in the constants. ss returns the start and end
+// epoch and the segment size in days.
+func readConstJpl(ss []float64, serr *string) int {
+	if retc := state(0.0, nil, false, nil, nil, nil, serr); retc != OK {
+		return retc
+	}
+	copy(ss[:3], js.ehSs[:])
+	return OK
+}
 
-func swiCloseJplFile() error {
+// ===== 0910 ===== swi_close_jpl_file swejpl.c-0910 =================================================================
+
+// swiCloseJplFile closes the JPL file and deletes its data.
+func swiCloseJplFile() {
 	if js != nil {
 		if js.jplfptr != nil {
-			if err := js.jplfptr.Close(); err != nil {
-				return fmt.Errorf("failed to close JPL file: %w", err)
-			}
+			js.jplfptr.Close()
 		}
-		js.jplfname = ""
-		js.jplfpath = ""
 		js = nil
 	}
-	return nil
+}
+
+// ===== 0925 ===== swi_open_jpl_file swejpl.c-0925 ==================================================================
+
+// swiOpenJplFile opens the JPL file fname in the directories of fpath and reads the constants. ss returns the start
+// and end epoch and the segment size in days. Returns OK, NOT_AVAILABLE if the file is not found or has an invalid
+// format, or ERR.
+func swiOpenJplFile(ss []float64, fname, fpath string, serr *string) int {
+	// if open, return
+	if js != nil && js.jplfptr != nil {
+		return OK
+	}
+	js = &JplSave{jplfname: fname, jplfpath: fpath}
+	retc := readConstJpl(ss, serr)
+	if retc != OK {
+		swiCloseJplFile()
+	} else {
+		// intializations for function interp()
+		js.pc[0] = 1
+		js.pc[1] = 2
+		js.vc[1] = 1
+		js.ac[2] = 4
+		js.jc[3] = 24
+	}
+	return retc
+}
+
+// ===== 0954 ===== swi_get_jpl_denum swejpl.c-0954 ==================================================================
+
+// swiGetJplDenum returns the DE number of the open JPL file.
+func swiGetJplDenum() int32 {
+	return js.ehDenum
 }
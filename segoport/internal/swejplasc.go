@@ -0,0 +1,308 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Port: the functions in this file are not part of the C version. They convert the ASCII distribution of the JPL
+// ephemerides (header.4xx and ascpNNNN.4xx) into the binary format that is read by fsizer and state.
+
+// jplAsciiHeader contains the data of the ASCII header file header.4xx.
+type jplAsciiHeader struct {
+	ncoeff int          // number of coefficients per data block (NCOEFF)
+	title  []string     // group 1010: ephemeris title, start and final epoch
+	ss     [3]float64   // group 1030: start and end epoch, segment size in days
+	cnam   []string     // group 1040: names of the constants
+	cval   []float64    // group 1041: values of the constants
+	ipt    [13][3]int32 // group 1050: start, number of coefficients and sub-intervals of the 13 bodies
+}
+
+// ConvertJplAscii converts the ASCII files of a JPL ephemeris into a binary file fnam that can be used with
+// SEFLG_JPLEPH (see SweSetJplFile). header is the header file (header.4xx), dataFiles are the files with the data
+// blocks (ascpNNNN.4xx) in chronological order; blocks that occur in two files are written once.
+// If tjdStart or tjdEnd is not 0, only the blocks that overlap tjdStart .. tjdEnd are written.
+// The binary file contains the 13 bodies of the reader: the planets, the moon, the sun, nutations and librations.
+// Further data of newer ephemerides (e.g. TT-TDB of DE440) and the constants after the first 400 are omitted.
+// Returns the start and end epoch of the binary file.
+func ConvertJplAscii(fnam, header string, dataFiles []string, tjdStart, tjdEnd float64) (float64, float64, error) {
+	h, err := readJplAsciiHeader(header)
+	if err != nil {
+		return 0, 0, err
+	}
+	// constants that are part of the header record
+	constant := func(name string) (float64, error) {
+		for i, s := range h.cnam {
+			if s == name {
+				return h.cval[i], nil
+			}
+		}
+		return 0, fmt.Errorf("constant %s missing in JPL header file %s", name, header)
+	}
+	var au, emrat, denum float64
+	if au, err = constant("AU"); err != nil {
+		return 0, 0, err
+	}
+	if emrat, err = constant("EMRAT"); err != nil {
+		return 0, 0, err
+	}
+	if denum, err = constant("DENUM"); err != nil {
+		return 0, 0, err
+	}
+	// record size as computed by fsizer
+	kmx, khi := int32(0), 0
+	for i := 0; i < 13; i++ {
+		if h.ipt[i][0] > kmx {
+			kmx = h.ipt[i][0]
+			khi = i + 1
+		}
+	}
+	nd := int32(3)
+	if khi == 12 {
+		nd = 2
+	}
+	ksize := (h.ipt[khi-1][0] + nd*h.ipt[khi-1][1]*h.ipt[khi-1][2] - 1) * 2
+	if int(ksize)/2 > h.ncoeff {
+		return 0, 0, fmt.Errorf("JPL header file %s is corrupt: coefficients of the bodies beyond NCOEFF", header)
+	}
+	// de102 records contain 424 empty bytes
+	if ksize == 1546 {
+		ksize = 1652
+	}
+	if ksize < 1000 || ksize > 5000 {
+		return 0, 0, fmt.Errorf("JPL header file %s does not provide valid ksize (%d)", header, ksize)
+	}
+	nrecl := int(ksize) / 2
+	// data blocks
+	var blocks [][]float64
+	for _, df := range dataFiles {
+		if blocks, err = readJplAsciiBlocks(df, h.ncoeff, tjdStart, tjdEnd, blocks); err != nil {
+			return 0, 0, err
+		}
+	}
+	if len(blocks) == 0 {
+		return 0, 0, fmt.Errorf("no JPL data blocks in the range JD %.1f - %.1f", tjdStart, tjdEnd)
+	}
+	ss := [3]float64{blocks[0][0], blocks[len(blocks)-1][1], h.ss[2]}
+	// header record
+	order := binary.LittleEndian
+	var b bytes.Buffer
+	w := func(data any) {
+		binary.Write(&b, order, data)
+	}
+	pad := func() {
+		b.Write(make([]byte, int(ksize)*4-b.Len()%(int(ksize)*4)))
+	}
+	w(jplAsciiTitle(h.title, ss))
+	var cnam [400][6]byte
+	var cval [400]float64
+	ncon := min(len(h.cnam), 400)
+	for i := 0; i < ncon; i++ {
+		copy(cnam[i][:], fmt.Sprintf("%-6s", h.cnam[i]))
+		cval[i] = h.cval[i]
+	}
+	w(cnam)
+	w(ss)
+	w(int32(ncon))
+	w(au)
+	w(emrat)
+	for i := 0; i < 12; i++ {
+		w(h.ipt[i])
+	}
+	w(int32(denum))
+	w(h.ipt[12])
+	pad()
+	// constants record
+	w(cval)
+	pad()
+	// data records
+	for _, blk := range blocks {
+		rec := make([]float64, nrecl)
+		copy(rec, blk)
+		w(rec)
+	}
+	if err := os.WriteFile(fnam, b.Bytes(), 0o644); err != nil {
+		return 0, 0, err
+	}
+	return ss[0], ss[1], nil
+}
+
+// readJplAsciiHeader reads the header file header.4xx of a JPL ephemeris.
+func readJplAsciiHeader(fnam string) (*jplAsciiHeader, error) {
+	data, err := os.ReadFile(fnam)
+	if err != nil {
+		return nil, err
+	}
+	h := &jplAsciiHeader{}
+	corrupt := func(group string) error {
+		return fmt.Errorf("JPL header file %s is corrupt: group %s", fnam, group)
+	}
+	// tokens of the groups, group 1010 (title) as lines
+	groups := map[string][]string{}
+	group := ""
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r", ""), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case fields[0] == "GROUP" && len(fields) == 2:
+			group = fields[1]
+			groups[group] = []string{}
+		case group == "":
+			// KSIZE= 2036    NCOEFF= 1018
+			for i := 0; i+1 < len(fields); i++ {
+				if fields[i] == "NCOEFF=" {
+					h.ncoeff, _ = strconv.Atoi(fields[i+1])
+				}
+			}
+		case group == "1010":
+			groups[group] = append(groups[group], strings.TrimSpace(line))
+		default:
+			groups[group] = append(groups[group], fields...)
+		}
+	}
+	if h.ncoeff <= 2 {
+		return nil, fmt.Errorf("JPL header file %s is corrupt: NCOEFF missing", fnam)
+	}
+	h.title = groups["1010"]
+	// start and end epoch, segment size
+	g := groups["1030"]
+	if len(g) != 3 {
+		return nil, corrupt("1030")
+	}
+	for i := range h.ss {
+		if h.ss[i], err = parseJplFloat(g[i]); err != nil {
+			return nil, corrupt("1030")
+		}
+	}
+	// names and values of constants
+	g = groups["1040"]
+	if len(g) == 0 {
+		return nil, corrupt("1040")
+	}
+	if n, err := strconv.Atoi(g[0]); err != nil || n != len(g)-1 {
+		return nil, corrupt("1040")
+	}
+	h.cnam = g[1:]
+	g = groups["1041"]
+	if len(g) != len(h.cnam)+1 {
+		return nil, corrupt("1041")
+	}
+	h.cval = make([]float64, len(h.cnam))
+	for i := range h.cval {
+		if h.cval[i], err = parseJplFloat(g[i+1]); err != nil {
+			return nil, corrupt("1041")
+		}
+	}
+	// pointers: three rows with a column for each body; newer ephemerides have more than 13 columns
+	g = groups["1050"]
+	if len(g) < 39 || len(g)%3 != 0 {
+		return nil, corrupt("1050")
+	}
+	ncol := len(g) / 3
+	for i := 0; i < 13; i++ {
+		for j := 0; j < 3; j++ {
+			n, err := strconv.Atoi(g[j*ncol+i])
+			if err != nil {
+				return nil, corrupt("1050")
+			}
+			h.ipt[i][j] = int32(n)
+		}
+	}
+	return h, nil
+}
+
+// readJplAsciiBlocks reads the data blocks of the file fnam (ascpNNNN.4xx) and appends them to blocks. Each block
+// starts with a line with the number of the block and the number of coefficients ncoeff; the coefficients follow,
+// three per line. Blocks that end before tjdStart or start after tjdEnd are skipped, as well as blocks that start
+// before the end of the last block in blocks.
+func readJplAsciiBlocks(fnam string, ncoeff int, tjdStart, tjdEnd float64, blocks [][]float64) ([][]float64, error) {
+	f, err := os.Open(fnam)
+	if err != nil {
+		return blocks, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	lnr := 0
+	for sc.Scan() {
+		lnr++
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return blocks, fmt.Errorf("JPL data file %s, line %d: block header expected", fnam, lnr)
+		}
+		if n, err := strconv.Atoi(fields[1]); err != nil || n != ncoeff {
+			return blocks, fmt.Errorf("JPL data file %s, line %d: %s coefficients instead of %d", fnam, lnr, fields[1],
+				ncoeff)
+		}
+		blk := make([]float64, 0, ncoeff+2)
+		for len(blk) < ncoeff && sc.Scan() {
+			lnr++
+			for _, s := range strings.Fields(sc.Text()) {
+				v, err := parseJplFloat(s)
+				if err != nil {
+					return blocks, fmt.Errorf("JPL data file %s, line %d: %v", fnam, lnr, err)
+				}
+				blk = append(blk, v)
+			}
+		}
+		if len(blk) < ncoeff {
+			return blocks, fmt.Errorf("JPL data file %s: block at line %d is incomplete", fnam, lnr)
+		}
+		blk = blk[:ncoeff]
+		if tjdStart != 0 && blk[1] <= tjdStart || tjdEnd != 0 && blk[0] >= tjdEnd {
+			continue
+		}
+		if n := len(blocks); n > 0 {
+			// block that is already contained in the previous file
+			if blk[0] < blocks[n-1][1] {
+				continue
+			}
+			if blk[0] > blocks[n-1][1] {
+				return blocks, fmt.Errorf("JPL data file %s, line %d: gap between JD %.1f and JD %.1f", fnam, lnr,
+					blocks[n-1][1], blk[0])
+			}
+		}
+		blocks = append(blocks, blk)
+	}
+	return blocks, sc.Err()
+}
+
+// parseJplFloat parses a number of the JPL ASCII files, which have the exponent with D, e.g. 0.405D+03.
+func parseJplFloat(s string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(s, "D", "E", 1), 64)
+}
+
+// jplAsciiTitle returns the title of the binary file: three lines of 84 characters with the name of the ephemeris and
+// the start and end epoch ss[0] and ss[1].
+func jplAsciiTitle(title []string, ss [3]float64) [252]byte {
+	var ttl [252]byte
+	for i := range ttl {
+		ttl[i] = ' '
+	}
+	if len(title) > 0 {
+		copy(ttl[:84], title[0])
+	}
+	epoch := func(tjd float64) string {
+		gregflag := SE_GREG_CAL
+		if tjd < 2299160.5 {
+			gregflag = SE_JUL_CAL
+		}
+		year, month, day, hour := SweRevJul(tjd, gregflag)
+		months := []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+		hour = math.Round(hour * 3600)
+		return fmt.Sprintf("JED=%11.1f%5d %s %02d %02d:%02d:%02d", tjd, year, months[month-1], day, int(hour)/3600,
+			int(hour)/60%60, int(hour)%60)
+	}
+	copy(ttl[84:168], "Start Epoch: "+epoch(ss[0]))
+	copy(ttl[168:], "Final Epoch: "+epoch(ss[1]))
+	return ttl
+}
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// jplAsciiValue formats v as in the JPL ASCII files, with the exponent with D.
+func jplAsciiValue(v float64) string {
+	return strings.Replace(fmt.Sprintf("%26.18E", v), "E", "D", 1)
+}
+
+// writeJplAscii writes the synthetic JPL ephemeris of jplTestData as ASCII files: header.405 and one data file for
+// each element of blocks with the numbers of the data records it contains.
+func writeJplAscii(t *testing.T, dir string, blocks ...[]int) (string, []string) {
+	t.Helper()
+	ipt, recs := jplTestData()
+	d := jplAsciiValue
+	var b strings.Builder
+	// two coefficients of TT-TDB after the 13 bodies of the reader, as in DE440
+	ncoeff := len(recs[0]) + 2
+	fmt.Fprintf(&b, "KSIZE= %5d    NCOEFF= %5d\n\n", 2*ncoeff, ncoeff)
+	fmt.Fprintf(&b, "GROUP   1010\n\nJPL Planetary Ephemeris DE405/LE405 (synthetic)\n")
+	fmt.Fprintf(&b, "Start Epoch: JED=  2451536.5 1999 DEC 24 00:00:00\n")
+	fmt.Fprintf(&b, "Final Epoch: JED=  2451600.5 2000 FEB 26 00:00:00\n\n")
+	fmt.Fprintf(&b, "GROUP   1030\n\n%12.2f%12.2f%12.0f.\n\n", jplTestStart, jplTestStart+jplTestNseg*jplTestSeg,
+		jplTestSeg)
+	fmt.Fprintf(&b, "GROUP   1040\n\n     3\n  DENUM   AU      EMRAT\n\n")
+	fmt.Fprintf(&b, "GROUP   1041\n\n     3\n%s%s%s\n\n", d(405), d(jplTestAu), d(jplTestEmrat))
+	// pointers with a 14th column for TT-TDB
+	fmt.Fprintf(&b, "GROUP   1050\n\n")
+	ttTdb := []int{len(recs[0]) + 1, 2, 1}
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 13; i++ {
+			fmt.Fprintf(&b, "%6d", ipt[i*3+j])
+		}
+		fmt.Fprintf(&b, "%6d\n", ttTdb[j])
+	}
+	fmt.Fprintf(&b, "\nGROUP   1070\n\n")
+	header := filepath.Join(dir, "header.405")
+	if err := os.WriteFile(header, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var dataFiles []string
+	for k, nrs := range blocks {
+		b.Reset()
+		for _, nr := range nrs {
+			rec := append(recs[nr], 1e-3, 1e-5)
+			fmt.Fprintf(&b, "%6d%6d\n", nr+1, len(rec))
+			for i := 0; i < len(rec); i += 3 {
+				for j := i; j < i+3; j++ {
+					v := 0.0
+					if j < len(rec) {
+						v = rec[j]
+					}
+					b.WriteString(d(v))
+				}
+				b.WriteString("\n")
+			}
+		}
+		fnam := filepath.Join(dir, fmt.Sprintf("ascp%04d.405", k))
+		if err := os.WriteFile(fnam, []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		dataFiles = append(dataFiles, fnam)
+	}
+	return header, dataFiles
+}
+
+func TestConvertJplAscii(t *testing.T) {
+	dir := t.TempDir()
+	writeJplFile(t, filepath.Join(dir, "de405.eph"), binary.LittleEndian)
+	// the second block is contained in both data files
+	header, dataFiles := writeJplAscii(t, dir, []int{0, 1}, []int{1})
+	var ss [3]float64
+	var serr string
+	// positions of the binary file that is written directly
+	if retc := swiOpenJplFile(ss[:], "de405.eph", dir, &serr); retc != OK {
+		t.Fatalf("swiOpenJplFile: %s", serr)
+	}
+	tjds := []float64{2451536.5, 2451545.0, 2451570.25, 2451600.5}
+	want := make([][6]float64, len(tjds)*J_LIB)
+	for i, tjd := range tjds {
+		for ntarg := 0; ntarg < J_LIB; ntarg++ {
+			swiPleph(tjd, ntarg, J_SBARY, want[i*J_LIB+ntarg][:], &serr)
+		}
+	}
+	swiCloseJplFile()
+	tests := []struct {
+		name             string
+		tjdStart, tjdEnd float64
+		wantStart        float64
+		wantEpoch        string
+	}{
+		{"full", 0, 0, jplTestStart, "Start Epoch: JED=  2451536.5 1999 DEC 24 00:00:00"},
+		{"trimmed", jplTestStart + 40, 2451700.5, jplTestStart + jplTestSeg,
+			"Start Epoch: JED=  2451568.5 2000 JAN 25 00:00:00"},
+	}
+	for _, tt := range tests {
+		fnam := filepath.Join(dir, "de405"+tt.name+".eph")
+		tstart, tend, err := ConvertJplAscii(fnam, header, dataFiles, tt.tjdStart, tt.tjdEnd)
+		wantEnd := jplTestStart + jplTestNseg*jplTestSeg
+		if err != nil || tstart != tt.wantStart || tend != wantEnd {
+			t.Errorf("ConvertJplAscii %s: JD %f - %f, error %v; want JD %f - %f", tt.name, tstart, tend, err,
+				tt.wantStart, wantEnd)
+			continue
+		}
+		if data, _ := os.ReadFile(fnam); !strings.HasPrefix(string(data[84:]), tt.wantEpoch) {
+			t.Errorf("ConvertJplAscii %s: title %q; want %q", tt.name, data[:252], tt.wantEpoch)
+		}
+		if retc := swiOpenJplFile(ss[:], filepath.Base(fnam), dir, &serr); retc != OK {
+			t.Errorf("swiOpenJplFile %s: %s", tt.name, serr)
+			continue
+		}
+		names := string(js.chCnam[0][:]) + string(js.chCnam[1][:]) + string(js.chCnam[2][:])
+		if ss != [3]float64{tt.wantStart, wantEnd, jplTestSeg} || swiGetJplDenum() != 405 || js.ehNcon != 3 ||
+			names != "DENUM AU    EMRAT " {
+			t.Errorf("swiOpenJplFile %s: range %v, DE%d, %d constants %q", tt.name, ss, swiGetJplDenum(), js.ehNcon,
+				names)
+		}
+		for i, tjd := range tjds {
+			if tjd < tt.wantStart {
+				continue
+			}
+			for ntarg := 0; ntarg < J_LIB; ntarg++ {
+				var rrd [6]float64
+				if retc := swiPleph(tjd, ntarg, J_SBARY, rrd[:], &serr); retc != OK || rrd != want[i*J_LIB+ntarg] {
+					t.Errorf("swiPleph %s (%f, %d) = %v, %d; want %v", tt.name, tjd, ntarg, rrd, retc,
+						want[i*J_LIB+ntarg])
+				}
+			}
+		}
+		swiCloseJplFile()
+	}
+	// errors
+	fnam := filepath.Join(dir, "de405err.eph")
+	if _, _, err := ConvertJplAscii(fnam, header, dataFiles, 2451700.5, 2451800.5); err == nil ||
+		!strings.Contains(err.Error(), "no JPL data blocks") {
+		t.Errorf("ConvertJplAscii beyond the data: error %v", err)
+	}
+	// gap between the data files
+	dir = t.TempDir()
+	header, dataFiles = writeJplAscii(t, dir, []int{0}, []int{1})
+	data, _ := os.ReadFile(dataFiles[1])
+	data = []byte(strings.Replace(string(data), jplAsciiValue(jplTestStart+jplTestSeg),
+		jplAsciiValue(jplTestStart+jplTestSeg+4), 1))
+	if err := os.WriteFile(dataFiles[1], data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ConvertJplAscii(fnam, header, dataFiles, 0, 0); err == nil || !strings.Contains(err.Error(), "gap") {
+		t.Errorf("ConvertJplAscii with a gap: error %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package internal
+
+import "math"
+
+// Port: the functions in this file are not part of the C version. They expose the rotations that are applied
+// internally (frame bias, precession and nutation) as 3x3 matrices, for a chosen set of models. The models are passed
+// to the calculations, swed.AstroModels is not changed, so the functions can be called concurrently.
+
+// Matrix3 is a 3x3 rotation matrix, stored row by row. A vector v is rotated as m * v.
+type Matrix3 [3][3]float64
+
+// IdentityMatrix returns the 3x3 identity matrix.
+func IdentityMatrix() Matrix3 {
+	return Matrix3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// Mul returns the product m * n, i.e. the rotation n followed by the rotation m.
+func (m Matrix3) Mul(n Matrix3) Matrix3 {
+	var r Matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = m[i][0]*n[0][j] + m[i][1]*n[1][j] + m[i][2]*n[2][j]
+		}
+	}
+	return r
+}
+
+// Transpose returns the transposed matrix, for a rotation matrix this is the inverse rotation.
+func (m Matrix3) Transpose() Matrix3 {
+	var r Matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = m[j][i]
+		}
+	}
+	return r
+}
+
+// Apply rotates the cartesian vector v.
+func (m Matrix3) Apply(v [3]float64) [3]float64 {
+	var r [3]float64
+	for i := 0; i < 3; i++ {
+		r[i] = m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2]
+	}
+	return r
+}
+
+// ApplySpeed rotates a cartesian position and speed vector (x, y, z, dx, dy, dz). The change of the matrix itself
+// during a day is ignored, this makes a difference of about 0.01" per day for nutation and less for precession.
+func (m Matrix3) ApplySpeed(x [6]float64) [6]float64 {
+	var r [6]float64
+	for i := 0; i < 3; i++ {
+		r[i] = m[i][0]*x[0] + m[i][1]*x[1] + m[i][2]*x[2]
+		r[i+3] = m[i][0]*x[3] + m[i][1]*x[4] + m[i][2]*x[5]
+	}
+	return r
+}
+
+// ApplyPolar rotates a position in polar coordinates: longitude (or right ascension) and latitude (or declination)
+// in degrees, and distance. The result is returned in the same units.
+func (m Matrix3) ApplyPolar(lon, lat, dist float64) (float64, float64, float64) {
+	x := swiPolcart([]float64{lon * DEGTORAD, lat * DEGTORAD, dist})
+	r := m.Apply([3]float64{x[0], x[1], x[2]})
+	l := swiCartpol(r[:])
+	return l[0] * RADTODEG, l[1] * RADTODEG, l[2]
+}
+
+// astroModels returns the models, indexed by SE_MODEL_*, for precession model precModel, nutation model nutModel and
+// bias model biasModel. A model of 0 is the default model. The models are passed to the calculations, swed.AstroModels
+// is not changed.
+func astroModels(precModel, nutModel, biasModel int32) []int32 {
+	models := make([]int32, SEI_NMODELS)
+	models[SE_MODEL_PREC_LONGTERM] = precModel
+	models[SE_MODEL_PREC_SHORTTERM] = precModel
+	models[SE_MODEL_NUT] = nutModel
+	models[SE_MODEL_BIAS] = biasModel
+	return models
+}
+
+// PrecessionMatrix returns the precession matrix from the mean equator and equinox J2000 to the mean equator and
+// equinox of date for Julian day tjd (TT), using precession model precModel (SEMOD_PREC_*, 0 for the default).
+func PrecessionMatrix(tjd float64, precModel int32) Matrix3 {
+	var m Matrix3
+	models := astroModels(precModel, 0, 0)
+	for j := 0; j < 3; j++ {
+		x := make([]float64, 3)
+		x[j] = 1
+		precess(x, tjd, 0, J2000_TO_J, models)
+		for i := 0; i < 3; i++ {
+			m[i][j] = x[i]
+		}
+	}
+	return m
+}
+
+// NutationMatrix returns the nutation matrix from the mean equator and equinox of date to the true equator and
+// equinox of date for Julian day tjd (TT), using nutation model nutModel (SEMOD_NUT_*, 0 for the default). The mean
+// obliquity is calculated with precession model precModel (SEMOD_PREC_*, 0 for the default).
+func NutationMatrix(tjd float64, precModel, nutModel int32) Matrix3 {
+	var nu Nut
+	models := astroModels(precModel, nutModel, 0)
+	nu.Nutlo = make([]float64, 2)
+	nutation(tjd, 0, nu.Nutlo, models)
+	eps := epsiln(tjd, 0, models)
+	oe := Epsilon{Teps: tjd, Eps: eps, Seps: math.Sin(eps), Ceps: math.Cos(eps)}
+	nutMatrix(&nu, &oe)
+	// nutMatrix returns the matrix for multiplication of a row vector, see SwiNutate
+	return Matrix3(nu.Matrix).Transpose()
+}
+
+// BiasMatrix returns the frame bias matrix from ICRS to the mean equator and equinox J2000, using bias model
+// biasModel (SEMOD_BIAS_*, 0 for the default). For SEMOD_BIAS_NONE the identity matrix is returned.
+func BiasMatrix(biasModel int32) Matrix3 {
+	var m Matrix3
+	models := astroModels(0, 0, biasModel)
+	for j := 0; j < 3; j++ {
+		x := make([]float64, 6)
+		x[j] = 1
+		bias(x, J2000, 0, false, models)
+		for i := 0; i < 3; i++ {
+			m[i][j] = x[i]
+		}
+	}
+	return m
+}
+
+// NpbMatrix returns the combined matrix N * P * B that rotates ICRS coordinates to the true equator and equinox of
+// date for Julian day tjd (TT), using the given models for precession, nutation and frame bias (0 for the defaults).
+func NpbMatrix(tjd float64, precModel, nutModel, biasModel int32) Matrix3 {
+	b := BiasMatrix(biasModel)
+	p := PrecessionMatrix(tjd, precModel)
+	n := NutationMatrix(tjd, precModel, nutModel)
+	return n.Mul(p.Mul(b))
+}
+
+// ApplyPolarSp rotates a position and speed in polar coordinates: longitude and latitude in degrees, distance, and
+// their speeds per day. The result is returned in the same units.
+func (m Matrix3) ApplyPolarSp(xpo [6]float64) [6]float64 {
+	var xpn [6]float64
+	x := make([]float64, 6)
+	copy(x, xpo[:])
+	x[0] *= DEGTORAD
+	x[1] *= DEGTORAD
+	x[3] *= DEGTORAD
+	x[4] *= DEGTORAD
+	if x[2] == 0 {
+		x[2] = 1 // avoids problems with polcart(), if x[2] = 0
+	}
+	swiPolcartSp(x, x)
+	var xc [6]float64
+	copy(xc[:], x)
+	xc = m.ApplySpeed(xc)
+	swiCartpolSp(xc[:], xpn[:])
+	xpn[0] *= RADTODEG
+	xpn[1] *= RADTODEG
+	xpn[2] = xpo[2]
+	xpn[3] *= RADTODEG
+	xpn[4] *= RADTODEG
+	xpn[5] = xpo[5]
+	return xpn
+}
+
+// equToGal is the rotation matrix from equatorial coordinates ICRS (J2000) to galactic coordinates. The galactic
+// system is the IAU 1958 system (north galactic pole at B1950 12h49m, +27°24', see also SE_SIDM_GALEQU_IAU1958), as
+// realised for ICRS in the Hipparcos catalogue, ESA SP-1200 (1997), vol. 1, sect. 1.5.3.
+var equToGal = Matrix3{
+	{-0.0548755604162154, -0.8734370902348850, -0.4838350155487132},
+	{+0.4941094278755837, -0.4448296299600112, +0.7469822444972189},
+	{-0.8676661490190047, -0.1980763734312015, +0.4559837761750669},
+}
+
+// Supergalactic coordinates according to de Vaucouleurs et al., Second Reference Catalogue of Bright Galaxies
+// (1976): the north supergalactic pole and the zero point of supergalactic longitude in galactic coordinates.
+const (
+	SGAL_POLE_L = 47.37
+	SGAL_POLE_B = 6.32
+	SGAL_ZERO_L = 137.37
+)
+
+// galToSgal returns the rotation matrix from galactic to supergalactic coordinates.
+func galToSgal() Matrix3 {
+	x := swiPolcart([]float64{SGAL_ZERO_L * DEGTORAD, 0, 1})
+	z := swiPolcart([]float64{SGAL_POLE_L * DEGTORAD, SGAL_POLE_B * DEGTORAD, 1})
+	y := swiCrossProd(z, x)
+	return Matrix3{{x[0], x[1], x[2]}, {y[0], y[1], y[2]}, {z[0], z[1], z[2]}}
+}
+
+// EquToGalMatrix returns the rotation matrix from equatorial coordinates ICRS (J2000) to galactic coordinates.
+func EquToGalMatrix() Matrix3 {
+	return equToGal
+}
+
+// GalToSgalMatrix returns the rotation matrix from galactic to supergalactic coordinates.
+func GalToSgalMatrix() Matrix3 {
+	return galToSgal()
+}
+
+// SweEquToGal transforms equatorial polar coordinates ICRS (J2000) to galactic coordinates, or back if backward is
+// true. Positions and speeds as for SweCotransSp. For equatorial coordinates of date, precess to J2000 first, e.g.
+// with the transpose of PrecessionMatrix.
+func SweEquToGal(xpo [6]float64, backward bool) [6]float64 {
+	if backward {
+		return equToGal.Transpose().ApplyPolarSp(xpo)
+	}
+	return equToGal.ApplyPolarSp(xpo)
+}
+
+// SweGalToSgal transforms galactic polar coordinates to supergalactic coordinates, or back if backward is true.
+// Positions and speeds as for SweCotransSp.
+func SweGalToSgal(xpo [6]float64, backward bool) [6]float64 {
+	if backward {
+		return galToSgal().Transpose().ApplyPolarSp(xpo)
+	}
+	return galToSgal().ApplyPolarSp(xpo)
+}
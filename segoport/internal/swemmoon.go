@@ -1,6 +1,385 @@
 package internal
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
+
+// ===== 0284 ===== z swemmoon.c-0284 ================================================================================
+
+// moonZ contains coefficients that were calculated by a simultaneous least squares fit between the analytical theory
+// and DE404 on the finite interval from -3000 to +3000. The coefficients were estimated from 34,247 lunar positions.
+// Port: the coefficients of the fit to DE200 (MOSH_MOON_200) are not ported.
+var moonZ = [25]float64{
+	// The following are scaled in arc seconds, time in Julian centuries. They replace the corresponding terms in
+	// the mean elements.
+	-1.312045233711e+01, // F, t^2
+	-1.138215912580e-03, // F, t^3
+	-9.646018347184e-06, // F, t^4
+	3.146734198839e+01,  // l, t^2
+	4.768357585780e-02,  // l, t^3
+	-3.421689790404e-04, // l, t^4
+	-6.847070905410e+00, // D, t^2
+	-5.834100476561e-03, // D, t^3
+	-2.905334122698e-04, // D, t^4
+	-5.663161722088e+00, // L, t^2
+	5.722859298199e-03,  // L, t^3
+	-8.466472828815e-05, // L, t^4
+	// The following longitude terms are in arc seconds times 10^5.
+	-8.429817796435e+01, // t^2 cos(18V - 16E - l)
+	-2.072552484689e+02, // t^2 sin(18V - 16E - l)
+	7.876842214863e+00,  // t^2 cos(10V - 3E - l)
+	1.836463749022e+00,  // t^2 sin(10V - 3E - l)
+	-1.557471855361e+01, // t^2 cos(8V - 13E)
+	-2.006969124724e+01, // t^2 sin(8V - 13E)
+	2.152670284757e+01,  // t^2 cos(4E - 8M + 3J)
+	-6.179946916139e+00, // t^2 sin(4E - 8M + 3J)
+	-9.070028191196e-01, // t^2 cos(18V - 16E)
+	-1.270848233038e+01, // t^2 sin(18V - 16E)
+	-2.145589319058e+00, // t^2 cos(2J - 5S)
+	1.381936399935e+01,  // t^2 sin(2J - 5S)
+	-1.999840061168e+00, // t^3 sin(l')
+}
+
+// ===== 0318 ===== defines swemmoon.c-0318 ==========================================================================
+
+// number of lines of the perturbation tables
+const (
+	NLR   = 118
+	NMB   = 77
+	NLRT  = 38
+	NBT   = 16
+	NLRT2 = 25
+	NBT2  = 12
+)
+
+// ===== 0319 ===== LR swemmoon.c-0319 ===============================================================================
+
+// moonLR contains the perturbations in longitude and radius.
+var moonLR = [NLR * 8]int16{
+	// D, l', l, F; longitude in 1" and .0001"; radius in 1 km and .0001 km
+	0, 0, 1, 0, 22639, 5858, -20905, -3550,
+	2, 0, -1, 0, 4586, 4383, -3699, -1109,
+	2, 0, 0, 0, 2369, 9139, -2955, -9676,
+	0, 0, 2, 0, 769, 257, -569, -9251,
+	0, 1, 0, 0, -666, -4171, 48, 8883,
+	0, 0, 0, 2, -411, -5957, -3, -1483,
+	2, 0, -2, 0, 211, 6556, 246, 1585,
+	2, -1, -1, 0, 205, 4358, -152, -1377,
+	2, 0, 1, 0, 191, 9562, -170, -7331,
+	2, -1, 0, 0, 164, 7285, -204, -5860,
+	0, 1, -1, 0, -147, -3213, -129, -6201,
+	1, 0, 0, 0, -124, -9881, 108, 7427,
+	0, 1, 1, 0, -109, -3803, 104, 7552,
+	2, 0, 0, -2, 55, 1771, 10, 3211,
+	0, 0, 1, 2, -45, -996, 0, 0,
+	0, 0, 1, -2, 39, 5333, 79, 6606,
+	4, 0, -1, 0, 38, 4298, -34, -7825,
+	0, 0, 3, 0, 36, 1238, -23, -2104,
+	4, 0, -2, 0, 30, 7726, -21, -6363,
+	2, 1, -1, 0, -28, -3971, 24, 2085,
+	2, 1, 0, 0, -24, -3582, 30, 8238,
+	1, 0, -1, 0, -18, -5847, -8, -3791,
+	1, 1, 0, 0, 17, 9545, -16, -6747,
+	2, -1, 1, 0, 14, 5303, -12, -8314,
+	2, 0, 2, 0, 14, 3797, -10, -4448,
+	4, 0, 0, 0, 13, 8991, -11, -6500,
+	2, 0, -3, 0, 13, 1941, 14, 4027,
+	0, 1, -2, 0, -9, -6791, -7, -27,
+	2, 0, -1, 2, -9, -3659, 0, 7740,
+	2, -1, -2, 0, 8, 6055, 10, 562,
+	1, 0, 1, 0, -8, -4531, 6, 3220,
+	2, -2, 0, 0, 8, 502, -9, -8845,
+	0, 1, 2, 0, -7, -6302, 5, 7509,
+	0, 2, 0, 0, -7, -4475, 1, 657,
+	2, -2, -1, 0, 7, 3712, -4, -9501,
+	2, 0, 1, -2, -6, -3832, 4, 1311,
+	2, 0, 0, 2, -5, -7416, 0, 0,
+	4, -1, -1, 0, 4, 3740, -3, -9580,
+	0, 0, 2, 2, -3, -9976, 0, 0,
+	3, 0, -1, 0, -3, -2097, 3, 2582,
+	2, 1, 1, 0, -2, -9145, 2, 6164,
+	4, -1, -2, 0, 2, 7319, -1, -8970,
+	0, 2, -1, 0, -2, -5679, -2, -1171,
+	2, 2, -1, 0, -2, -5212, 2, 3536,
+	2, 1, -2, 0, 2, 4889, 0, 1437,
+	2, -1, 0, -2, 2, 1461, 0, 6571,
+	4, 0, 1, 0, 1, 9777, -1, -4226,
+	0, 0, 4, 0, 1, 9337, -1, -1169,
+	4, -1, 0, 0, 1, 8708, -1, -5714,
+	1, 0, -2, 0, -1, -7530, -1, -7385,
+	2, 1, 0, -2, -1, -4372, 0, -1357,
+	0, 0, 2, -2, -1, -3726, -4, -4212,
+	1, 1, 1, 0, 1, 2618, 0, -9333,
+	3, 0, -2, 0, -1, -2241, 0, 8624,
+	4, 0, -3, 0, 1, 1868, 0, -5142,
+	2, -1, 2, 0, 1, 1770, 0, -8488,
+	0, 2, 1, 0, -1, -1617, 1, 1655,
+	1, 1, -1, 0, 1, 777, 0, 8512,
+	2, 0, 3, 0, 1, 595, 0, -6697,
+	2, 0, 1, 2, 0, -9902, 0, 0,
+	2, 0, -4, 0, 0, 9483, 0, 7785,
+	2, -2, 1, 0, 0, 7517, 0, -6575,
+	0, 1, -3, 0, 0, -6694, 0, -4224,
+	4, 1, -1, 0, 0, -6352, 0, 5788,
+	1, 0, 2, 0, 0, -5840, 0, 3785,
+	1, 0, 0, -2, 0, -5833, 0, -7956,
+	6, 0, -2, 0, 0, 5716, 0, -4225,
+	2, 0, -2, -2, 0, -5606, 0, 4726,
+	1, -1, 0, 0, 0, -5569, 0, 4976,
+	0, 1, 3, 0, 0, -5459, 0, 3551,
+	2, 0, -2, 2, 0, -5357, 0, 7740,
+	2, 0, -1, -2, 0, 1790, 8, 7516,
+	3, 0, 0, 0, 0, 4042, -1, -4189,
+	2, -1, -3, 0, 0, 4784, 0, 4950,
+	2, -1, 3, 0, 0, 932, 0, -585,
+	2, 0, 2, -2, 0, -4538, 0, 2840,
+	2, -1, -1, 2, 0, -4262, 0, 373,
+	0, 0, 0, 4, 0, 4203, 0, 0,
+	0, 1, 0, 2, 0, 4134, 0, -1580,
+	6, 0, -1, 0, 0, 3945, 0, -2866,
+	2, -1, 0, 2, 0, -3821, 0, 0,
+	2, -1, 1, -2, 0, -3745, 0, 2094,
+	4, 1, -2, 0, 0, -3576, 0, 2370,
+	1, 1, -2, 0, 0, 3497, 0, 3323,
+	2, -3, 0, 0, 0, 3398, 0, -4107,
+	0, 0, 3, 2, 0, -3286, 0, 0,
+	4, -2, -1, 0, 0, -3087, 0, -2790,
+	0, 1, -1, -2, 0, 3015, 0, 0,
+	4, 0, -1, -2, 0, 3009, 0, -3218,
+	2, -2, -2, 0, 0, 2942, 0, 3430,
+	6, 0, -3, 0, 0, 2925, 0, -1832,
+	2, 1, 2, 0, 0, -2902, 0, 2125,
+	4, 1, 0, 0, 0, -2891, 0, 2445,
+	4, -1, 1, 0, 0, 2825, 0, -2029,
+	3, 1, -1, 0, 0, 2737, 0, -2126,
+	0, 1, 1, 2, 0, 2634, 0, 0,
+	1, 0, 0, 2, 0, 2543, 0, 0,
+	3, 0, 0, -2, 0, -2530, 0, 2010,
+	2, 2, -2, 0, 0, -2499, 0, -1089,
+	2, -3, -1, 0, 0, 2469, 0, -1481,
+	3, -1, -1, 0, 0, -2314, 0, 2556,
+	4, 0, 2, 0, 0, 2185, 0, -1392,
+	4, 0, -1, 2, 0, -2013, 0, 0,
+	0, 2, -2, 0, 0, -1931, 0, 0,
+	2, 2, 0, 0, 0, -1858, 0, 0,
+	2, 1, -3, 0, 0, 1762, 0, 0,
+	4, 0, -2, 2, 0, -1698, 0, 0,
+	4, -2, -2, 0, 0, 1578, 0, -1083,
+	4, -2, 0, 0, 0, 1522, 0, -1281,
+	3, 1, 0, 0, 0, 1499, 0, -1077,
+	1, -1, -1, 0, 0, -1364, 0, 1141,
+	1, -3, 0, 0, 0, -1281, 0, 0,
+	6, 0, 0, 0, 0, 1261, 0, -859,
+	2, 0, 2, 2, 0, -1239, 0, 0,
+	1, -1, 1, 0, 0, -1207, 0, 1100,
+	0, 0, 5, 0, 0, 1110, 0, -589,
+	0, 3, 0, 0, 0, -1013, 0, 213,
+	4, -1, -3, 0, 0, 998, 0, 0,
+}
+
+// ===== 0511 ===== MB swemmoon.c-0511 ===============================================================================
+
+// moonMB contains the perturbations in latitude.
+var moonMB = [NMB * 6]int16{
+	// D, l', l, F; latitude in 1" and .0001"
+	0, 0, 0, 1, 18461, 2387,
+	0, 0, 1, 1, 1010, 1671,
+	0, 0, 1, -1, 999, 6936,
+	2, 0, 0, -1, 623, 6524,
+	2, 0, -1, 1, 199, 4837,
+	2, 0, -1, -1, 166, 5741,
+	2, 0, 0, 1, 117, 2607,
+	0, 0, 2, 1, 61, 9120,
+	2, 0, 1, -1, 33, 3572,
+	0, 0, 2, -1, 31, 7597,
+	2, -1, 0, -1, 29, 5766,
+	2, 0, -2, -1, 15, 5663,
+	2, 0, 1, 1, 15, 1216,
+	2, 1, 0, -1, -12, -941,
+	2, -1, -1, 1, 8, 8681,
+	2, -1, 0, 1, 7, 9586,
+	2, -1, -1, -1, 7, 4346,
+	0, 1, -1, -1, -6, -7314,
+	4, 0, -1, -1, 6, 5796,
+	0, 1, 0, 1, -6, -4601,
+	0, 0, 0, 3, -6, -2965,
+	0, 1, -1, 1, -5, -6324,
+	1, 0, 0, 1, -5, -3684,
+	0, 1, 1, 1, -5, -3113,
+	0, 1, 1, -1, -5, -759,
+	0, 1, 0, -1, -4, -8396,
+	1, 0, 0, -1, -4, -8057,
+	0, 0, 3, 1, 3, 9841,
+	4, 0, 0, -1, 3, 6745,
+	4, 0, -1, 1, 2, 9985,
+	0, 0, 1, -3, 2, 7986,
+	4, 0, -2, 1, 2, 4139,
+	2, 0, 0, -3, 2, 1863,
+	2, 0, 2, -1, 2, 1462,
+	2, -1, 1, -1, 1, 7660,
+	2, 0, -2, 1, -1, -6244,
+	0, 0, 3, -1, 1, 5813,
+	2, 0, 2, 1, 1, 5198,
+	2, 0, -3, -1, 1, 5156,
+	2, 1, -1, 1, -1, -3178,
+	2, 1, 0, 1, -1, -2643,
+	4, 0, 0, 1, 1, 1919,
+	2, -1, 1, 1, 1, 1346,
+	2, -2, 0, -1, 1, 859,
+	0, 0, 1, 3, -1, -194,
+	2, 1, 1, -1, 0, -8227,
+	1, 1, 0, -1, 0, 8042,
+	1, 1, 0, 1, 0, 8026,
+	0, 1, -2, -1, 0, -7932,
+	2, 1, -1, -1, 0, -7910,
+	1, 0, 1, 1, 0, -6674,
+	2, -1, -2, -1, 0, 6502,
+	0, 1, 2, 1, 0, -6388,
+	4, 0, -2, -1, 0, 6337,
+	4, -1, -1, -1, 0, 5958,
+	1, 0, 1, -1, 0, -5889,
+	4, 0, 1, -1, 0, 4734,
+	1, 0, -1, -1, 0, -4299,
+	4, -1, 0, -1, 0, 4149,
+	2, -2, 0, 1, 0, 3835,
+	3, 0, 0, -1, 0, -3518,
+	4, -1, -1, 1, 0, 3388,
+	2, 0, -1, -3, 0, 3291,
+	2, -2, -1, 1, 0, 3147,
+	0, 1, 2, -1, 0, -3129,
+	3, 0, -1, -1, 0, -3052,
+	0, 1, -2, 1, 0, -3013,
+	2, 0, 1, -3, 0, -2912,
+	2, -2, -1, -1, 0, 2686,
+	0, 0, 4, 1, 0, 2633,
+	2, 0, -3, 1, 0, 2541,
+	2, 0, -1, 3, 0, -2448,
+	2, 1, 1, 1, 0, -2370,
+	4, -1, -2, 1, 0, 2138,
+	4, 0, 1, 1, 0, 2126,
+	3, 0, -1, 1, 0, -2059,
+	4, 1, -1, -1, 0, -1719,
+}
+
+// ===== 0597 ===== LRT swemmoon.c-0597 ==============================================================================
+
+// moonLRT contains the perturbations in longitude and radius that are multiplied by T.
+var moonLRT = [NLRT * 8]int16{
+	// D, l', l, F; longitude in .1" and .00001"; radius in .1 km and .00001 km
+	0, 1, 0, 0, 16, 7680, -1, -2302,
+	2, -1, -1, 0, -5, -1642, 3, 8245,
+	2, -1, 0, 0, -4, -1383, 5, 1395,
+	0, 1, -1, 0, 3, 7115, 3, 2654,
+	0, 1, 1, 0, 2, 7560, -2, -6396,
+	2, 1, -1, 0, 0, 7118, 0, -6068,
+	2, 1, 0, 0, 0, 6128, 0, -7754,
+	1, 1, 0, 0, 0, -4516, 0, 4194,
+	2, -2, 0, 0, 0, -4048, 0, 4970,
+	0, 2, 0, 0, 0, 3747, 0, -540,
+	2, -2, -1, 0, 0, -3707, 0, 2490,
+	2, -1, 1, 0, 0, -3649, 0, 3222,
+	0, 1, -2, 0, 0, 2438, 0, 1760,
+	2, -1, -2, 0, 0, -2165, 0, -2530,
+	0, 1, 2, 0, 0, 1923, 0, -1450,
+	0, 2, -1, 0, 0, 1292, 0, 1070,
+	2, 2, -1, 0, 0, 1271, 0, -6070,
+	4, -1, -1, 0, 0, -1098, 0, 990,
+	2, 0, 0, 0, 0, 1073, 0, -1360,
+	2, 0, -1, 0, 0, 839, 0, -630,
+	2, 1, 1, 0, 0, 734, 0, -660,
+	4, -1, -2, 0, 0, -688, 0, 480,
+	2, 1, -2, 0, 0, -630, 0, 0,
+	0, 2, 1, 0, 0, 587, 0, -590,
+	2, -1, 0, -2, 0, -540, 0, -170,
+	4, -1, 0, 0, 0, -468, 0, 390,
+	2, -2, 1, 0, 0, -378, 0, 330,
+	2, 1, 0, -2, 0, 364, 0, 0,
+	1, 1, 1, 0, 0, -317, 0, 240,
+	2, -1, 2, 0, 0, -295, 0, 210,
+	1, 1, -1, 0, 0, -270, 0, -210,
+	2, -3, 0, 0, 0, -256, 0, 310,
+	2, -3, -1, 0, 0, -187, 0, 110,
+	0, 1, -3, 0, 0, 169, 0, 110,
+	4, 1, -1, 0, 0, 158, 0, -150,
+	4, -2, -1, 0, 0, -155, 0, 140,
+	0, 0, 1, 0, 0, 155, 0, -250,
+	2, -2, -2, 0, 0, -148, 0, -170,
+}
+
+// ===== 0644 ===== BT swemmoon.c-0644 ===============================================================================
+
+// moonBT contains the perturbations in latitude that are multiplied by T.
+var moonBT = [NBT * 5]int16{
+	// D, l', l, F; latitude in .00001"
+	2, -1, 0, -1, -7430,
+	2, 1, 0, -1, 3043,
+	2, -1, -1, 1, -2229,
+	2, -1, 0, 1, -1999,
+	2, -1, -1, -1, -1869,
+	0, 1, -1, -1, 1696,
+	0, 1, 0, 1, 1623,
+	0, 1, -1, 1, 1418,
+	0, 1, 1, 1, 1339,
+	0, 1, 1, -1, 1278,
+	0, 1, 0, -1, 1217,
+	2, -2, 0, -1, -547,
+	2, -1, 1, -1, -443,
+	2, 1, -1, 1, 331,
+	2, 1, 0, 1, 317,
+	2, 0, 0, -1, 295,
+}
+
+// ===== 0669 ===== LRT2 swemmoon.c-0669 =============================================================================
+
+// moonLRT2 contains the perturbations in longitude and radius that are multiplied by T^2.
+var moonLRT2 = [NLRT2 * 6]int16{
+	// D, l', l, F; longitude in .00001"; radius in .00001 km
+	0, 1, 0, 0, 487, -36,
+	2, -1, -1, 0, -150, 111,
+	2, -1, 0, 0, -120, 149,
+	0, 1, -1, 0, 108, 95,
+	0, 1, 1, 0, 80, -77,
+	2, 1, -1, 0, 21, -18,
+	2, 1, 0, 0, 20, -23,
+	1, 1, 0, 0, -13, 12,
+	2, -2, 0, 0, -12, 14,
+	2, -1, 1, 0, -11, 9,
+	2, -2, -1, 0, -11, 7,
+	0, 2, 0, 0, 11, 0,
+	2, -1, -2, 0, -6, -7,
+	0, 1, -2, 0, 7, 5,
+	0, 1, 2, 0, 6, -4,
+	2, 2, -1, 0, 5, -3,
+	0, 2, -1, 0, 5, 3,
+	4, -1, -1, 0, -3, 3,
+	2, 0, 0, 0, 3, -4,
+	4, -1, -2, 0, -2, 0,
+	2, 1, -2, 0, -2, 0,
+	2, -1, 0, -2, -2, 0,
+	2, 1, 1, 0, 2, -2,
+	2, 0, -1, 0, 2, 0,
+	0, 2, 1, 0, 2, 0,
+}
+
+// ===== 0703 ===== BT2 swemmoon.c-0703 ==============================================================================
+
+// moonBT2 contains the perturbations in latitude that are multiplied by T^2.
+var moonBT2 = [NBT2 * 5]int16{
+	// D, l', l, F; latitude in .00001"
+	2, -1, 0, -1, -22,
+	2, 1, 0, -1, 9,
+	2, -1, 0, 1, -6,
+	2, -1, -1, 1, -6,
+	2, -1, -1, -1, -5,
+	0, 1, 0, 1, 5,
+	0, 1, -1, -1, 5,
+	0, 1, 1, 1, 4,
+	0, 1, 1, -1, 4,
+	0, 1, 0, -1, 4,
+	0, 1, -1, 1, 4,
+	2, -2, 0, -1, -2,
+}
 
 // ===== 0723 ===== constants mean_node_corr swemmoon.c-0723 ===================================================================
 
@@ -81,6 +460,392 @@ var meanApsisCorr = []float64{
 	10.986, 11.25, 11.52,
 }
 
+// ===== 0811 ===== static variables swemmoon.c-0811 =================================================================
+
+// moshMoon contains the variables of the lunar theory, which refer to the same instant.
+// Port: these are static variables in C.
+type moshMoon struct {
+	ss, cc  [5][8]float64 // sin and cos of multiple angles, see sscc()
+	l       float64       // Moon's ecliptic longitude
+	B       float64       // ecliptic latitude
+	moonpol [3]float64
+	SWELP   float64 // mean longitude of the moon
+	M       float64 // mean anomaly of the sun
+	MP      float64 // mean anomaly of the moon
+	D       float64 // mean elongation of the moon
+	NF      float64 // mean distance of the moon from its ascending node
+	T, T2   float64 // Julian centuries from J2000 and its square
+	f       float64 // 18V - 16E
+	Ve      float64 // mean longitudes of the planets
+	Ea      float64
+	Ma      float64
+	Ju      float64
+	Sa      float64
+	l1      float64 // longitude terms in T, T^2, T^3 and T^4
+	l2      float64
+	l3      float64
+	l4      float64
+}
+
+var mmoon moshMoon
+
+// ===== 0848 ===== swi_moshmoon2 swemmoon.c-0848 ====================================================================
+
+// swiMoshmoon2 calculates the geometric coordinates of the Moon without light time or nutation correction, for the
+// Julian ephemeris date J. The ecliptic polar coordinates of date are returned in pol: longitude and latitude in
+// radians and the radius in au.
+func swiMoshmoon2(J float64, pol []float64) int {
+	m := &mmoon
+	m.T = (J - J2000) / 36525.0
+	m.T2 = m.T * m.T
+	m.meanElements()
+	m.meanElementsPl()
+	m.moon1()
+	m.moon2()
+	m.moon3()
+	m.moon4()
+	copy(pol[:3], m.moonpol[:])
+	return 0
+}
+
+// ===== 0869 ===== swi_moshmoon swemmoon.c-0869 =====================================================================
+
+// swiMoshmoon computes the Moshier moon: position and speed in cartesian equatorial coordinates J2000.
+// tjd		julian day
+// doSave	write the position in the save area swed.Pldat[SEI_MOON]
+// xpmret	position and speed vectors of the moon, may be nil
+func swiMoshmoon(tjd float64, doSave bool, xpmret []float64, serr *string) int {
+	var xx, x1, x2 [6]float64
+	pdp := &swed.Pldat[SEI_MOON]
+	xpm := xx[:]
+	if doSave {
+		xpm = pdp.X[:]
+	}
+	// allow 0.2 day tolerance so that true node interval fits in
+	if tjd < MOSHLUEPH_START-0.2 || tjd > MOSHLUEPH_END+0.2 {
+		if serr != nil {
+			s := fmt.Sprintf("jd %f outside Moshier's Moon range %.2f .. %.2f ", tjd, MOSHLUEPH_START, MOSHLUEPH_END)
+			if len(*serr)+len(s) < AS_MAXCH {
+				*serr += s
+			}
+		}
+		return ERR
+	}
+	// if moon has already been computed
+	if tjd == pdp.Teval && pdp.Iephe == SEFLG_MOSEPH {
+		if xpmret != nil {
+			copy(xpmret[:6], pdp.X[:])
+		}
+		return OK
+	}
+	// else compute moon
+	swiMoshmoon2(tjd, xpm)
+	if doSave {
+		pdp.Teval = tjd
+		pdp.Xflgs = -1
+		pdp.Iephe = SEFLG_MOSEPH
+	}
+	// Moshier moon is referred to ecliptic of date. But we need equatorial positions for several reasons, e.g.
+	// computation of earth from emb and moon, of heliocentric moon. Besides, this helps to keep the program structure
+	// simpler
+	ecldatEqu2000(tjd, xpm)
+	// speed from 2 other positions. one would be good enough for computation of osculating node, but not for
+	// osculating apogee
+	t := tjd + MOON_SPEED_INTV
+	swiMoshmoon2(t, x1[:])
+	ecldatEqu2000(t, x1[:])
+	t = tjd - MOON_SPEED_INTV
+	swiMoshmoon2(t, x2[:])
+	ecldatEqu2000(t, x2[:])
+	for i := 0; i <= 2; i++ {
+		b := (x1[i] - x2[i]) / 2
+		a := (x1[i]+x2[i])/2 - xpm[i]
+		xpm[i+3] = (2*a + b) / MOON_SPEED_INTV
+	}
+	if xpmret != nil {
+		copy(xpmret[:6], xpm)
+	}
+	return OK
+}
+
+// ===== 1182 ===== moon1 swemmoon.c-1182 ============================================================================
+
+// moon1 computes the terms in T^2 and T and the perturbations by the planets.
+func (m *moshMoon) moon1() {
+	// This code added by Bhanu Pinnamaneni, 17-aug-2009
+	// Note by Dieter: Bhanu noted that ss and cc are not sufficiently initialised and random values are used for the
+	// calculation. However, this may be only part of the bug. The bug could be in sscc(). Or may be the bug is
+	// rather in the 116th line of NLR, where the value "5" may be wrong. Still, this will make a maximum difference
+	// of only 0.1", while the error of the Moshier lunar ephemeris can reach 7".
+	m.ss = [5][8]float64{}
+	m.cc = [5][8]float64{}
+	// End of code addition
+	m.sscc(0, STR*m.D, 6)
+	m.sscc(1, STR*m.M, 4)
+	m.sscc(2, STR*m.MP, 4)
+	m.sscc(3, STR*m.NF, 4)
+	m.moonpol = [3]float64{}
+	// terms in T^2, scale 1.0 = 10^-5"
+	m.chewm(moonLRT2[:], NLRT2, 4, 2, m.moonpol[:])
+	m.chewm(moonBT2[:], NBT2, 4, 4, m.moonpol[:])
+	m.f = 18*m.Ve - 16*m.Ea
+	g := STR * (m.f - m.MP) // 18V - 16E - l
+	cg := math.Cos(g)
+	sg := math.Sin(g)
+	m.l = 6.367278*cg + 12.747036*sg   // t^0
+	m.l1 = 23123.70*cg - 10570.02*sg   // t^1
+	m.l2 = moonZ[12]*cg + moonZ[13]*sg // t^2
+	m.moonpol[2] += 5.01*cg + 2.72*sg
+	g = STR * (10.*m.Ve - 3.*m.Ea - m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.253102*cg + 0.503359*sg
+	m.l1 += 1258.46*cg + 707.29*sg
+	m.l2 += moonZ[14]*cg + moonZ[15]*sg
+	g = STR * (8.*m.Ve - 13.*m.Ea)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.187231*cg - 0.127481*sg
+	m.l1 += -319.87*cg - 18.34*sg
+	m.l2 += moonZ[16]*cg + moonZ[17]*sg
+	a := 4.0*m.Ea - 8.0*m.Ma + 3.0*m.Ju
+	g = STR * a
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.866287*cg + 0.248192*sg
+	m.l1 += 41.87*cg + 1053.97*sg
+	m.l2 += moonZ[18]*cg + moonZ[19]*sg
+	g = STR * (a - m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.165009*cg + 0.044176*sg
+	m.l1 += 4.67*cg + 201.55*sg
+	g = STR * m.f // 18V - 16E
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.330401*cg + 0.661362*sg
+	m.l1 += 1202.67*cg - 555.59*sg
+	m.l2 += moonZ[20]*cg + moonZ[21]*sg
+	g = STR * (m.f - 2.0*m.MP) // 18V - 16E - 2l
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.352185*cg + 0.705041*sg
+	m.l1 += 1283.59*cg - 586.43*sg
+	g = STR * (2.0*m.Ju - 5.0*m.Sa)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.034700*cg + 0.160041*sg
+	m.l2 += moonZ[22]*cg + moonZ[23]*sg
+	g = STR * (m.SWELP - m.NF)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.000116*cg + 7.063040*sg
+	m.l1 += 298.8 * sg
+	// T^3 terms
+	sg = math.Sin(STR * m.M)
+	// l3 += z[24] * sg;			moshier! l3 not initialized!
+	m.l3 = moonZ[24] * sg
+	m.l4 = 0
+	g = STR * (2.0*m.D - m.M)
+	sg = math.Sin(g)
+	cg = math.Cos(g)
+	m.moonpol[2] += -0.2655 * cg * m.T
+	g = STR * (m.M - m.MP)
+	m.moonpol[2] += -0.1568 * math.Cos(g) * m.T
+	g = STR * (m.M + m.MP)
+	m.moonpol[2] += 0.1309 * math.Cos(g) * m.T
+	g = STR * (2.0*(m.D+m.M) - m.MP)
+	sg = math.Sin(g)
+	cg = math.Cos(g)
+	m.moonpol[2] += 0.5568 * cg * m.T
+	m.l2 += m.moonpol[0]
+	g = STR * (2.0*m.D - m.M - m.MP)
+	m.moonpol[2] += -0.1910 * math.Cos(g) * m.T
+	m.moonpol[1] *= m.T
+	m.moonpol[2] *= m.T
+	// terms in T
+	m.moonpol[0] = 0.0
+	m.chewm(moonBT[:], NBT, 4, 4, m.moonpol[:])
+	m.chewm(moonLRT[:], NLRT, 4, 1, m.moonpol[:])
+	g = STR * (m.f - m.MP - m.NF - 2355767.6) // 18V - 16E - l - F
+	m.moonpol[1] += -1127. * math.Sin(g)
+	g = STR * (m.f - m.MP + m.NF - 235353.6) // 18V - 16E - l + F
+	m.moonpol[1] += -1123. * math.Sin(g)
+	g = STR * (m.Ea + m.D + 51987.6)
+	m.moonpol[1] += 1303. * math.Sin(g)
+	g = STR * m.SWELP
+	m.moonpol[1] += 342. * math.Sin(g)
+	g = STR * (2.*m.Ve - 3.*m.Ea)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.343550*cg - 0.000276*sg
+	m.l1 += 105.90*cg + 336.53*sg
+	g = STR * (m.f - 2.*m.D) // 18V - 16E - 2D
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.074668*cg + 0.149501*sg
+	m.l1 += 271.77*cg - 124.20*sg
+	g = STR * (m.f - 2.*m.D - m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.073444*cg + 0.147094*sg
+	m.l1 += 265.24*cg - 121.16*sg
+	g = STR * (m.f + 2.*m.D - m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.072844*cg + 0.145829*sg
+	m.l1 += 265.18*cg - 121.29*sg
+	g = STR * (m.f + 2.*(m.D-m.MP))
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.070201*cg + 0.140542*sg
+	m.l1 += 255.36*cg - 116.79*sg
+	g = STR * (m.Ea + m.D - m.NF)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.288209*cg - 0.025901*sg
+	m.l1 += -63.51*cg - 240.14*sg
+	g = STR * (2.*m.Ea - 3.*m.Ju + 2.*m.D - m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += 0.077865*cg + 0.438460*sg
+	m.l1 += 210.57*cg + 124.84*sg
+	g = STR * (m.Ea - 2.*m.Ma)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.216579*cg + 0.241702*sg
+	m.l1 += 197.67*cg + 125.23*sg
+	g = STR * (a + m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.165009*cg + 0.044176*sg
+	m.l1 += 4.67*cg + 201.55*sg
+	g = STR * (a + 2.*m.D - m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.133533*cg + 0.041116*sg
+	m.l1 += 6.95*cg + 187.07*sg
+	g = STR * (a - 2.*m.D + m.MP)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.133430*cg + 0.041079*sg
+	m.l1 += 6.28*cg + 169.08*sg
+	g = STR * (3.*m.Ve - 4.*m.Ea)
+	cg = math.Cos(g)
+	sg = math.Sin(g)
+	m.l += -0.175074*cg + 0.003035*sg
+	m.l1 += 49.17*cg + 150.57*sg
+	g = STR * (2.*(m.Ea+m.D-m.MP) - 3.*m.Ju + 213534.)
+	m.l1 += 158.4 * math.Sin(g)
+	m.l1 += m.moonpol[0]
+	a = 0.1 * m.T // set amplitude scale of 1.0 = 10^-4 arcsec
+	m.moonpol[1] *= a
+	m.moonpol[2] *= a
+}
+
+// ===== 1367 ===== moon2 swemmoon.c-1367 ============================================================================
+
+// moon2 computes the terms in T^0 of the perturbations by the planets.
+func (m *moshMoon) moon2() {
+	// terms in T^0
+	g := STR * (2*(m.Ea-m.Ju+m.D) - m.MP + 648431.172)
+	m.l += 1.14307 * math.Sin(g)
+	g = STR * (m.Ve - m.Ea + 648035.568)
+	m.l += 0.82155 * math.Sin(g)
+	g = STR * (3*(m.Ve-m.Ea) + 2*m.D - m.MP + 647933.184)
+	m.l += 0.64371 * math.Sin(g)
+	g = STR * (m.Ea - m.Ju + 4424.04)
+	m.l += 0.63880 * math.Sin(g)
+	g = STR * (m.SWELP + m.MP - m.NF + 4.68)
+	m.l += 0.49331 * math.Sin(g)
+	g = STR * (m.SWELP - m.MP - m.NF + 4.68)
+	m.l += 0.4914 * math.Sin(g)
+	g = STR * (m.SWELP + m.NF + 2.52)
+	m.l += 0.36061 * math.Sin(g)
+	g = STR * (2.*m.Ve - 2.*m.Ea + 736.2)
+	m.l += 0.30154 * math.Sin(g)
+	g = STR * (2.*m.Ea - 3.*m.Ju + 2.*m.D - 2.*m.MP + 36138.2)
+	m.l += 0.28282 * math.Sin(g)
+	g = STR * (2.*m.Ea - 2.*m.Ju + 2.*m.D - 2.*m.MP + 311.0)
+	m.l += 0.24516 * math.Sin(g)
+	g = STR * (m.Ea - m.Ju - 2.*m.D + m.MP + 6275.88)
+	m.l += 0.21117 * math.Sin(g)
+	g = STR * (2.*(m.Ea-m.Ma) - 846.36)
+	m.l += 0.19444 * math.Sin(g)
+	g = STR * (2.*(m.Ea-m.Ju) + 1569.96)
+	m.l -= 0.18457 * math.Sin(g)
+	g = STR * (2.*(m.Ea-m.Ju) - m.MP - 55.8)
+	m.l += 0.18256 * math.Sin(g)
+	g = STR * (m.Ea - m.Ju - 2.*m.D + 6490.08)
+	m.l += 0.16499 * math.Sin(g)
+	g = STR * (m.Ea - 2.*m.Ju - 212378.4)
+	m.l += 0.16427 * math.Sin(g)
+	g = STR * (2.*(m.Ve-m.Ea-m.D) + m.MP + 1122.48)
+	m.l += 0.16088 * math.Sin(g)
+	g = STR * (m.Ve - m.Ea - m.MP + 32.04)
+	m.l -= 0.15350 * math.Sin(g)
+	g = STR * (m.Ea - m.Ju - m.MP + 4488.88)
+	m.l += 0.14346 * math.Sin(g)
+	g = STR * (2.*(m.Ve-m.Ea+m.D) - m.MP - 8.64)
+	m.l += 0.13594 * math.Sin(g)
+	g = STR * (2.*(m.Ve-m.Ea-m.D) + 1319.76)
+	m.l += 0.13432 * math.Sin(g)
+	g = STR * (m.Ve - m.Ea - 2.*m.D + m.MP - 56.16)
+	m.l -= 0.13122 * math.Sin(g)
+	g = STR * (m.Ve - m.Ea + m.MP + 54.36)
+	m.l -= 0.12722 * math.Sin(g)
+	g = STR * (3.*(m.Ve-m.Ea) - m.MP + 433.8)
+	m.l += 0.12539 * math.Sin(g)
+	g = STR * (m.Ea - m.Ju + m.MP + 4002.12)
+	m.l += 0.10994 * math.Sin(g)
+	g = STR * (20.*m.Ve - 21.*m.Ea - 2.*m.D + m.MP - 317511.72)
+	m.l += 0.10652 * math.Sin(g)
+	g = STR * (26.*m.Ve - 29.*m.Ea - m.MP + 270002.52)
+	m.l += 0.10490 * math.Sin(g)
+	g = STR * (3.*m.Ve - 4.*m.Ea + m.D - m.MP - 322765.56)
+	m.l += 0.10386 * math.Sin(g)
+	g = STR * (m.SWELP + 648002.556)
+	m.B = 8.04508 * math.Sin(g)
+	g = STR * (m.Ea + m.D + 996048.252)
+	m.B += 1.51021 * math.Sin(g)
+	g = STR * (m.f - m.MP + m.NF + 95554.332)
+	m.B += 0.63037 * math.Sin(g)
+	g = STR * (m.f - m.MP - m.NF + 95553.792)
+	m.B += 0.63014 * math.Sin(g)
+	g = STR * (m.SWELP - m.MP + 2.9)
+	m.B += 0.45587 * math.Sin(g)
+	g = STR * (m.SWELP + m.MP + 2.5)
+	m.B += -0.41573 * math.Sin(g)
+	g = STR * (m.SWELP - 2.0*m.NF + 3.2)
+	m.B += 0.32623 * math.Sin(g)
+	g = STR * (m.SWELP - 2.0*m.D + 2.5)
+	m.B += 0.29855 * math.Sin(g)
+}
+
+// ===== 1444 ===== moon3 swemmoon.c-1444 ============================================================================
+
+// moon3 adds the main terms in T^0 and the polynomial in T of the longitude.
+func (m *moshMoon) moon3() {
+	// terms in T^0
+	m.moonpol[0] = 0.0
+	m.chewm(moonLR[:], NLR, 4, 1, m.moonpol[:])
+	m.chewm(moonMB[:], NMB, 4, 3, m.moonpol[:])
+	m.l += (((m.l4*m.T+m.l3)*m.T+m.l2)*m.T + m.l1) * m.T * 1.0e-5
+	m.moonpol[0] = m.SWELP + m.l + 1.0e-4*m.moonpol[0]
+	m.moonpol[1] = 1.0e-4*m.moonpol[1] + m.B
+	m.moonpol[2] = 1.0e-4*m.moonpol[2] + 385000.52899 // kilometers
+}
+
+// ===== 1458 ===== moon4 swemmoon.c-1458 ============================================================================
+
+// moon4 computes the final ecliptic polar coordinates.
+func (m *moshMoon) moon4() {
+	m.moonpol[2] /= AUNIT / 1000
+	m.moonpol[0] = STR * mods3600(m.moonpol[0])
+	m.moonpol[1] = STR * m.moonpol[1]
+	m.B = m.moonpol[1]
+}
+
 // ===== 1466 ===== constants for corr_mean_node == swemmoon.c-1466 ==================================================
 
 const (
@@ -114,6 +879,34 @@ func corrMeanNode(J float64) float64 {
 	return dcor
 }
 
+// ===== 1493 ===== swi_mean_node swemmoon.c-1493 ====================================================================
+
+// swiMeanNode computes the mean lunar node for the Julian day J, with the elements of swiMoshmoon2(), which are
+// fitted to the JPL ephemeris. pol receives the polar coordinates of the ecliptic of date.
+func swiMeanNode(J float64, pol []float64, serr *string) int {
+	m := &mmoon
+	m.T = (J - J2000) / 36525.0
+	m.T2 = m.T * m.T
+	if J < MOSHNDEPH_START || J > MOSHNDEPH_END {
+		if serr != nil {
+			s := fmt.Sprintf("jd %f outside mean node range %.2f .. %.2f ", J, MOSHNDEPH_START, MOSHNDEPH_END)
+			if len(*serr)+len(s) < AS_MAXCH {
+				*serr += s
+			}
+		}
+		return ERR
+	}
+	m.meanElements()
+	dcor := corrMeanNode(J) * 3600
+	// longitude
+	pol[0] = Mod2PI((m.SWELP - m.NF - dcor) * STR)
+	// latitude
+	pol[1] = 0.0
+	// distance
+	pol[2] = MOON_MEAN_DIST / AUNIT // or should it be derived from mean orbital ellipse?
+	return OK
+}
+
 // ===== 1536 ===== constants for corr_mean_apog swemmoon.c-1470 =====================================================
 const (
 	CORR_MAPOG_JD_T0GREG = -3063616.5 /* 1 jan -13100 greg. */
@@ -139,6 +932,149 @@ func corrMeanApog(j float64) float64 {
 	return dcor
 }
 
+// ===== 1564 ===== swi_mean_apog swemmoon.c-1564 ====================================================================
+
+// swiMeanApog computes the mean lunar apogee ('dark moon', 'lilith') for the Julian day J. pol receives the polar
+// coordinates of the ecliptic of date.
+func swiMeanApog(J float64, pol []float64, serr *string) int {
+	m := &mmoon
+	m.T = (J - J2000) / 36525.0
+	m.T2 = m.T * m.T
+	// with elements from swiMoshmoon2(), which are fitted to jpl-ephemeris
+	if J < MOSHNDEPH_START || J > MOSHNDEPH_END {
+		if serr != nil {
+			s := fmt.Sprintf("jd %f outside mean apogee range %.2f .. %.2f ", J, MOSHNDEPH_START, MOSHNDEPH_END)
+			if len(*serr)+len(s) < AS_MAXCH {
+				*serr += s
+			}
+		}
+		return ERR
+	}
+	m.meanElements()
+	pol[0] = Mod2PI((m.SWELP-m.MP)*STR + PI)
+	pol[1] = 0
+	pol[2] = MOON_MEAN_DIST * (1 + MOON_MEAN_ECC) / AUNIT // apogee
+	// Lilith or Dark Moon is either the empty focal point of the mean lunar ellipse or, for some people, its apogee
+	// ("aphelion"). This is 180 degrees from the perigee.
+	//
+	// Since the lunar orbit is not in the ecliptic, the apogee must be projected onto the ecliptic. Joelle de
+	// Gravelaine has in her book "Lilith der schwarze Mond" (Astrodata, 1990) an ephemeris which gives noon (12.00)
+	// positions but does not project them onto the ecliptic. This results in a mistake of several arc minutes.
+	//
+	// There is also another problem. The other focal point doesn't coincide with the geocenter but with the
+	// barycenter of the earth-moon-system. The difference is about 4700 km. If one took this into account, it would
+	// result in an oscillation of the Black Moon. If defined as the apogee, this oscillation would be about +/- 40
+	// arcmin. If defined as the second focus, the effect is very large: +/- 6 deg! We neglect this influence.
+	dcor := corrMeanApog(J) * DEGTORAD
+	pol[0] = Mod2PI(pol[0] - dcor)
+	// apogee is now projected onto ecliptic
+	node := (m.SWELP - m.NF) * STR
+	dcor = corrMeanNode(J) * DEGTORAD
+	node = Mod2PI(node - dcor)
+	pol[0] = Mod2PI(pol[0] - node)
+	copy(pol, swiPolcart(pol))
+	copy(pol, swiCoortrf(pol, -MOON_MEAN_INCL*DEGTORAD))
+	copy(pol, swiCartpol(pol))
+	pol[0] = Mod2PI(pol[0] + node)
+	return OK
+}
+
+// ===== 1628 ===== chewm swemmoon.c-1628 ============================================================================
+
+// chewm steps through the perturbation table pt with nlines lines of nangles multiple angle factors and adds the
+// terms to ans. typflg gives the layout of the amplitudes: 1 large longitude and radius, 2 longitude and radius,
+// 3 large latitude, 4 latitude.
+func (m *moshMoon) chewm(pt []int16, nlines, nangles, typflg int, ans []float64) {
+	p := 0
+	for i := 0; i < nlines; i++ {
+		k1 := false
+		sv := 0.0
+		cv := 0.0
+		for j := 0; j < nangles; j++ {
+			n := int(pt[p]) // multiple angle factor
+			p++
+			if n != 0 {
+				k := n
+				if n < 0 {
+					k = -k // make angle factor > 0
+				}
+				// sin, cos (k*angle) from lookup table
+				su := m.ss[j][k-1]
+				cu := m.cc[j][k-1]
+				if n < 0 {
+					su = -su // negative angle factor
+				}
+				if !k1 {
+					// Set sin, cos of first angle.
+					sv = su
+					cv = cu
+					k1 = true
+				} else {
+					// Combine angles by trigonometry.
+					ff := su*cv + cu*sv
+					cv = cu*cv - su*sv
+					sv = ff
+				}
+			}
+		}
+		// Accumulate
+		switch typflg {
+		case 1: // large longitude and radius
+			j, k := float64(pt[p]), float64(pt[p+1])
+			ans[0] += (10000.0*j + k) * sv
+			j, k = float64(pt[p+2]), float64(pt[p+3])
+			if k != 0 {
+				ans[2] += (10000.0*j + k) * cv
+			}
+			p += 4
+		case 2: // longitude and radius
+			ans[0] += float64(pt[p]) * sv
+			ans[2] += float64(pt[p+1]) * cv
+			p += 2
+		case 3: // large latitude
+			ans[1] += (10000.0*float64(pt[p]) + float64(pt[p+1])) * sv
+			p += 2
+		case 4: // latitude
+			ans[1] += float64(pt[p]) * sv
+			p++
+		}
+	}
+}
+
+// ===== 1696 ===== sscc swemmoon.c-1696 =============================================================================
+
+// sscc prepares the lookup table of sin and cos (i*arg) for the required multiple angles.
+func (m *moshMoon) sscc(k int, arg float64, n int) {
+	su := math.Sin(arg)
+	cu := math.Cos(arg)
+	m.ss[k][0] = su // sin(L)
+	m.cc[k][0] = cu // cos(L)
+	sv := 2.0 * su * cu
+	cv := cu*cu - su*su
+	m.ss[k][1] = sv // sin(2L)
+	m.cc[k][1] = cv
+	for i := 2; i < n; i++ {
+		s := su*cv + cu*sv
+		cv = cu*cv - su*sv
+		sv = s
+		m.ss[k][i] = sv // sin( i+1 L )
+		m.cc[k][i] = cv
+	}
+}
+
+// ===== 1722 ===== ecldat_equ2000 swemmoon.c-1722 ===================================================================
+
+// ecldatEqu2000 converts xpm from polar coordinates of the ecliptic of date to cartesian coordinates of the equator
+// J2000.
+func ecldatEqu2000(tjd float64, xpm []float64) {
+	// cartesian
+	copy(xpm, swiPolcart(xpm))
+	// equatorial
+	swiCoortrf2(xpm, xpm, -swed.Oec.Seps, swed.Oec.Ceps)
+	// j2000
+	swiPrecess(xpm, tjd, 0, J_TO_J2000)
+}
+
 // ===== 1731 ===== mods3600 swemmoon.c-1731 =========================================================================
 
 // mods3600 reduces arc seconds modulo 360 degrees (1296000 arc seconds) and returns the result in arc seconds
@@ -146,3 +1082,155 @@ func mods3600(x float64) float64 {
 	// 1296000 arc seconds = 360 degrees
 	return x - 1296000.0*math.Floor(x/1296000.0)
 }
+
+// ===== 1742 ===== swi_mean_lunar_elements swemmoon.c-1742 ==========================================================
+
+// swiMeanLunarElements returns the mean lunar node and perigee for tjd, and their daily motions, in degrees.
+// Port: C returns the values in pointer arguments.
+func swiMeanLunarElements(tjd float64) (node, dnode, peri, dperi float64) {
+	m := &mmoon
+	m.T = (tjd - J2000) / 36525.0
+	m.T2 = m.T * m.T
+	m.meanElements()
+	node = SweDegnorm((m.SWELP - m.NF) * STR * RADTODEG)
+	peri = SweDegnorm((m.SWELP - m.MP) * STR * RADTODEG)
+	m.T -= 1.0 / 36525
+	m.meanElements()
+	dnode = SweDegnorm(node - (m.SWELP-m.NF)*STR*RADTODEG)
+	dnode -= 360
+	dperi = SweDegnorm(peri - (m.SWELP-m.MP)*STR*RADTODEG)
+	dcor := corrMeanNode(tjd)
+	node = SweDegnorm(node - dcor)
+	dcor = corrMeanApog(tjd)
+	peri = SweDegnorm(peri - dcor)
+	return node, dnode, peri, dperi
+}
+
+// ===== 1763 ===== mean_elements swemmoon.c-1763 ====================================================================
+
+// meanElements computes the mean elements of the moon and the mean anomaly of the sun for m.T.
+// Port: the mean elements of the fit to DE200 (MOSH_MOON_200) are not ported.
+func (m *moshMoon) meanElements() {
+	T, T2 := m.T, m.T2
+	fracT := math.Mod(T, 1)
+	// Mean anomaly of sun = l' (J. Laskar)
+	// M =  mods3600(129596581.038354 * T +  1287104.76154);
+	m.M = mods3600(129600000.0*fracT - 3418.961646*T + 1287104.76154)
+	m.M += ((((((((1.62e-20*T-1.0390e-17)*T-3.83508e-15)*T+4.237343e-13)*T+8.8555011e-11)*T-4.77258489e-8)*T-
+		1.1297037031e-5)*T+1.4732069041e-4)*T - 0.552891801772) * T2
+	// Mean distance of moon from its ascending node = F
+	// NF = mods3600((1739527263.0983 - 2.079419901760e-01) * T + 335779.55755);
+	m.NF = mods3600(1739232000.0*fracT + 295263.0983*T - 2.079419901760e-01*T + 335779.55755)
+	// Mean anomaly of moon = l
+	// MP = mods3600((1717915923.4728 - 2.035946368532e-01) * T +  485868.28096);
+	m.MP = mods3600(1717200000.0*fracT + 715923.4728*T - 2.035946368532e-01*T + 485868.28096)
+	// Mean elongation of moon = D
+	// D = mods3600((1602961601.4603 + 3.962893294503e-01) * T + 1072260.73512);
+	m.D = mods3600(1601856000.0*fracT + 1105601.4603*T + 3.962893294503e-01*T + 1072260.73512)
+	// Mean longitude of moon, referred to the mean ecliptic and equinox of date
+	// SWELP = mods3600((1732564372.83264 - 6.784914260953e-01) * T +  785939.95571);
+	m.SWELP = mods3600(1731456000.0*fracT + 1108372.83264*T - 6.784914260953e-01*T + 785939.95571)
+	// Higher degree secular terms found by least squares fit
+	m.NF += ((moonZ[2]*T+moonZ[1])*T + moonZ[0]) * T2
+	m.MP += ((moonZ[5]*T+moonZ[4])*T + moonZ[3]) * T2
+	m.D += ((moonZ[8]*T+moonZ[7])*T + moonZ[6]) * T2
+	m.SWELP += ((moonZ[11]*T+moonZ[10])*T + moonZ[9]) * T2
+	// sensitivity of mean elements
+	//    delta argument = scale factor times delta amplitude (arcsec)
+	// cos l  9.0019 = mean eccentricity
+	// cos 2D 43.6
+	// cos F  11.2 (latitude term)
+}
+
+// ===== 1820 ===== mean_elements_pl swemmoon.c-1820 =================================================================
+
+// meanElementsPl computes the mean longitudes of the planets (Laskar, Bretagnon) for m.T.
+func (m *moshMoon) meanElementsPl() {
+	T, T2 := m.T, m.T2
+	m.Ve = mods3600(210664136.4335482*T + 655127.283046)
+	m.Ve += ((((((((-9.36e-023*T-1.95e-20)*T+6.097e-18)*T+4.43201e-15)*T+2.509418e-13)*T-3.0622898e-10)*T-
+		2.26602516e-9)*T-1.4244812531e-5)*T + 0.005871373088) * T2
+	m.Ea = mods3600(129597742.26669231*T + 361679.214649)
+	m.Ea += ((((((((-1.16e-22*T+2.976e-19)*T+2.8460e-17)*T-1.08402e-14)*T-1.226182e-12)*T+1.7228268e-10)*T+
+		1.515912254e-7)*T+8.863982531e-6)*T - 2.0199859001e-2) * T2
+	m.Ma = mods3600(68905077.59284*T + 1279559.78866)
+	m.Ma += (-1.043e-5*T + 9.38012e-3) * T2
+	m.Ju = mods3600(10925660.428608*T + 123665.342120)
+	m.Ju += (1.543273e-5*T - 3.06037836351e-1) * T2
+	m.Sa = mods3600(4399609.65932*T + 180278.89694)
+	m.Sa += ((4.475946e-8*T-6.874806e-5)*T + 7.56161437443e-1) * T2
+}
+
+// ===== 1854 ===== swi_intp_apsides swemmoon.c-1854 =================================================================
+
+// swiIntpApsides calculates the geometric coordinates of the true interpolated lunar apogee or perigee (ipli
+// SEI_INTP_APOG or SEI_INTP_PERG): the extreme distance of the moon, found by varying its mean anomaly.
+// pol		ecliptic polar coordinates of date: longitude, latitude (radians) and distance (au)
+func swiIntpApsides(J float64, pol []float64, ipli int) int {
+	var rsv [3]float64
+	niter := 4
+	m := &mmoon
+	zMP := 27.55454988
+	fNF := 27.212220817 / zMP
+	fD := 29.530588835 / zMP
+	fLP := 27.321582 / zMP
+	fM := 365.2596359 / zMP
+	fVe := 224.7008001 / zMP
+	fEa := 365.2563629 / zMP
+	fMa := 686.9798519 / zMP
+	fJu := 4332.589348 / zMP
+	fSa := 10759.22722 / zMP
+	m.T = (J - J2000) / 36525.0
+	m.T2 = m.T * m.T
+	m.meanElements()
+	m.meanElementsPl()
+	sM, sVe, sEa, sMa, sJu, sSa := m.M, m.Ve, m.Ea, m.Ma, m.Ju, m.Sa
+	sNF := mods3600(m.NF)
+	sD := mods3600(m.D)
+	sLP := mods3600(m.SWELP)
+	sMP := mods3600(m.MP)
+	if ipli == SEI_INTP_PERG {
+		m.MP = 0.0
+		niter = 5
+	}
+	if ipli == SEI_INTP_APOG {
+		m.MP = 648000.0
+		niter = 4
+	}
+	dd := 18000.0
+	for iii := 0; iii <= niter; iii++ {
+		dMP := sMP - m.MP
+		mLP := sLP - dMP
+		mNF := sNF - dMP
+		mD := sD - dMP
+		mMP := sMP - dMP
+		for ii := 0; ii <= 2; ii++ {
+			fi := float64(ii - 1)
+			m.MP = mMP + fi*dd
+			m.NF = mNF + fi*dd/fNF
+			m.D = mD + fi*dd/fD
+			m.SWELP = mLP + fi*dd/fLP
+			m.M = sM + fi*dd/fM
+			m.Ve = sVe + fi*dd/fVe
+			m.Ea = sEa + fi*dd/fEa
+			m.Ma = sMa + fi*dd/fMa
+			m.Ju = sJu + fi*dd/fJu
+			m.Sa = sSa + fi*dd/fSa
+			m.moon1()
+			m.moon2()
+			m.moon3()
+			m.moon4()
+			if ii == 1 {
+				copy(pol[:3], m.moonpol[:])
+			}
+			rsv[ii] = m.moonpol[2]
+		}
+		cMP := (1.5*rsv[0] - 2*rsv[1] + 0.5*rsv[2]) / (rsv[0] + rsv[2] - 2*rsv[1])
+		cMP *= dd
+		cMP = cMP - dd
+		mMP += cMP
+		m.MP = mMP
+		dd /= 10
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSweCalcMoshierMoon(t *testing.T) {
+	// values from the C version of the Swiss Ephemeris
+	geo := int32(SEFLG_MOSEPH | SEFLG_SPEED)
+	equ := geo | SEFLG_EQUATORIAL | SEFLG_TRUEPOS | SEFLG_J2000
+	dflt := int32(SEFLG_SPEED | SEFLG_EQUATORIAL)
+	j2000 := geo | SEFLG_J2000
+	tests := []struct {
+		tjd   float64
+		ipl   int
+		iflag int32
+		want  [6]float64
+	}{
+		{625100.5, SE_MOON, geo, [6]float64{252.0872269538, -4.6167315047, 0.0025818634, 13.1146394261, -0.5219325637, -0.0000228507}},
+		{2451545.0, SE_MOON, geo, [6]float64{223.3148945943, 5.1709469544, 0.0026899635, 12.0212913666, -0.1778892600, 0.0000185826}},
+		{2817900.5, SE_MOON, geo, [6]float64{223.4137622919, 4.4318595070, 0.0024715359, 14.2093750398, 0.6486274861, -0.0000020098}},
+		{2451545.0, SE_MOON, equ, [6]float64{222.4473491724, -10.9001167902, 0.0026901911, 11.5973934694, -3.6984608913, 0.0000185537}},
+		{-3000000.5, SE_MEAN_NODE, geo, [6]float64{116.7888043438, 0.0000000000, 0.0025695553, -0.0529813147, 0.0000000000, 0.0000000000}},
+		{2451545.0, SE_MEAN_NODE, geo, [6]float64{125.0406851753, 0.0000000000, 0.0025695553, -0.0529518078, 0.0000000000, 0.0000000000}},
+		{7900000.5, SE_MEAN_NODE, geo, [6]float64{14.4237753670, 0.0000000000, 0.0025695553, -0.0529623726, 0.0000000000, 0.0000000000}},
+		{-3000000.5, SE_MEAN_NODE, dflt, [6]float64{118.9078205847, 21.1947401274, 0.0025695553, -0.0557216970, 0.0103902030, 0.0000000000}},
+		{2451545.0, SE_MEAN_NODE, dflt, [6]float64{127.3917123227, 19.0053663923, 0.0025695553, -0.0543476917, 0.0127853834, -0.0000000000}},
+		{7900000.5, SE_MEAN_NODE, dflt, [6]float64{13.3391064482, 5.5380507045, 0.0025695553, -0.0492846264, -0.0199664949, -0.0000000000}},
+		{-3000000.5, SE_MEAN_NODE, j2000, [6]float64{321.1956951517, -1.8994523753, 0.0025695553, -0.0530248481, -0.0006224174, 0.0000000000}},
+		{2451545.0, SE_MEAN_NODE, j2000, [6]float64{125.0445550444, 0.0000000000, 0.0025695553, -0.0529920197, 0.0000000000, 0.0000000000}},
+		{7900000.5, SE_MEAN_NODE, j2000, [6]float64{161.8656289549, 0.6217859323, 0.0025695553, -0.0529619665, -0.0013917890, 0.0000000000}},
+		{-3000000.5, SE_MEAN_APOG, geo, [6]float64{99.4245945369, -1.5393870687, 0.0027106251, 0.1108830549, 0.0140924543, 0.0000000000}},
+		{2451545.0, SE_MEAN_APOG, geo, [6]float64{263.4642504791, 3.4197231610, 0.0027106251, 0.1113276881, -0.0110209821, 0.0000000000}},
+		{7900000.5, SE_MEAN_APOG, geo, [6]float64{36.8439787312, 1.9669550010, 0.0027106251, 0.1108236756, 0.0136158575, 0.0000000000}},
+		{-3000000.5, SE_MEAN_APOG, dflt, [6]float64{100.1696387121, 22.0140244189, 0.0027106251, 0.1203409747, 0.0061271825, 0.0000000000}},
+		{2451545.0, SE_MEAN_APOG, dflt, [6]float64{263.0613902530, -19.8606813534, 0.0027106251, 0.1174562409, -0.0163575756, 0.0000000000}},
+		{7900000.5, SE_MEAN_APOG, dflt, [6]float64{33.9856775408, 15.2972065852, 0.0027106251, 0.1041952803, 0.0484955853, -0.0000000000}},
+		{-3000000.5, SE_MEAN_APOG, j2000, [6]float64{303.8236852581, -3.5532043735, 0.0027106251, 0.1110198444, 0.0142191916, 0.0000000000}},
+		{2451545.0, SE_MEAN_APOG, j2000, [6]float64{263.4681203482, 3.4197231610, 0.0027106251, 0.1112875150, -0.0110266517, 0.0000000000}},
+		{7900000.5, SE_MEAN_APOG, j2000, [6]float64{184.2426973313, 3.1158908062, 0.0027106251, 0.1106095247, 0.0158517297, 0.0000000000}},
+	}
+	defer SweSetEphePath("")
+	SweSetEphePath(t.TempDir())
+	for _, tt := range tests {
+		xx, iflgret, _, err := SweCalc(tt.tjd, tt.ipl, tt.iflag)
+		if err != nil || iflgret&SEFLG_EPHMASK != tt.iflag&SEFLG_EPHMASK {
+			t.Errorf("SweCalc(%.1f, %d, %d): flags %d, error %v", tt.tjd, tt.ipl, tt.iflag, iflgret, err)
+			continue
+		}
+		for i := range xx {
+			tol := 1e-9
+			if i >= 3 {
+				tol = 1e-7
+			}
+			if math.Abs(xx[i]-tt.want[i]) > tol {
+				t.Errorf("SweCalc(%.1f, %d, %d) = %.10f; want %.10f", tt.tjd, tt.ipl, tt.iflag, xx, tt.want)
+				break
+			}
+		}
+	}
+	// outside the range of the Moshier moon and of the mean node
+	for _, tt := range []struct {
+		tjd     float64
+		ipl     int
+		wantErr string
+	}{
+		{MOSHLUEPH_START - 1, SE_MOON, "outside Moshier's Moon range"},
+		{MOSHNDEPH_END + 1, SE_MEAN_NODE, "outside mean node range"},
+		{MOSHNDEPH_START - 1, SE_MEAN_APOG, "outside mean apogee range"},
+	} {
+		_, iflgret, _, err := SweCalc(tt.tjd, tt.ipl, geo)
+		if iflgret != ERR || err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("SweCalc(%.1f, %d): flags %d, error %v; want ERR and %q", tt.tjd, tt.ipl, iflgret, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSweCalcLunarApsides(t *testing.T) {
+	// values from the C version of the Swiss Ephemeris, without the files of the moon
+	geo := int32(SEFLG_MOSEPH | SEFLG_SPEED)
+	dflt := int32(SEFLG_SPEED | SEFLG_EQUATORIAL)
+	j2000 := geo | SEFLG_J2000
+	trpos := int32(SEFLG_MOSEPH | SEFLG_TRUEPOS)
+	tests := []struct {
+		tjd      float64
+		ipl      int
+		iflag    int32
+		wantFlag int32
+		want     [6]float64
+	}{
+		{625100.5, SE_TRUE_NODE, geo, 260, [6]float64{8.4716069310, 0.0000000000, 0.0024865222, -0.1174505598, 0.0000000000, -0.0000052013}},
+		{2451545.0, SE_TRUE_NODE, geo, 260, [6]float64{123.9533291111, 0.0000000000, 0.0024453715, -0.0543770030, 0.0000000000, 0.0000115892}},
+		{2460000.5, SE_TRUE_NODE, geo, 260, [6]float64{35.8559195057, 0.0000000000, 0.0025442048, 0.0043036885, 0.0000000000, 0.0000000551}},
+		{2817900.5, SE_TRUE_NODE, geo, 260, [6]float64{164.0605467508, 0.0000000000, 0.0025130033, -0.0395998201, 0.0000000000, 0.0000045701}},
+		{625100.5, SE_TRUE_NODE, dflt, 2308, [6]float64{7.7470930818, 3.4384339059, 0.0024865222, -0.1076638778, -0.0473795257, -0.0000052013}},
+		{2460000.5, SE_TRUE_NODE, dflt, 2308, [6]float64{33.5473826624, 13.4731328451, 0.0025442048, 0.0041752315, 0.0014267046, 0.0000000551}},
+		{2451545.0, SE_TRUE_NODE, j2000, 356, [6]float64{123.9571989803, 0.0000000000, 0.0024453715, -0.0544172165, 0.0000000000, 0.0000115892}},
+		{2460000.5, SE_TRUE_NODE, j2000, 356, [6]float64{35.5351011704, -0.0019711637, 0.0025442048, 0.0042795438, -0.0000001726, 0.0000000551}},
+		{2460000.5, SE_TRUE_NODE, trpos, 1556, [6]float64{35.8559195057, 0.0000000000, 0.0025442048, 0.0000000000, 0.0000000000, 0.0000000000}},
+		{625100.5, SE_OSCU_APOG, geo, 260, [6]float64{166.0704672089, 1.9674528350, 0.0026788700, 4.0775701599, -0.3512002518, -0.0000120337}},
+		{2451545.0, SE_OSCU_APOG, geo, 260, [6]float64{252.9781236583, 4.0755989708, 0.0027138412, 1.6465337478, -0.0970970195, 0.0000012131}},
+		{2460000.5, SE_OSCU_APOG, geo, 260, [6]float64{123.3377400888, 5.0868822234, 0.0027298234, -2.6145249179, -0.0023448352, -0.0000095581}},
+		{2817900.5, SE_OSCU_APOG, geo, 260, [6]float64{50.2756038752, -4.7126111932, 0.0026165420, 1.5553407503, -0.0556369063, 0.0000106892}},
+		{625100.5, SE_OSCU_APOG, dflt, 2308, [6]float64{168.0234636231, 7.4293948182, 0.0026788700, 3.6281426967, -1.9460191695, -0.0000120337}},
+		{2460000.5, SE_OSCU_APOG, dflt, 2308, [6]float64{126.9323126082, 24.3525986111, 0.0027298234, -2.7756737027, 0.6226066430, -0.0000095581}},
+		{2451545.0, SE_OSCU_APOG, j2000, 356, [6]float64{252.9819935274, 4.0755989708, 0.0027138412, 1.6464935345, -0.0970970196, 0.0000012131}},
+		{2460000.5, SE_OSCU_APOG, j2000, 356, [6]float64{123.0167554925, 5.0845074984, 0.0027298234, -2.6145393401, -0.0024301036, -0.0000095581}},
+		{2460000.5, SE_OSCU_APOG, trpos, 1556, [6]float64{123.3377400888, 5.0868822234, 0.0027298234, 0.0000000000, 0.0000000000, 0.0000000000}},
+		{625100.5, SE_INTP_APOG, geo, 260, [6]float64{163.7700137029, 2.1720715907, 0.0027038264, 0.1743153017, -0.0200062894, -0.0000001836}},
+		{2451545.0, SE_INTP_APOG, geo, 260, [6]float64{259.1639483662, 3.6850700637, 0.0027170897, -0.0159068776, 0.0031586264, -0.0000001082}},
+		{2460000.5, SE_INTP_APOG, geo, 260, [6]float64{120.5464444677, 5.0319003730, 0.0027145475, 0.1035105981, 0.0063032574, -0.0000001836}},
+		{2817900.5, SE_INTP_APOG, geo, 260, [6]float64{34.4867972047, -3.9760305816, 0.0027030808, 0.2320246678, -0.0208793420, -0.0000000515}},
+		{625100.5, SE_INTP_APOG, dflt, 2304, [6]float64{165.9747081478, 8.5297023672, 0.0027038264, 0.1537988188, -0.0872280218, -0.0000001836}},
+		{2460000.5, SE_INTP_APOG, dflt, 2304, [6]float64{123.9422015174, 24.9428216126, 0.0027145475, 0.1124057899, -0.0168444396, -0.0000001836}},
+		{2451545.0, SE_INTP_APOG, j2000, 356, [6]float64{259.1678182353, 3.6850700637, 0.0027170897, -0.0159470203, 0.0031530215, -0.0000001082}},
+		{2460000.5, SE_INTP_APOG, j2000, 356, [6]float64{120.2254720551, 5.0294374671, 0.0027145475, 0.1034862591, 0.0063139467, -0.0000001836}},
+		{2460000.5, SE_INTP_APOG, trpos, 1556, [6]float64{120.5464449174, 5.0319005324, 0.0027145474, 0.0000000000, 0.0000000000, 0.0000000000}},
+		{625100.5, SE_INTP_PERG, geo, 260, [6]float64{9.0777688950, 0.0460893613, 0.0024444193, 0.0974112747, 0.0127412385, 0.0000012192}},
+		{2451545.0, SE_INTP_PERG, geo, 260, [6]float64{91.8934090227, -2.7775454641, 0.0023880590, 0.5590719832, 0.0426294050, 0.0000005323}},
+		{2460000.5, SE_INTP_PERG, geo, 260, [6]float64{320.2992675879, -4.9027708784, 0.0023995497, 0.4984868371, 0.0085012450, 0.0000008960}},
+		{2817900.5, SE_INTP_PERG, geo, 260, [6]float64{231.6385870771, 4.7562380964, 0.0024707217, -1.0973104591, -0.0299052368, 0.0000009601}},
+		{625100.5, SE_INTP_PERG, dflt, 2304, [6]float64{8.2842855071, 3.7249781834, 0.0024444193, 0.0842012973, 0.0509053589, 0.0000012192}},
+		{2460000.5, SE_INTP_PERG, dflt, 2304, [6]float64{324.3448436942, -19.3638409993, 0.0023995497, 0.4950523067, 0.1691517338, 0.0000008960}},
+		{2451545.0, SE_INTP_PERG, j2000, 356, [6]float64{91.8972788918, -2.7775454641, 0.0023880590, 0.5590317622, 0.0426351085, 0.0000005323}},
+		{2460000.5, SE_INTP_PERG, j2000, 356, [6]float64{319.9782365874, -4.9010445848, 0.0023995497, 0.4984623518, 0.0084740846, 0.0000008960}},
+		{2460000.5, SE_INTP_PERG, trpos, 1556, [6]float64{320.2992715271, -4.9027710652, 0.0023995495, 0.0000000000, 0.0000000000, 0.0000000000}},
+	}
+	defer SweSetEphePath("")
+	SweSetEphePath(t.TempDir())
+	for _, tt := range tests {
+		xx, iflgret, _, err := SweCalc(tt.tjd, tt.ipl, tt.iflag)
+		// the true node and the osculating apogee fall back to the Moshier moon
+		fallback := tt.iflag&SEFLG_EPHMASK == 0 && tt.wantFlag&SEFLG_MOSEPH != 0
+		if iflgret != tt.wantFlag || (err != nil) != fallback ||
+			fallback && !strings.Contains(err.Error(), "using Moshier eph.") {
+			t.Errorf("SweCalc(%.1f, %d, %d): flags %d, error %v; want flags %d", tt.tjd, tt.ipl, tt.iflag, iflgret, err,
+				tt.wantFlag)
+			continue
+		}
+		for i := range xx {
+			// the osculating elements and the interpolated apsides magnify the differences in the last bit between
+			// the trigonometric functions of Go and C
+			if math.Abs(xx[i]-tt.want[i]) > 1e-6 {
+				t.Errorf("SweCalc(%.1f, %d, %d) = %.10f; want %.10f", tt.tjd, tt.ipl, tt.iflag, xx, tt.want)
+				break
+			}
+		}
+	}
+	// the interpolated apsides are restricted to the range of the Moshier moon
+	for _, ipl := range []int{SE_INTP_APOG, SE_INTP_PERG} {
+		_, iflgret, _, err := SweCalc(MOSHLUEPH_START-0.5, ipl, geo)
+		if iflgret != ERR || err == nil || !strings.Contains(err.Error(), "Interpolated apsides are restricted") {
+			t.Errorf("SweCalc(%.1f, %d): flags %d, error %v; want ERR", MOSHLUEPH_START-0.5, ipl, iflgret, err)
+		}
+	}
+}
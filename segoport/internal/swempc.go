@@ -0,0 +1,440 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Port: the functions in this file are not part of the C version. They read the osculating elements of comets and
+// asteroids from the one-line formats of the Minor Planet Center, CometEls.txt and MPCORB.DAT, and compute the
+// positions by two-body propagation. Light-time and the corrections of the apparent place are those of the fictitious
+// bodies, see appPosEtcPlanOsc.
+
+// MpcObject contains the osculating elements of a comet or asteroid from a file of the Minor Planet Center. The
+// elements are heliocentric and refer to the ecliptic and equinox J2000.
+type MpcObject struct {
+	Ipl   int     // body number SE_COMET_OFFSET + n, given by SweFindMpcObject; 0 for an object without a number
+	Name  string  // designation and name as given in the file, e.g. "C/1995 O1 (Hale-Bopp)" or "(1) Ceres"
+	Comet bool    // elements in the format of CometEls.txt, otherwise in the format of MPCORB.DAT
+	Epoch float64 // epoch of the elements, Julian day (TT); 0 if a comet has no epoch
+	Tperi float64 // time of perihelion passage, Julian day (TT)
+	Q     float64 // perihelion distance in AU
+	Ecce  float64 // eccentricity, 1 and greater for parabolic and hyperbolic orbits
+	Peri  float64 // argument of perihelion in degrees
+	Node  float64 // longitude of the ascending node in degrees
+	Incl  float64 // inclination in degrees
+	Fnam  string  // path and name of the file
+}
+
+// mpcMaxBodies is the number of body numbers from SE_COMET_OFFSET up to the planetary moons.
+const mpcMaxBodies = SE_PLMOON_OFFSET - SE_COMET_OFFSET
+
+// SweLoadMpcFile loads the comets and asteroids of the file fname, in the format of CometEls.txt or MPCORB.DAT of the
+// Minor Planet Center; a file may contain both formats. Without a directory, the file is searched in the ephemeris
+// path. The header of MPCORB.DAT, up to the line of dashes, and empty lines are skipped. The file is read line by
+// line, so that the whole MPCORB.DAT can be loaded. The objects get no body numbers, see SweFindMpcObject.
+// Returns the number of objects in the file, or an error for a line with invalid elements; then no object of the
+// file is loaded.
+func SweLoadMpcFile(fname string) (int, error) {
+	swiInitSwedIfStart()
+	if !swed.EphePathIsSet {
+		SweSetEphePath("")
+	}
+	var fp *os.File
+	var err error
+	if strings.Contains(fname, DIR_GLUE) {
+		fp, err = os.Open(fname)
+	} else {
+		fp, err = SwiFopen(-1, fname, swed.EphePath)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer fp.Close()
+	var objects []MpcObject
+	var lineErr error // first invalid line, unless it belongs to the header
+	dashes := false
+	scanner := bufio.NewScanner(fp)
+	for iline := 1; scanner.Scan(); iline++ {
+		line := scanner.Text()
+		if !dashes && strings.HasPrefix(line, "-----") {
+			// the header of MPCORB.DAT ends with a line of dashes
+			dashes = true
+			objects, lineErr = objects[:0], nil
+			continue
+		}
+		if lineErr != nil || strings.TrimSpace(line) == "" {
+			continue
+		}
+		obj, err := parseMpcLine(line)
+		if err != nil {
+			lineErr = fmt.Errorf("error in file %s, line %d: %v", fp.Name(), iline, err)
+			if dashes {
+				break
+			}
+			continue
+		}
+		obj.Fnam = fp.Name()
+		objects = append(objects, obj)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading file %s: %v", fp.Name(), err)
+	}
+	if lineErr != nil {
+		return 0, lineErr
+	}
+	if swed.MpcIndex == nil {
+		swed.MpcIndex = make(map[string]int)
+	}
+	for _, obj := range objects {
+		// an object of a later line or file replaces one with the same name in the index
+		for _, n := range mpcNames(obj.Name) {
+			swed.MpcIndex[strings.ToLower(n)] = len(swed.MpcObjects)
+		}
+		swed.MpcObjects = append(swed.MpcObjects, obj)
+	}
+	return len(objects), nil
+}
+
+// SweListMpcObjects returns the comets and asteroids of the files loaded with SweLoadMpcFile. Only the objects of
+// SweFindMpcObject have a body number.
+func SweListMpcObjects() []MpcObject {
+	return append([]MpcObject{}, swed.MpcObjects...)
+}
+
+// SweFindMpcObject returns the comet or asteroid with the given name, which is compared without regard to case with
+// the full name, the designation and the name proper, e.g. "C/1995 O1 (Hale-Bopp)", "C/1995 O1" or "Hale-Bopp",
+// "1P/Halley", "1P" or "Halley", and "(1) Ceres", "1" or "Ceres". If several objects match, the one that was loaded
+// last is returned, e.g. from a more recent file.
+// The object gets the next body number SE_COMET_OFFSET + n for SweCalc, if it has none yet; up to 8000 objects can
+// have a number. Returns an error if no object matches or all numbers are used.
+func SweFindMpcObject(name string) (MpcObject, error) {
+	i, ok := swed.MpcIndex[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return MpcObject{}, fmt.Errorf("comet or asteroid %s not found, see SweLoadMpcFile", name)
+	}
+	obj := &swed.MpcObjects[i]
+	if obj.Ipl == 0 {
+		if len(swed.MpcBodies) >= mpcMaxBodies {
+			return MpcObject{}, fmt.Errorf("no more than %d comets and asteroids can have a body number",
+				mpcMaxBodies)
+		}
+		obj.Ipl = SE_COMET_OFFSET + len(swed.MpcBodies)
+		swed.MpcBodies = append(swed.MpcBodies, i)
+	}
+	return *obj, nil
+}
+
+// mpcBody returns the object with the body number ipl, or nil if there is none.
+func mpcBody(ipl int) *MpcObject {
+	i := ipl - SE_COMET_OFFSET
+	if i < 0 || i >= len(swed.MpcBodies) {
+		return nil
+	}
+	return &swed.MpcObjects[swed.MpcBodies[i]]
+}
+
+// SweClearMpcObjects removes the objects of SweLoadMpcFile, their body numbers are given again by SweFindMpcObject.
+func SweClearMpcObjects() {
+	swed.MpcObjects = nil
+	swed.MpcIndex = nil
+	swed.MpcBodies = nil
+	// the save area may contain positions of the removed objects
+	swiForceAppPosEtc()
+}
+
+// mpcNames returns the full name, the designation and the name proper of an object.
+func mpcNames(name string) []string {
+	names := []string{name}
+	if strings.HasPrefix(name, "(") {
+		// numbered asteroid: "(1) Ceres"
+		if i := strings.Index(name, ")"); i > 0 {
+			names = append(names, name[1:i], strings.TrimSpace(name[i+1:]))
+		}
+	} else if i := strings.Index(name, " ("); i > 0 && strings.HasSuffix(name, ")") {
+		// comet with a provisional designation: "C/1995 O1 (Hale-Bopp)"
+		names = append(names, name[:i], name[i+2:len(name)-1])
+	} else if i := strings.Index(name, "/"); i > 0 && !strings.Contains(name[:i], " ") {
+		// periodic comet: "1P/Halley"
+		names = append(names, name[:i], name[i+1:])
+	}
+	return names
+}
+
+// parseMpcLine reads the elements of a line of CometEls.txt or MPCORB.DAT.
+func parseMpcLine(line string) (MpcObject, error) {
+	// the year of perihelion passage of CometEls.txt, where MPCORB.DAT has the slope parameter G
+	if len(line) >= 18 && isDigits(line[14:18]) {
+		return parseCometLine(line)
+	}
+	return parseMpcorbLine(line)
+}
+
+// parseCometLine reads a line of CometEls.txt: perihelion time in columns 15 - 29, perihelion distance 31 - 39,
+// eccentricity 42 - 49, argument of perihelion 52 - 59, node 62 - 69, inclination 72 - 79, epoch 82 - 89 (may be
+// empty) and designation and name 103 - 158.
+func parseCometLine(line string) (MpcObject, error) {
+	if len(line) < 103 {
+		return MpcObject{}, fmt.Errorf("comet elements incomplete")
+	}
+	obj := MpcObject{Comet: true, Name: strings.TrimSpace(column(line, 102, 158))}
+	year, err1 := strconv.Atoi(line[14:18])
+	month, err2 := strconv.Atoi(strings.TrimSpace(line[19:21]))
+	day, err3 := strconv.ParseFloat(strings.TrimSpace(line[22:29]), 64)
+	if err1 != nil || err2 != nil || err3 != nil || month < 1 || month > 12 || day < 1 || day >= 32 {
+		return MpcObject{}, fmt.Errorf("time of perihelion invalid")
+	}
+	obj.Tperi = SweJulday(year, month, 1, 0, SE_GREG_CAL) + day - 1
+	var err error
+	if obj.Q, err = parseMpcFloat(line, 30, 39); err != nil || obj.Q <= 0 {
+		return MpcObject{}, fmt.Errorf("perihelion distance invalid")
+	}
+	if obj.Ecce, err = parseMpcFloat(line, 41, 49); err != nil || obj.Ecce < 0 {
+		return MpcObject{}, fmt.Errorf("eccentricity invalid")
+	}
+	if err := parseMpcAngles(line, &obj, 51, 61, 71, 9); err != nil {
+		return MpcObject{}, err
+	}
+	if epoch := strings.TrimSpace(line[81:89]); epoch != "" {
+		y, err1 := strconv.Atoi(epoch[:min(4, len(epoch))])
+		md, err2 := strconv.Atoi(line[85:89])
+		if err1 != nil || err2 != nil || len(epoch) != 8 || md/100 < 1 || md/100 > 12 || md%100 < 1 || md%100 > 31 {
+			return MpcObject{}, fmt.Errorf("invalid epoch")
+		}
+		obj.Epoch = SweJulday(y, md/100, md%100, 0, SE_GREG_CAL)
+	}
+	if obj.Name == "" {
+		obj.Name = strings.TrimSpace(line[:12])
+	}
+	return obj, nil
+}
+
+// parseMpcorbLine reads a line of MPCORB.DAT: packed epoch in columns 21 - 25, mean anomaly 27 - 35, argument of
+// perihelion 38 - 46, node 49 - 57, inclination 60 - 68, eccentricity 71 - 79, semi-axis 93 - 103 and the readable
+// designation 167 - 194.
+func parseMpcorbLine(line string) (MpcObject, error) {
+	if len(line) < 103 {
+		return MpcObject{}, fmt.Errorf("elements incomplete")
+	}
+	obj := MpcObject{Name: strings.TrimSpace(column(line, 166, 194))}
+	if obj.Name == "" {
+		obj.Name = strings.TrimSpace(line[:7])
+	}
+	var ok bool
+	if obj.Epoch, ok = unpackMpcDate(line[20:25]); !ok {
+		return MpcObject{}, fmt.Errorf("invalid epoch")
+	}
+	mano, err := parseMpcFloat(line, 26, 35)
+	if err != nil {
+		return MpcObject{}, fmt.Errorf("mean anomaly value invalid")
+	}
+	if err := parseMpcAngles(line, &obj, 37, 48, 59, 9); err != nil {
+		return MpcObject{}, err
+	}
+	if obj.Ecce, err = parseMpcFloat(line, 70, 79); err != nil || obj.Ecce < 0 || obj.Ecce >= 1 {
+		return MpcObject{}, fmt.Errorf("eccentricity invalid")
+	}
+	sema, err := parseMpcFloat(line, 92, 103)
+	if err != nil || sema <= 0 {
+		return MpcObject{}, fmt.Errorf("semi-axis value invalid")
+	}
+	// perihelion passage before the epoch, with the daily motion of the two-body problem
+	obj.Q = sema * (1 - obj.Ecce)
+	obj.Tperi = obj.Epoch - mano*DEGTORAD/(KGAUSS/sema/math.Sqrt(sema))
+	return obj, nil
+}
+
+// parseMpcAngles reads the argument of perihelion, the node and the inclination in columns of width w that start at
+// the indices iperi, inode and iincl.
+func parseMpcAngles(line string, obj *MpcObject, iperi, inode, iincl, w int) error {
+	var err error
+	if obj.Peri, err = parseMpcFloat(line, iperi, iperi+w-1); err != nil {
+		return fmt.Errorf("perihelion argument value invalid")
+	}
+	if obj.Node, err = parseMpcFloat(line, inode, inode+w-1); err != nil {
+		return fmt.Errorf("node value invalid")
+	}
+	if obj.Incl, err = parseMpcFloat(line, iincl, iincl+w-1); err != nil || obj.Incl < 0 || obj.Incl > 180 {
+		return fmt.Errorf("inclination value invalid")
+	}
+	return nil
+}
+
+// parseMpcFloat reads the number in line[i:j].
+func parseMpcFloat(line string, i, j int) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(column(line, i, j)), 64)
+	if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		err = strconv.ErrRange
+	}
+	return f, err
+}
+
+// column returns line[i:j], shortened to the length of the line.
+func column(line string, i, j int) string {
+	if i >= len(line) {
+		return ""
+	}
+	return line[i:min(j, len(line))]
+}
+
+// isDigits reports whether s consists of digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// unpackMpcDate converts a packed date of the Minor Planet Center, e.g. K2555 for 2025 May 5, to a Julian day (TT).
+// The century is I, J or K for 1800, 1900 or 2000; month and day are 1 - 9, or A - C and A - V for 10 - 12 and
+// 10 - 31.
+func unpackMpcDate(s string) (float64, bool) {
+	digit := func(c byte) int {
+		switch {
+		case c >= '1' && c <= '9':
+			return int(c - '0')
+		case c >= 'A' && c <= 'V':
+			return int(c-'A') + 10
+		}
+		return 0
+	}
+	if len(s) != 5 || s[0] < 'I' || s[0] > 'K' || !isDigits(s[1:3]) {
+		return 0, false
+	}
+	year := 1800 + 100*int(s[0]-'I') + int(s[1]-'0')*10 + int(s[2]-'0')
+	month, day := digit(s[3]), digit(s[4])
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return 0, false
+	}
+	return SweJulday(year, month, day, 0, SE_GREG_CAL), true
+}
+
+// oscElPlan computes the barycentric position of the fictitious body (see swiOscElPlan) or MPC object (see mpcPlan)
+// ipl at tjd into xp. fictEl are the built-in elements of the Uranian planets.
+func oscElPlan(tjd float64, xp []float64, ipl, ipli int, xearth, xsun []float64, fictEl int32, serr *string) int {
+	if ipl >= SE_COMET_OFFSET {
+		return mpcPlan(tjd, xp, ipl, ipli, xsun, serr)
+	}
+	return swiOscElPlan(tjd, xp, ipl-SE_FICT_OFFSET, ipli, xearth, xsun, fictEl, serr)
+}
+
+// mpcPlan computes the barycentric position and speed of the MPC object ipl at tjd into xp, equator J2000, from the
+// two-body orbit around the sun xsun (heliocentric with Moshier). As with swiOscElPlan, the time and the ephemeris
+// of the earth are saved for the precession, if xp is the position of ipli in the save area.
+func mpcPlan(tjd float64, xp []float64, ipl, ipli int, xsun []float64, serr *string) int {
+	obj := mpcBody(ipl)
+	if obj == nil {
+		if serr != nil {
+			*serr = fmt.Sprintf("no elements for comet or asteroid %d, see SweFindMpcObject", ipl)
+		}
+		return ERR
+	}
+	pdp := &swed.Pldat[ipli]
+	pedp := &swed.Pldat[SEI_EARTH]
+	mpcHelio(obj, tjd, xp)
+	// to solar system barycentre
+	for j := 0; j <= 5; j++ {
+		xp[j] += xsun[j]
+	}
+	if &xp[0] == &pdp.X[0] {
+		pdp.Teval = tjd // for precession!
+		pdp.Iephe = pedp.Iephe
+	}
+	return OK
+}
+
+// mpcHelio computes the heliocentric position and speed of the two-body orbit of obj at tjd into xp, equator J2000.
+func mpcHelio(obj *MpcObject, tjd float64, xp []float64) {
+	var x [6]float64
+	mpcKepler(tjd-obj.Tperi, obj.Q, obj.Ecce, x[:])
+	// from the plane of the orbit to the ecliptic, as in swiOscElPlan
+	sinnode, cosnode := math.Sincos(obj.Node * DEGTORAD)
+	sinincl, cosincl := math.Sincos(obj.Incl * DEGTORAD)
+	sinparg, cosparg := math.Sincos(obj.Peri * DEGTORAD)
+	p := [3]float64{cosparg*cosnode - sinparg*cosincl*sinnode, cosparg*sinnode + sinparg*cosincl*cosnode,
+		sinparg * sinincl}
+	q := [3]float64{-sinparg*cosnode - cosparg*cosincl*sinnode, -sinparg*sinnode + cosparg*cosincl*cosnode,
+		cosparg * sinincl}
+	for j := 0; j <= 2; j++ {
+		xp[j] = p[j]*x[0] + q[j]*x[1]
+		xp[j+3] = p[j]*x[3] + q[j]*x[4]
+	}
+	// transformation to equator J2000
+	sineps, coseps := math.Sincos(-swiEpsiln(J2000, 0))
+	swiCoortrf2(xp, xp, sineps, coseps)
+	swiCoortrf2(xp[3:], xp[3:], sineps, coseps)
+}
+
+// mpcKepler computes the position and speed (AU, AU/day) in the plane of the orbit, x towards the perihelion, dt days
+// after the perihelion passage of an ellipse, parabola or hyperbola with perihelion distance q and eccentricity ecce.
+// The mass of the body is neglected. The Kepler equation is solved with universal variables (Danby, Fundamentals of
+// Celestial Mechanics, 6.9), which is accurate as well for the many comets with an eccentricity close to 1.
+func mpcKepler(dt, q, ecce float64, x []float64) {
+	mu := KGAUSS * KGAUSS
+	beta := mu * (1 - ecce) / q
+	if ecce < 1 {
+		// within half a period of the perihelion
+		dt = math.Remainder(dt, 2*math.Pi/math.Sqrt(beta*beta*beta)*mu)
+	}
+	// Kepler equation dt = q * G1(s) + mu * G3(s), solved with the method of Laguerre and Conway, which converges
+	// from any start
+	s := dt / q
+	var g0, g1, g2, g3 float64
+	for i := 0; i < 100; i++ {
+		g0, g1, g2, g3 = stumpffG(beta, s)
+		f := q*g1 + mu*g3 - dt
+		df := q*g0 + mu*g2
+		ddf := mu * ecce * g1
+		ds := -5 * f / (df + math.Copysign(math.Sqrt(math.Abs(16*df*df-20*f*ddf)), df))
+		s += ds
+		if math.Abs(ds) <= 1e-15*math.Max(1, math.Abs(s)) {
+			break
+		}
+	}
+	g0, g1, g2, g3 = stumpffG(beta, s)
+	r := q + mu*ecce*g2
+	// f and g functions, starting from the perihelion
+	vperi := math.Sqrt(mu * (1 + ecce) / q)
+	f, g := 1-mu*g2/q, q*g1
+	df, dg := -mu*g1/(r*q), 1-mu*g2/r
+	x[0], x[1], x[2] = f*q, g*vperi, 0
+	x[3], x[4], x[5] = df*q, dg*vperi, 0
+}
+
+// stumpffG returns the functions G0 .. G3 of the universal variable s, G_k = s^k * c_k(beta * s^2) with the Stumpff
+// functions c_k.
+func stumpffG(beta, s float64) (float64, float64, float64, float64) {
+	z := beta * s * s
+	var c0, c1, c2, c3 float64
+	switch {
+	case math.Abs(z) < 1:
+		// series, without cancellation
+		c2, c3 = 0.5, 1.0/6
+		t2, t3 := 0.5, 1.0/6
+		for n := 1; n < 30; n++ {
+			t2 *= -z / float64((2*n+1)*(2*n+2))
+			t3 *= -z / float64((2*n+2)*(2*n+3))
+			c2 += t2
+			c3 += t3
+			if math.Abs(t2) < 1e-17 && math.Abs(t3) < 1e-17 {
+				break
+			}
+		}
+		c0, c1 = 1-z*c2, 1-z*c3
+	case z > 0:
+		w := math.Sqrt(z)
+		c0, c1 = math.Cos(w), math.Sin(w)/w
+		c2, c3 = (1-c0)/z, (1-c1)/z
+	default:
+		w := math.Sqrt(-z)
+		c0, c1 = math.Cosh(w), math.Sinh(w)/w
+		c2, c3 = (1-c0)/z, (1-c1)/z
+	}
+	return c0, s * c1, s * s * c2, s * s * s * c3
+}
@@ -0,0 +1,264 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testCometEls contains lines in the format of CometEls.txt, with the elements of Halley and Hale-Bopp and of a
+// parabolic and a hyperbolic comet without an epoch.
+const testCometEls = `0001P         1986 02  9.4589  0.574961  0.967929  111.8657   59.0194  162.1951  19860205   4.0  6.0  1P/Halley
+    CJ95O010  1997 04  1.1375  0.914100  0.994927  130.5909  282.4707   89.4296  19970405   4.0  6.0  C/1995 O1 (Hale-Bopp)
+    CK99Z010  2000 01  1.5000  1.200000  1.000000   30.0000   40.0000   50.0000             4.0  6.0  C/2099 Z1 (Parabola)
+    CK99Z020  2000 01  1.5000  1.200000  1.050000   30.0000   40.0000   50.0000             4.0  6.0  C/2099 Z2 (Hyperbola)
+`
+
+// testMpcorb contains a header and a line in the format of MPCORB.DAT.
+const testMpcorb = `MINOR PLANET CENTER ORBIT DATABASE (MPCORB)
+
+Des'n     H     G   Epoch     M        Peri.      Node       Incl.       e            n           a        Reference
+----------------------------------------------------------------------------------------------------------------------
+
+00001    3.34  0.15 K0011  60.00000   73.00000   80.30000   10.60000  0.0785000  0.21413632   2.7670000  0 MPO000000  ` +
+	`7283 123 1801-2024 0.65 M-v 30h MPCLINUX   0000 (1) Ceres
+`
+
+func writeMpcFiles(t *testing.T) string {
+	dir := t.TempDir()
+	for fname, s := range map[string]string{"CometEls.txt": testCometEls, "MPCORB.DAT": testMpcorb} {
+		if err := os.WriteFile(filepath.Join(dir, fname), []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSweLoadMpcFile(t *testing.T) {
+	defer SweClearMpcObjects()
+	defer SweSetEphePath("")
+	dir := writeMpcFiles(t)
+	SweSetEphePath(dir)
+	if n, err := SweLoadMpcFile("CometEls.txt"); n != 4 || err != nil {
+		t.Fatalf("SweLoadMpcFile(CometEls.txt) = %d, %v; want 4", n, err)
+	}
+	if n, err := SweLoadMpcFile(filepath.Join(dir, "MPCORB.DAT")); n != 1 || err != nil {
+		t.Fatalf("SweLoadMpcFile(MPCORB.DAT) = %d, %v; want 1", n, err)
+	}
+	objects := SweListMpcObjects()
+	if len(objects) != 5 {
+		t.Fatalf("SweListMpcObjects() returns %d objects; want 5", len(objects))
+	}
+	halley := MpcObject{Name: "1P/Halley", Comet: true, Epoch: 2446466.5, Tperi: 2446470.9589,
+		Q: 0.574961, Ecce: 0.967929, Peri: 111.8657, Node: 59.0194, Incl: 162.1951,
+		Fnam: filepath.Join(dir, "CometEls.txt")}
+	if got := objects[0]; got != halley {
+		t.Errorf("Halley = %+v; want %+v", got, halley)
+	}
+	if got := objects[2]; got.Epoch != 0 || got.Ecce != 1 || got.Tperi != 2451545.0 {
+		t.Errorf("parabolic comet = %+v; want epoch 0, eccentricity 1 and perihelion 2451545.0", got)
+	}
+	ceres := objects[4]
+	if ceres.Ipl != 0 || ceres.Name != "(1) Ceres" || ceres.Comet || ceres.Epoch != 2451544.5 ||
+		math.Abs(ceres.Q-2.767*(1-0.0785)) > 1e-12 || ceres.Fnam != filepath.Join(dir, "MPCORB.DAT") {
+		t.Errorf("Ceres = %+v", ceres)
+	}
+	// mean anomaly 60 degrees at the epoch
+	if dt := ceres.Epoch - ceres.Tperi; math.Abs(dt-60/0.9856076686*2.767*math.Sqrt(2.767)) > 1e-6 {
+		t.Errorf("Ceres: perihelion %.6f days before the epoch", dt)
+	}
+	// the body numbers in the order of the first search
+	for _, tt := range []struct {
+		name string
+		ipl  int
+	}{{"1P/Halley", SE_COMET_OFFSET}, {"Ceres", SE_COMET_OFFSET + 1}, {"halley", SE_COMET_OFFSET},
+		{"1P", SE_COMET_OFFSET}, {"Hale-Bopp", SE_COMET_OFFSET + 2}, {"c/1995 o1", SE_COMET_OFFSET + 2},
+		{"C/1995 O1 (Hale-Bopp)", SE_COMET_OFFSET + 2}, {"1", SE_COMET_OFFSET + 1}, {"(1) Ceres", SE_COMET_OFFSET + 1}} {
+		if obj, err := SweFindMpcObject(tt.name); err != nil || obj.Ipl != tt.ipl {
+			t.Errorf("SweFindMpcObject(%q) = %d, %v; want %d", tt.name, obj.Ipl, err, tt.ipl)
+		}
+	}
+	if obj, err := SweFindMpcObject("Pallas"); err == nil {
+		t.Errorf("SweFindMpcObject(Pallas) = %+v; want not found", obj)
+	}
+	if got := SweListMpcObjects()[4].Ipl; got != SE_COMET_OFFSET+1 {
+		t.Errorf("SweListMpcObjects: Ceres has number %d; want %d", got, SE_COMET_OFFSET+1)
+	}
+	// a file with an error is not loaded
+	bad := strings.Replace(testCometEls, "0.994927", "-0.99492", 1)
+	if err := os.WriteFile(filepath.Join(dir, "bad.txt"), []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := SweLoadMpcFile("bad.txt"); err == nil || !strings.Contains(err.Error(), "line 2: eccentricity") {
+		t.Errorf("SweLoadMpcFile(bad.txt) = %d, %v; want an error in line 2", n, err)
+	}
+	if _, err := SweLoadMpcFile("missing.txt"); err == nil {
+		t.Errorf("SweLoadMpcFile(missing.txt): no error")
+	}
+	if n := len(SweListMpcObjects()); n != 5 {
+		t.Errorf("%d objects after errors; want 5", n)
+	}
+	// the numbers are used again after clearing
+	SweClearMpcObjects()
+	if _, iflgret, _, err := SweCalc(2451545.0, SE_COMET_OFFSET, SEFLG_MOSEPH); iflgret != ERR || err == nil {
+		t.Errorf("SweCalc of a cleared object: flags %d, error %v; want ERR", iflgret, err)
+	}
+	if n, err := SweLoadMpcFile("MPCORB.DAT"); n != 1 || err != nil {
+		t.Errorf("SweLoadMpcFile after SweClearMpcObjects = %d, %v; want 1", n, err)
+	}
+	if obj, err := SweFindMpcObject("Ceres"); err != nil || obj.Ipl != SE_COMET_OFFSET {
+		t.Errorf("SweFindMpcObject(Ceres) after SweClearMpcObjects = %d, %v; want %d", obj.Ipl, err, SE_COMET_OFFSET)
+	}
+}
+
+func TestSweLoadMpcFileLarge(t *testing.T) {
+	defer SweClearMpcObjects()
+	// more objects than body numbers, after the header of MPCORB.DAT
+	var b strings.Builder
+	header, ceres, _ := strings.Cut(testMpcorb, "00001")
+	b.WriteString(header)
+	n := mpcMaxBodies + 2000
+	for i := 1; i <= n; i++ {
+		line := fmt.Sprintf("%05d", i) + strings.Replace(ceres, "(1) Ceres", fmt.Sprintf("(%d) Test%d", i, i), 1)
+		b.WriteString(line)
+	}
+	fnam := filepath.Join(t.TempDir(), "MPCORB.DAT")
+	if err := os.WriteFile(fnam, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := SweLoadMpcFile(fnam); got != n || err != nil {
+		t.Fatalf("SweLoadMpcFile = %d, %v; want %d", got, err, n)
+	}
+	// the last object gets the first number
+	obj, err := SweFindMpcObject(fmt.Sprintf("Test%d", n))
+	if err != nil || obj.Ipl != SE_COMET_OFFSET || obj.Name != fmt.Sprintf("(%d) Test%d", n, n) {
+		t.Fatalf("SweFindMpcObject(Test%d) = %+v, %v; want number %d", n, obj, err, SE_COMET_OFFSET)
+	}
+	if _, iflgret, prov, err := SweCalc(2451545.0, obj.Ipl, SEFLG_MOSEPH); iflgret == ERR || prov.Fnam != fnam {
+		t.Errorf("SweCalc of object %d: flags %d, provenance %+v, error %v", n, iflgret, prov, err)
+	}
+	for i := 1; i < mpcMaxBodies; i++ {
+		if _, err := SweFindMpcObject(strconv.Itoa(i)); err != nil {
+			t.Fatalf("SweFindMpcObject(%d): %v", i, err)
+		}
+	}
+	if _, err := SweFindMpcObject(strconv.Itoa(mpcMaxBodies)); err == nil {
+		t.Errorf("SweFindMpcObject for more than %d objects: no error", mpcMaxBodies)
+	}
+}
+
+func TestSweCalcMpc(t *testing.T) {
+	defer SweClearMpcObjects()
+	defer SweClearElements()
+	defer SweSetEphePath("")
+	dir := writeMpcFiles(t)
+	SweSetEphePath(dir)
+	for _, fname := range []string{"CometEls.txt", "MPCORB.DAT"} {
+		if _, err := SweLoadMpcFile(fname); err != nil {
+			t.Fatal(err)
+		}
+	}
+	halleyMpc, err1 := SweFindMpcObject("Halley")
+	ceresMpc, err2 := SweFindMpcObject("Ceres")
+	if err1 != nil || err2 != nil {
+		t.Fatal(err1, err2)
+	}
+	// elliptic orbits as elements of SweRegisterElements, which are computed as in the C version
+	halley, _ := SweRegisterElements("Halley", 2446470.9589, J2000, 0, 0.574961/(1-0.967929), 0.967929, 111.8657,
+		59.0194, 162.1951)
+	ceres, _ := SweRegisterElements("Ceres", 2451544.5, J2000, 60, 2.767, 0.0785, 73, 80.3, 10.6)
+	geo := int32(SEFLG_MOSEPH | SEFLG_SPEED)
+	for _, iflag := range []int32{geo, geo | SEFLG_HELCTR, geo | SEFLG_EQUATORIAL | SEFLG_TRUEPOS | SEFLG_J2000,
+		geo | SEFLG_XYZ | SEFLG_NOABERR} {
+		for _, tjd := range []float64{2415020.5, 2446470.5, 2451545.0, 2460000.5} {
+			for _, obj := range []MpcObject{halleyMpc, ceresMpc} {
+				ipl, iplReg, fnam := obj.Ipl, halley, obj.Fnam
+				if obj == ceresMpc {
+					iplReg = ceres
+				}
+				xx, iflgret, prov, err := SweCalc(tjd, ipl, iflag)
+				want, _, _, _ := SweCalc(tjd, iplReg, iflag)
+				if iflgret&SEFLG_EPHMASK != SEFLG_MOSEPH || err != nil ||
+					prov != (Provenance{Iephe: SEFLG_MOSEPH, Denum: 403, Fnam: fnam}) {
+					t.Errorf("SweCalc(%.1f, %d, %d): flags %d, provenance %+v, error %v", tjd, ipl, iflag, iflgret, prov,
+						err)
+				}
+				for j := range xx {
+					if math.Abs(xx[j]-want[j]) > 1e-7 {
+						t.Errorf("SweCalc(%.1f, %d, %d) = %.10f; want %.10f", tjd, ipl, iflag, xx, want)
+						break
+					}
+				}
+			}
+		}
+	}
+	// the speeds of the parabolic and hyperbolic comets agree with the motion in a day
+	for _, name := range []string{"Parabola", "Hyperbola"} {
+		obj, err := SweFindMpcObject(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ipl := obj.Ipl
+		for _, tjd := range []float64{2451445.0, 2451545.0, 2451845.0} {
+			x0, _, _, err0 := SweCalc(tjd-0.5, ipl, geo)
+			x, _, _, err := SweCalc(tjd, ipl, geo)
+			x1, _, _, err1 := SweCalc(tjd+0.5, ipl, geo)
+			if err0 != nil || err != nil || err1 != nil {
+				t.Fatalf("SweCalc(%.1f, %d): %v %v %v", tjd, ipl, err0, err, err1)
+			}
+			for j := 0; j <= 2; j++ {
+				d := x1[j] - x0[j]
+				if j == 0 {
+					d = SweDegnorm(x1[j]-x0[j]+180) - 180
+				}
+				if math.Abs(d-x[j+3]) > 1e-3*math.Max(1, math.Abs(x[j+3])) {
+					t.Errorf("SweCalc(%.1f, %d): speed %.8f; want about %.8f", tjd, ipl, x[j+3], d)
+				}
+			}
+		}
+	}
+}
+
+func TestMpcKepler(t *testing.T) {
+	k2 := KGAUSS * KGAUSS
+	var x, x0, x1 [6]float64
+	for _, ecce := range []float64{0, 0.5, 0.99, 0.999999, 1, 1.000001, 1.05, 3} {
+		for _, dt := range []float64{-1000, -10, 0, 3, 500} {
+			q := 0.8
+			mpcKepler(dt, q, ecce, x[:])
+			r := math.Sqrt(x[0]*x[0] + x[1]*x[1])
+			v2 := x[3]*x[3] + x[4]*x[4]
+			// vis-viva equation and conservation of the angular momentum
+			if math.Abs(v2-k2*(2/r-(1-ecce)/q)) > 1e-12*v2 ||
+				math.Abs(x[0]*x[4]-x[1]*x[3]-math.Sqrt(k2*q*(1+ecce))) > 1e-12 {
+				t.Errorf("mpcKepler(%g, %g, %g) = %v: not on the orbit", dt, q, ecce, x)
+			}
+			// the speed is the derivative of the position
+			h := 1e-3
+			mpcKepler(dt-h, q, ecce, x0[:])
+			mpcKepler(dt+h, q, ecce, x1[:])
+			for j := 0; j <= 1; j++ {
+				if d := (x1[j] - x0[j]) / (2 * h); math.Abs(d-x[j+3]) > 1e-9 {
+					t.Errorf("mpcKepler(%g, %g, %g): speed %.12f; want %.12f", dt, q, ecce, x[j+3], d)
+				}
+			}
+		}
+	}
+	// the orbits are continuous at eccentricity 1
+	for _, dt := range []float64{-1000, 100} {
+		mpcKepler(dt, 1.2, 1, x[:])
+		for _, ecce := range []float64{1 - 1e-9, 1 + 1e-9} {
+			mpcKepler(dt, 1.2, ecce, x0[:])
+			for j := range x {
+				if math.Abs(x[j]-x0[j]) > 1e-6 {
+					t.Errorf("mpcKepler(%g, 1.2, %g) = %v; want about %v", dt, ecce, x0, x)
+					break
+				}
+			}
+		}
+	}
+}
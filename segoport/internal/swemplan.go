@@ -1,14 +1,464 @@
 package internal
 
 import (
-	"strconv"
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
 	"strings"
-	"unicode"
 )
 
+// ===== 0069 ===== defines swemplan.c-0069 ==========================================================================
+
+const (
+	TIMESCALE  = 3652500.0
+	FICT_GEO   = 1
+	KGAUSS_GEO = 0.0000298122353216 // Earth only
+	// KGAUSS_GEO 0.00002999502129737  Earth + Moon
+)
+
+// ===== 0084 ===== pnoint2msh swemplan.c-0084 =======================================================================
+
+// pnoint2msh converts internal planet numbers to the index of the planet in planets
+var pnoint2msh = []int{2, 2, 0, 1, 3, 4, 5, 6, 7, 8}
+
+// ===== 0087 ===== freqs swemplan.c-0087 ============================================================================
+
+// freqs from Simon et al (1994), arc sec per 10000 Julian years
+var freqs = []float64{
+	53810162868.8982,
+	21066413643.3548,
+	12959774228.3429,
+	6890507749.3988,
+	1092566037.7991,
+	439960985.5372,
+	154248119.3933,
+	78655032.0744,
+	52272245.1795,
+}
+
+// ===== 0102 ===== phases swemplan.c-0102 ===========================================================================
+
+// phases from Simon et al (1994), arc sec
+var phases = []float64{
+	252.25090552 * 3600.,
+	181.97980085 * 3600.,
+	100.46645683 * 3600.,
+	355.43299958 * 3600.,
+	34.35151874 * 3600.,
+	50.07744430 * 3600.,
+	314.05500511 * 3600.,
+	304.34866548 * 3600.,
+	860492.1546,
+}
+
+// ===== 0116 ===== planets swemplan.c-0116 ==========================================================================
+
+// planets contains the tables of swemptab.go, ordered as in pnoint2msh
+var planets = []*PlanTbl{
+	&mer404,
+	&ven404,
+	&ear404,
+	&mar404,
+	&jup404,
+	&sat404,
+	&ura404,
+	&nep404,
+	&plu404,
+}
+
+// ===== 0129 ===== ss cc swemplan.c-0129 ============================================================================
+
+// sin and cos of multiple angles, see sscc()
+// Port: renamed from ss and cc, because the lunar theory uses static arrays with the same names.
+var plSs, plCc [9][24]float64
+
+// ===== 0134 ===== swi_moshplan2 swemplan.c-0134 ====================================================================
+
+// swiMoshplan2 computes the heliocentric ecliptic polar coordinates of equinox J2000 of planet iplm (index in planets)
+// with the Moshier planetary theory and writes longitude and latitude (radians) and distance (AU) to pobj.
+func swiMoshplan2(J float64, iplm int, pobj []float64) int {
+	plan := planets[iplm]
+	T := (J - J2000) / TIMESCALE
+	// Calculate sin( i*MM ), etc. for needed multiple angles.
+	for i := 0; i < 9; i++ {
+		if j := int(plan.MaxHarmonic[i]); j > 0 {
+			sr := (mods3600(freqs[i]*T) + phases[i]) * STR
+			sscc(i, sr, j)
+		}
+	}
+	// start of table of arguments and of tabulated cosine and sine amplitudes
+	p, pl, pb, pr := plan.ArgTbl, plan.LonTbl, plan.LatTbl, plan.RadTbl
+	sl, sb, sr := 0.0, 0.0, 0.0
+	// next returns the first value of the table tbl and removes it from the table
+	next := func(tbl *[]float64) float64 {
+		v := (*tbl)[0]
+		*tbl = (*tbl)[1:]
+		return v
+	}
+	for {
+		// argument of sine and cosine
+		// Number of periodic arguments.
+		np := int(p[0])
+		p = p[1:]
+		if np < 0 {
+			break
+		}
+		if np == 0 { // It is a polynomial term.
+			nt := int(p[0])
+			p = p[1:]
+			// Longitude polynomial.
+			cu := next(&pl)
+			for ip := 0; ip < nt; ip++ {
+				cu = cu*T + next(&pl)
+			}
+			sl += mods3600(cu)
+			// Latitude polynomial.
+			cu = next(&pb)
+			for ip := 0; ip < nt; ip++ {
+				cu = cu*T + next(&pb)
+			}
+			sb += cu
+			// Radius polynomial.
+			cu = next(&pr)
+			for ip := 0; ip < nt; ip++ {
+				cu = cu*T + next(&pr)
+			}
+			sr += cu
+			continue
+		}
+		k1 := false
+		cv, sv := 0.0, 0.0
+		for ip := 0; ip < np; ip++ {
+			// What harmonic.
+			j := int(p[0])
+			// Which planet.
+			m := int(p[1]) - 1
+			p = p[2:]
+			if j != 0 {
+				k := j
+				if j < 0 {
+					k = -k
+				}
+				k--
+				su := plSs[m][k] // sin(k*angle)
+				if j < 0 {
+					su = -su
+				}
+				cu := plCc[m][k]
+				if !k1 { // set first angle
+					sv = su
+					cv = cu
+					k1 = true
+				} else { // combine angles
+					t := su*cv + cu*sv
+					cv = cu*cv - su*sv
+					sv = t
+				}
+			}
+		}
+		// Highest power of T.
+		nt := int(p[0])
+		p = p[1:]
+		// Longitude.
+		cu := next(&pl)
+		su := next(&pl)
+		for ip := 0; ip < nt; ip++ {
+			cu = cu*T + next(&pl)
+			su = su*T + next(&pl)
+		}
+		sl += cu*cv + su*sv
+		// Latitude.
+		cu = next(&pb)
+		su = next(&pb)
+		for ip := 0; ip < nt; ip++ {
+			cu = cu*T + next(&pb)
+			su = su*T + next(&pb)
+		}
+		sb += cu*cv + su*sv
+		// Radius.
+		cu = next(&pr)
+		su = next(&pr)
+		for ip := 0; ip < nt; ip++ {
+			cu = cu*T + next(&pr)
+			su = su*T + next(&pr)
+		}
+		sr += cu*cv + su*sv
+	}
+	pobj[0] = STR * sl
+	pobj[1] = STR * sb
+	pobj[2] = STR*plan.Distance*sr + plan.Distance
+	return OK
+}
+
+// ===== 0276 ===== swi_moshplan swemplan.c-0276 =====================================================================
+
+// swiMoshplan computes the heliocentric cartesian equatorial coordinates of equinox 2000 of the earth and a planet
+// with the Moshier ephemeris.
+// tjd		julian day
+// ipli		internal SWEPH planet number
+// doSave	write new positions in save area swed.Pldat
+// xpret	position and speed of the planet
+// xeret	of the earth
+// The return slices can be nil.
+func swiMoshplan(tjd float64, ipli int, doSave bool, xpret, xeret []float64, serr *string) int {
+	var xxe, xxp [6]float64
+	var dx, x2 [3]float64
+	iplm := pnoint2msh[ipli]
+	pdp := &swed.Pldat[ipli]
+	pedp := &swed.Pldat[SEI_EARTH]
+	seps2000 := swed.Oec2000.Seps
+	ceps2000 := swed.Oec2000.Ceps
+	xp, xe := xxp[:], xxe[:]
+	if doSave {
+		xp, xe = pdp.X[:], pedp.X[:]
+	}
+	doEarth := doSave || ipli == SEI_EARTH || xeret != nil
+	// tjd beyond ephemeris limits, give some margin for spped at edge
+	if tjd < MOSHPLEPH_START-0.3 || tjd > MOSHPLEPH_END+0.3 {
+		if serr != nil {
+			s := fmt.Sprintf("jd %f outside Moshier planet range %.2f .. %.2f ", tjd, MOSHPLEPH_START,
+				MOSHPLEPH_END)
+			if len(*serr)+len(s) < AS_MAXCH {
+				*serr += s
+			}
+		}
+		return ERR
+	}
+	// position in polar coordinates of the ecliptic J2000, converted to the equator J2000
+	moshplanEqu := func(tjd float64, iplm int, x []float64) {
+		swiMoshplan2(tjd, iplm, x)
+		copy(x, swiPolcart(x))
+		swiCoortrf2(x, x, -seps2000, ceps2000)
+	}
+	// earth, for geocentric position
+	if doEarth {
+		if tjd == pedp.Teval && pedp.Iephe == SEFLG_MOSEPH {
+			xe = pedp.X[:]
+		} else {
+			// emb
+			moshplanEqu(tjd, pnoint2msh[SEI_EMB], xe)
+			embofsMosh(tjd, xe) // emb -> earth
+			if doSave {
+				pedp.Teval = tjd
+				pedp.Xflgs = -1
+				pedp.Iephe = SEFLG_MOSEPH
+			}
+			// one more position for speed.
+			moshplanEqu(tjd-PLAN_SPEED_INTV, pnoint2msh[SEI_EMB], x2[:])
+			embofsMosh(tjd-PLAN_SPEED_INTV, x2[:])
+			for i := 0; i <= 2; i++ {
+				dx[i] = (xe[i] - x2[i]) / PLAN_SPEED_INTV
+			}
+			// store speed
+			for i := 0; i <= 2; i++ {
+				xe[i+3] = dx[i]
+			}
+		}
+		if xeret != nil {
+			copy(xeret[:6], xe)
+		}
+	}
+	// earth is the planet wanted
+	if ipli == SEI_EARTH {
+		return OK
+	}
+	// other planet
+	// if planet has already been computed, return
+	if tjd == pdp.Teval && pdp.Iephe == SEFLG_MOSEPH {
+		xp = pdp.X[:]
+	} else {
+		moshplanEqu(tjd, iplm, xp)
+		if doSave {
+			pdp.Teval = tjd
+			pdp.Xflgs = -1
+			pdp.Iephe = SEFLG_MOSEPH
+		}
+		// one more position for speed. the following dt gives good speed for light-time correction
+		dt := PLAN_SPEED_INTV
+		moshplanEqu(tjd-dt, iplm, x2[:])
+		for i := 0; i <= 2; i++ {
+			dx[i] = (xp[i] - x2[i]) / dt
+		}
+		// store speed
+		for i := 0; i <= 2; i++ {
+			xp[i+3] = dx[i]
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp)
+	}
+	return OK
+}
+
+// ===== 0387 ===== sscc swemplan.c-0387 =============================================================================
+
+// sscc prepares the lookup table of sin and cos ( i*Lj ) for required multiple angles
+func sscc(k int, arg float64, n int) {
+	su := math.Sin(arg)
+	cu := math.Cos(arg)
+	plSs[k][0] = su // sin(L)
+	plCc[k][0] = cu // cos(L)
+	sv := 2.0 * su * cu
+	cv := cu*cu - su*su
+	plSs[k][1] = sv // sin(2L)
+	plCc[k][1] = cv
+	for i := 2; i < n; i++ {
+		s := su*cv + cu*sv
+		cv = cu*cv - su*sv
+		sv = s
+		plSs[k][i] = sv // sin( i+1 L )
+		plCc[k][i] = cv
+	}
+}
+
+// ===== 0416 ===== embofs_mosh swemplan.c-0416 ======================================================================
+
+// embofsMosh adjusts the position from the Earth-Moon barycenter to the Earth.
+// tjd		julian day number
+// xemb		rectangular equatorial coordinates of the Earth-Moon barycenter, equinox J2000
+func embofsMosh(tjd float64, xemb []float64) {
+	var xyz [6]float64
+	seps := swed.Oec.Seps
+	ceps := swed.Oec.Ceps
+	// Short series for position of the Moon
+	T := (tjd - J1900) / 36525.0
+	// Mean anomaly of moon (MP)
+	a := SweDegnorm(((1.44e-5*T+0.009192)*T+477198.8491)*T + 296.104608)
+	a *= DEGTORAD
+	smp := math.Sin(a)
+	cmp := math.Cos(a)
+	s2mp := 2.0 * smp * cmp   // sin(2MP)
+	c2mp := cmp*cmp - smp*smp // cos(2MP)
+	// Mean elongation of moon (D)
+	a = SweDegnorm(((1.9e-6*T-0.001436)*T+445267.1142)*T + 350.737486)
+	a = 2.0 * DEGTORAD * a
+	s2d := math.Sin(a)
+	c2d := math.Cos(a)
+	// Mean distance of moon from its ascending node (F)
+	a = SweDegnorm(((-3.e-7*T-0.003211)*T+483202.0251)*T + 11.250889)
+	a *= DEGTORAD
+	sf := math.Sin(a)
+	cf := math.Cos(a)
+	s2f := 2.0 * sf * cf    // sin(2F)
+	sx := s2d*cmp - c2d*smp // sin(2D - MP)
+	cx := c2d*cmp + s2d*smp // cos(2D - MP)
+	// Mean longitude of moon (LP)
+	L := ((1.9e-6*T-0.001133)*T+481267.8831)*T + 270.434164
+	// Mean anomaly of sun (M)
+	M := SweDegnorm(((-3.3e-6*T-1.50e-4)*T+35999.0498)*T + 358.475833)
+	// Ecliptic longitude of the moon
+	L = L + 6.288750*smp + 1.274018*sx + 0.658309*s2d + 0.213616*s2mp - 0.185596*math.Sin(DEGTORAD*M) -
+		0.114336*s2f
+	// Ecliptic latitude of the moon
+	a = smp * cf
+	sx = cmp * sf
+	B := 5.128189*sf +
+		0.280606*(a+sx) + // sin(MP+F)
+		0.277693*(a-sx) + // sin(MP-F)
+		0.173238*(s2d*cf-c2d*sf) // sin(2D-F)
+	B *= DEGTORAD
+	// Parallax of the moon
+	p := 0.950724 + 0.051818*cmp + 0.009531*cx + 0.007843*c2d + 0.002824*c2mp
+	p *= DEGTORAD
+	// Elongation of Moon from Sun
+	L = SweDegnorm(L)
+	L *= DEGTORAD
+	// Distance in au
+	a = 4.263523e-5 / math.Sin(p)
+	// Convert to rectangular ecliptic coordinates
+	xyz[0] = L
+	xyz[1] = B
+	xyz[2] = a
+	copy(xyz[:3], swiPolcart(xyz[:]))
+	// Convert to equatorial
+	swiCoortrf2(xyz[:], xyz[:], -seps, ceps)
+	// Precess to equinox of J2000.0
+	swiPrecess(xyz[:], tjd, 0, J_TO_J2000)
+	// now emb -> earth
+	for i := 0; i <= 2; i++ {
+		xemb[i] -= xyz[i] / (EARTH_MOON_MRAT + 1.0)
+	}
+}
+
+// ===== 0506 ===== plan_fict_nam swemplan.c-0506 ====================================================================
+
+var planFictNam = [SE_NFICT_ELEM]string{"Cupido", "Hades", "Zeus", "Kronos", "Apollon", "Admetos", "Vulkanus",
+	"Poseidon", "Isis-Transpluto", "Nibiru", "Harrington", "Leverrier", "Adams", "Lowell", "Pickering"}
+
+// ===== 0513 ===== swi_get_fict_name swemplan.c-0513 ================================================================
+
+// swiGetFictName returns the name of fictitious body ipl (0 = Cupido), from seorbel.txt or the built-in bodies.
+// Port: the elements are checked as well, a line with invalid elements returns "name not found".
+func swiGetFictName(ipl int) string {
+	var el fictElements
+	// the names are the same in both sets of elements
+	if readElementsFile(ipl, 0, SE_FICTEL_NEELY, &el, nil) == ERR {
+		return "name not found"
+	}
+	return el.name
+}
+
+// SweGetFictName returns the name of the fictitious body ipl (SE_FICT_OFFSET + number of the body), from seorbel.txt
+// or from the built-in elements, or "name not found".
+// Port: not part of the C API, swe_get_planet_name() calls swi_get_fict_name() for these bodies.
+func SweGetFictName(ipl int) string {
+	if ipl < SE_FICT_OFFSET || ipl > SE_FICT_MAX {
+		return "name not found"
+	}
+	return swiGetFictName(ipl - SE_FICT_OFFSET)
+}
+
+// SweCheckFictElements returns an error if set is not one of the built-in elements of the Uranian planets Cupido ..
+// Poseidon, which are used if there is no seorbel.txt: SE_FICTEL_NEELY, the default as in C, or SE_FICTEL_CLASSIC.
+// Port: not part of C, where the elements are chosen at compile time with SE_NEELY. The elements are passed to
+// SweCalcFictEl.
+func SweCheckFictElements(set int32) error {
+	if set != SE_FICTEL_NEELY && set != SE_FICTEL_CLASSIC {
+		return fmt.Errorf("invalid elements %d of the Uranian planets", set)
+	}
+	return nil
+}
+
+// SweRegisterElements defines a fictitious body by its osculating elements, as a line of seorbel.txt does: epoch and
+// equinox as julian days (equinox 0 for the equinox of date), mean anomaly, semi-axis in AU, eccentricity, argument
+// of perihelion, ascending node and inclination, the angles in degrees.
+// Returns the body number ipl for SweCalc, SE_FICT_REG + n for the n-th body (from 0), or an error if the elements
+// are invalid or all numbers up to SE_FICT_MAX are used.
+// Port: not part of C.
+func SweRegisterElements(name string, epoch, equinox, mano, sema, ecce, parg, node, incl float64) (int, error) {
+	for _, v := range []float64{epoch, equinox, mano, sema, ecce, parg, node, incl} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, fmt.Errorf("elements of %s invalid", name)
+		}
+	}
+	switch {
+	case epoch == 0:
+		return 0, errors.New("invalid epoch")
+	case sema <= 0:
+		return 0, errors.New("semi-axis value invalid")
+	case ecce < 0 || ecce >= 1:
+		return 0, errors.New("eccentricity invalid (no parabolic or hyperbolic orbits allowed)")
+	case SE_FICT_REG+len(swed.FictElements) > SE_FICT_MAX:
+		return 0, fmt.Errorf("no more than %d bodies can be registered", SE_FICT_MAX-SE_FICT_REG+1)
+	}
+	swed.FictElements = append(swed.FictElements, fictElements{tjd0: epoch, tequ: equinox, mano: mano * DEGTORAD,
+		sema: sema, ecce: ecce, parg: parg * DEGTORAD, node: node * DEGTORAD, incl: incl * DEGTORAD, name: name})
+	return SE_FICT_REG + len(swed.FictElements) - 1, nil
+}
+
+// SweClearElements removes the bodies of SweRegisterElements, their numbers are used again by new bodies.
+// Port: not part of C.
+func SweClearElements() {
+	swed.FictElements = nil
+	// the save area may contain positions of the removed bodies
+	swiForceAppPosEtc()
+}
+
 // ===== 0522 ===== plan_oscu_elem swemplan.c-0522 ===================================================================
 // Port: important, in the original code there is an ifdef pragma, with parts for SE_NEELY and another part.
 // I splitted this in two ragnges of constants: planOscuElem and planOscuElemNeely
+// The fictEl argument of SweCalcFictEl chooses between them.
 
 var planOscuElem = [SE_NFICT_ELEM][8]float64{
 	{J1900, J1900, 104.5959, 40.99837, 0, 0, 0, 0},  /* Cupido   */
@@ -68,94 +518,368 @@ var planOscuElemNeely = [SE_NFICT_ELEM][8]float64{
 	{2425977.5, 2425977.5, 48.95, 55.1, 0.31, 280.1, 100, 15},
 }
 
-// ===== 0916 ===== check_t_terms swemplan.c-0916 ====================================================================
+// ===== 0579 ===== swi_osc_el_plan swemplan.c-0579 ==================================================================
 
-func checkTTerms(t float64, sinp string) (float64, bool) {
-	isgn := 1
-	var doutp float64 = 0
-	fac := 1.0
-	z := 0
-	tt := make([]float64, 5)
+// swiOscElPlan computes a planet from osculating elements in barycentric cartesian equatorial coordinates J2000.
+// tjd		julian day
+// xp		position and speed of the planet
+// ipl		body number (0 = Cupido)
+// ipli		body number in planetary data structure
+// xearth	barycentric earth, for bodies with geocentric elements
+// xsun		barycentric sun
+// fictEl	built-in elements of the Uranian planets, SE_FICTEL_NEELY or SE_FICTEL_CLASSIC
+// Port: fictEl is added.
+func swiOscElPlan(tjd float64, xp []float64, ipl, ipli int, xearth, xsun []float64, fictEl int32, serr *string) int {
+	var pqr [9]float64
+	var x [6]float64
+	var K float64
+	pedp := &swed.Pldat[SEI_EARTH]
+	pdp := &swed.Pldat[ipli]
+	// orbital elements, either from file or, if file not found, from above built-in set
+	var el fictElements
+	if readElementsFile(ipl, tjd, fictEl, &el, serr) == ERR {
+		return ERR
+	}
+	sema, ecce := el.sema, el.ecce
+	dmot := 0.9856076686 * DEGTORAD / sema / math.Sqrt(sema) // daily motion
+	if el.fictIfl&FICT_GEO != 0 {
+		dmot /= math.Sqrt(SUN_EARTH_MRAT)
+	}
+	sinnode, cosnode := math.Sincos(el.node)
+	sinincl, cosincl := math.Sincos(el.incl)
+	sinparg, cosparg := math.Sincos(el.parg)
+	// Gaussian vector
+	pqr[0] = cosparg*cosnode - sinparg*cosincl*sinnode
+	pqr[1] = -sinparg*cosnode - cosparg*cosincl*sinnode
+	pqr[2] = sinincl * sinnode
+	pqr[3] = cosparg*sinnode + sinparg*cosincl*cosnode
+	pqr[4] = -sinparg*sinnode + cosparg*cosincl*cosnode
+	pqr[5] = -sinincl * cosnode
+	pqr[6] = sinparg * sinincl
+	pqr[7] = cosparg * sinincl
+	pqr[8] = cosincl
+	// Kepler problem
+	M := Mod2PI(el.mano + (tjd-el.tjd0)*dmot) // mean anomaly of date
+	E := M
+	// better E for very high eccentricity and small M
+	if ecce > 0.975 {
+		M2 := M * RADTODEG
+		M180or0 := 0.0
+		if M2 > 150 && M2 < 210 {
+			M2 -= 180
+			M180or0 = 180
+		}
+		if M2 > 330 {
+			M2 -= 360
+		}
+		Msgn := 1.0
+		if M2 < 0 {
+			M2 = -M2
+			Msgn = -1
+		}
+		if M2 < 30 {
+			M2 *= DEGTORAD
+			alpha := (1 - ecce) / (4*ecce + 0.5)
+			beta := M2 / (8*ecce + 1)
+			// Port: C computes pow(..., 1/3) with integer division, i.e. pow(..., 0)
+			zeta := math.Pow(beta+math.Sqrt(beta*beta+alpha*alpha), 0)
+			sigma := zeta - alpha/2
+			sigma = sigma - 0.078*sigma*sigma*sigma*sigma*sigma/(1+ecce)
+			E = Msgn*(M2+ecce*(3*sigma-4*sigma*sigma*sigma)) + M180or0
+		}
+	}
+	E = swiKepler(E, M, ecce)
+	// position and speed, referred to orbital plane
+	if el.fictIfl&FICT_GEO != 0 {
+		K = KGAUSS_GEO / math.Sqrt(sema)
+	} else {
+		K = KGAUSS / math.Sqrt(sema)
+	}
+	sine, cose := math.Sincos(E)
+	fac := math.Sqrt((1 - ecce) * (1 + ecce))
+	rho := 1 - ecce*cose
+	x[0] = sema * (cose - ecce)
+	x[1] = sema * fac * sine
+	x[3] = -K * sine / rho
+	x[4] = K * fac * cose / rho
+	// transformation to ecliptic
+	xp[0] = pqr[0]*x[0] + pqr[1]*x[1]
+	xp[1] = pqr[3]*x[0] + pqr[4]*x[1]
+	xp[2] = pqr[6]*x[0] + pqr[7]*x[1]
+	xp[3] = pqr[0]*x[3] + pqr[1]*x[4]
+	xp[4] = pqr[3]*x[3] + pqr[4]*x[4]
+	xp[5] = pqr[6]*x[3] + pqr[7]*x[4]
+	// transformation to equator
+	eps := swiEpsiln(el.tequ, 0)
+	swiCoortrf2(xp, xp, math.Sin(-eps), math.Cos(-eps))
+	swiCoortrf2(xp[3:], xp[3:], math.Sin(-eps), math.Cos(-eps))
+	// precess to J2000
+	if el.tequ != J2000 {
+		swiPrecess(xp, el.tequ, 0, J_TO_J2000)
+		swiPrecess(xp[3:], el.tequ, 0, J_TO_J2000)
+	}
+	// to solar system barycentre
+	for i := 0; i <= 5; i++ {
+		if el.fictIfl&FICT_GEO != 0 {
+			xp[i] += xearth[i]
+		} else {
+			xp[i] += xsun[i]
+		}
+	}
+	if &xp[0] == &pdp.X[0] {
+		pdp.Teval = tjd // for precession!
+		pdp.Iephe = pedp.Iephe
+	}
+	return OK
+}
+
+// ===== 0694 ===== read_elements_file swemplan.c-0694 ===============================================================
+
+// fictElements contains the orbital elements of a fictitious body, see readElementsFile.
+// Port: replaces the output parameters of read_elements_file.
+type fictElements struct {
+	tjd0, tequ                         float64 // epoch and equinox
+	mano, sema, ecce, parg, node, incl float64 // angles in radians
+	name                               string
+	fictIfl                            int32 // FICT_GEO for geocentric elements
+}
 
+// readElementsFile reads the elements of fictitious body ipl (0 = Cupido) from seorbel.txt into el, or takes the
+// built-in elements if there is no such file. tjd is required for the T terms of the elements.
+// A line of the file contains epoch, equinox, mean anomaly, semi-axis, eccentricity, argument of perihelion,
+// ascending node, inclination and name, separated by commas, and optionally "geo" for a geocentric orbit. Epoch and
+// equinox are a julian day or J2000, B1950, J1900; the equinox may also be JDATE, the equinox of date.
+// Port: the built-in Uranian planets are the elements of Neely, as in C where SE_NEELY is defined, or the classic
+// elements, as chosen with fictEl (SE_FICTEL_NEELY or SE_FICTEL_CLASSIC).
+// A missing file is not reported; C returns the message of swi_fopen() as a warning. The bodies from SE_FICT_REG on
+// are those of SweRegisterElements. The path of the file that is read is kept in swed.FictFnam for the provenance of
+// the position.
+func readElementsFile(ipl int, tjd float64, fictEl int32, el *fictElements, serr *string) int {
+	// Port: the bodies of SweRegisterElements
+	if ipl >= SE_FICT_REG-SE_FICT_OFFSET {
+		i := ipl - (SE_FICT_REG - SE_FICT_OFFSET)
+		if i >= len(swed.FictElements) {
+			if serr != nil {
+				*serr = fmt.Sprintf("error no elements for fictitious body no %7.0f", float64(ipl))
+			}
+			return ERR
+		}
+		*el = swed.FictElements[i]
+		if el.tequ == 0 {
+			el.tequ = tjd // equinox of date
+		}
+		return OK
+	}
+	// -1, because file information is not saved, file is always closed
+	fp, err := SwiFopen(-1, SE_FICTFILE, swed.EphePath)
+	if err != nil {
+		swed.FictFnam = ""
+		// file does not exist, use built-in bodies
+		if ipl >= SE_NFICT_ELEM {
+			if serr != nil {
+				*serr = fmt.Sprintf("error no elements for fictitious body no %7.0f", float64(ipl))
+			}
+			return ERR
+		}
+		elem := &planOscuElemNeely[ipl]
+		if fictEl == SE_FICTEL_CLASSIC {
+			elem = &planOscuElem[ipl]
+		}
+		el.tjd0 = elem[0]            // epoch
+		el.tequ = elem[1]            // equinox
+		el.mano = elem[2] * DEGTORAD // mean anomaly
+		el.sema = elem[3]            // semi-axis
+		el.ecce = elem[4]            // eccentricity
+		el.parg = elem[5] * DEGTORAD // arg. of peri.
+		el.node = elem[6] * DEGTORAD // asc. node
+		el.incl = elem[7] * DEGTORAD // inclination
+		el.name = planFictNam[ipl]
+		return OK
+	}
+	defer fp.Close()
+	swed.FictFnam = fp.Name()
+	// epoch returns the julian day of an epoch or equinox, ok is false for an invalid name
+	epoch := func(sp string, equinox bool) (float64, bool) {
+		sp = strings.ToLower(sp)
+		switch {
+		case strings.HasPrefix(sp, "j2000"):
+			return J2000, true
+		case strings.HasPrefix(sp, "b1950"):
+			return B1950, true
+		case strings.HasPrefix(sp, "j1900"):
+			return J1900, true
+		case equinox && strings.HasPrefix(sp, "jdate"):
+			return tjd, true
+		case strings.HasPrefix(sp, "j") || strings.HasPrefix(sp, "b"):
+			return 0, false
+		}
+		return atof(sp), true
+	}
+	// find elements in file
+	iline := 0
+	iplan := -1
+	var serri string
+	cpos := make([]string, 20)
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		iline++
+		s := strings.TrimLeft(scanner.Text(), " \t")
+		if s == "" || s[0] == '#' || s[0] == '\r' {
+			continue
+		}
+		if i := strings.IndexByte(s, '#'); i >= 0 {
+			s = s[:i]
+		}
+		ncpos := swiCutstr(s, ",", cpos, 20)
+		serri = fmt.Sprintf("error in file %s, line %7.0f:", SE_FICTFILE, float64(iline))
+		if ncpos < 9 {
+			if serr != nil {
+				*serr = fmt.Sprintf("%s nine elements required", serri)
+			}
+			return ERR
+		}
+		iplan++
+		if iplan != ipl {
+			continue
+		}
+		// invalid returns an error for an element
+		invalid := func(msg string) int {
+			if serr != nil {
+				*serr = fmt.Sprintf("%s %s", serri, msg)
+			}
+			return ERR
+		}
+		var ok bool
+		// epoch of elements
+		if el.tjd0, ok = epoch(cpos[0], false); !ok {
+			return invalid("invalid epoch")
+		}
+		tt := tjd - el.tjd0
+		// equinox
+		if el.tequ, ok = epoch(strings.TrimLeft(cpos[1], " \t"), true); !ok {
+			return invalid("invalid equinox")
+		}
+		// mean anomaly t0
+		var retc int
+		el.mano, retc = checkTTerms(tt, cpos[2])
+		el.mano = SweDegnorm(el.mano)
+		if retc == ERR {
+			return invalid("mean anomaly value invalid")
+		}
+		// if mean anomaly has t terms (which happens with fictitious planet Vulcan), we set epoch = tjd, so that no
+		// motion will be added anymore equinox = tjd
+		if retc == 1 {
+			el.tjd0 = tjd
+		}
+		el.mano *= DEGTORAD
+		// semi-axis
+		el.sema, retc = checkTTerms(tt, cpos[3])
+		if el.sema <= 0 || retc == ERR {
+			return invalid("semi-axis value invalid")
+		}
+		// eccentricity
+		el.ecce, retc = checkTTerms(tt, cpos[4])
+		if el.ecce >= 1 || el.ecce < 0 || retc == ERR {
+			return invalid("eccentricity invalid (no parabolic or hyperbolic orbits allowed)")
+		}
+		// perihelion argument
+		el.parg, retc = checkTTerms(tt, cpos[5])
+		el.parg = SweDegnorm(el.parg)
+		if retc == ERR {
+			return invalid("perihelion argument value invalid")
+		}
+		el.parg *= DEGTORAD
+		// node
+		el.node, retc = checkTTerms(tt, cpos[6])
+		el.node = SweDegnorm(el.node)
+		if retc == ERR {
+			return invalid("node value invalid")
+		}
+		el.node *= DEGTORAD
+		// inclination
+		el.incl, retc = checkTTerms(tt, cpos[7])
+		el.incl = SweDegnorm(el.incl)
+		if retc == ERR {
+			return invalid("inclination value invalid")
+		}
+		el.incl *= DEGTORAD
+		// planet name
+		el.name = rightTrim(strings.TrimLeft(cpos[8], " \t"))
+		// geocentric
+		if ncpos > 9 && strings.Contains(strings.ToLower(cpos[9]), "geo") {
+			el.fictIfl |= FICT_GEO
+		}
+		return OK
+	}
+	if serr != nil {
+		*serr = fmt.Sprintf("%s elements for planet %7.0f not found", serri, float64(ipl))
+	}
+	return ERR
+}
+
+// ===== 0916 ===== check_t_terms swemplan.c-0916 ====================================================================
+
+// checkTTerms evaluates an element of seorbel.txt, a sum of terms with factors, e.g. "252.8987988 + 707550.7341 * T".
+// T is the time t (days after the epoch) in julian centuries, T2 .. T4 are its powers.
+// Returns the value and 1 if there are additional terms, otherwise 0.
+// Port: returns ERR for characters that are not part of a term; C loops endlessly.
+func checkTTerms(t float64, sinp string) (float64, int) {
+	var tt [5]float64
 	tt[0] = t / 36525
 	tt[1] = tt[0]
 	tt[2] = tt[1] * tt[1]
 	tt[3] = tt[2] * tt[1]
 	tt[4] = tt[3] * tt[1]
-
-	hasAdditionalTerms := strings.ContainsAny(sinp, "+-")
-
-	sp := []rune(strings.TrimSpace(sinp))
-	pos := 0
-
-	for pos < len(sp) {
-		for pos < len(sp) && (sp[pos] == ' ' || sp[pos] == '\t') {
-			pos++
-		}
-		if pos >= len(sp) {
-			break
+	retc := 0
+	if strings.ContainsAny(sinp, "+-") {
+		retc = 1 // with additional terms
+	}
+	doutp := 0.0
+	fac := 1.0
+	z := 0
+	sp := 0
+	for {
+		for sp < len(sinp) && (sinp[sp] == ' ' || sinp[sp] == '\t') {
+			sp++
 		}
-		if sp[pos] == '+' || sp[pos] == '-' || pos == len(sp) {
+		if sp == len(sinp) || sinp[sp] == '+' || sinp[sp] == '-' {
 			if z > 0 {
 				doutp += fac
 			}
-			isgn = 1
-			if pos < len(sp) && sp[pos] == '-' {
-				isgn = -1
+			fac = 1
+			if sp < len(sinp) && sinp[sp] == '-' {
+				fac = -1
 			}
-			fac = float64(isgn)
-			if pos >= len(sp) {
-				return doutp, hasAdditionalTerms
+			if sp == len(sinp) {
+				return doutp, retc
 			}
-			pos++
+			sp++
 		} else {
-			for pos < len(sp) && (sp[pos] == '*' || sp[pos] == ' ' || sp[pos] == '\t') {
-				pos++
-			}
-			if pos >= len(sp) {
-				break
-			}
-			// Handle 'T' terms
-			if sp[pos] == 't' || sp[pos] == 'T' {
-				pos++
-				if pos < len(sp) {
-					if sp[pos] == '+' || sp[pos] == '-' {
-						fac *= tt[0]
-					} else {
-						numStr := ""
-						for pos < len(sp) && unicode.IsDigit(sp[pos]) {
-							numStr += string(sp[pos])
-							pos++
-						}
-						if numStr != "" {
-							i, _ := strconv.Atoi(numStr)
-							if i >= 0 && i <= 4 {
-								fac *= tt[i]
-							}
-						}
-						continue
-					}
-				}
-			} else {
-				// Handle numbers
-				numStr := ""
-				for pos < len(sp) && (unicode.IsDigit(sp[pos]) || sp[pos] == '.') {
-					numStr += string(sp[pos])
-					pos++
+			for sp < len(sinp) && strings.IndexByte("* \t", sinp[sp]) >= 0 {
+				sp++
+			}
+			start := sp
+			if sp < len(sinp) && (sinp[sp] == 't' || sinp[sp] == 'T') {
+				// a T
+				sp++
+				if sp < len(sinp) && (sinp[sp] == '+' || sinp[sp] == '-') {
+					fac *= tt[0]
+				} else if i := atoi(sinp[sp:]); i <= 4 && i >= 0 {
+					fac *= tt[i]
 				}
-				if numStr != "" {
-					num, err := strconv.ParseFloat(numStr, 64)
-					if err == nil && (num != 0 || numStr == "0") {
-						fac *= num
-					}
+			} else if sp < len(sinp) {
+				// a number
+				if f := atof(sinp[sp:]); f != 0 || sinp[sp] == '0' {
+					fac *= f
 				}
-				continue
+			}
+			for sp < len(sinp) && strings.IndexByte("0123456789.", sinp[sp]) >= 0 {
+				sp++
+			}
+			if sp == start && sp < len(sinp) {
+				return doutp, ERR
 			}
 		}
 		z++
 	}
-	if z > 0 {
-		doutp += fac
-	}
-	return doutp, hasAdditionalTerms
 }
@@ -0,0 +1,427 @@
+package internal
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSweCalcMoshier(t *testing.T) {
+	// values from the C version of the Swiss Ephemeris
+	geo := int32(SEFLG_MOSEPH | SEFLG_SPEED)
+	helio := geo | SEFLG_HELCTR
+	equ := geo | SEFLG_EQUATORIAL | SEFLG_TRUEPOS | SEFLG_J2000
+	tests := []struct {
+		tjd   float64
+		ipl   int
+		iflag int32
+		want  [6]float64
+	}{
+		{625100.5, SE_SUN, geo, [6]float64{49.8457191459, -0.0012096364, 1.0155852092, 0.9550953631, -0.0000418197, -0.0001644159}},
+		{625100.5, SE_MERCURY, geo, [6]float64{62.1149216418, 1.9021733393, 1.3026221520, 1.8711804069, -0.0038229387, -0.0075651688}},
+		{625100.5, SE_VENUS, geo, [6]float64{25.3069298365, -1.0937012404, 1.5080764062, 1.2103177454, 0.0363176297, 0.0045603881}},
+		{625100.5, SE_MARS, geo, [6]float64{51.1180867774, 0.7769461926, 2.6699197061, 0.6375948649, 0.0069082862, 0.0003057925}},
+		{625100.5, SE_JUPITER, geo, [6]float64{113.5862105108, 1.4182572182, 5.7824584739, 0.1464613007, -0.0028411241, 0.0138554570}},
+		{625100.5, SE_SATURN, geo, [6]float64{73.9481466694, 0.2839117497, 10.2864110004, 0.1180933809, 0.0012452296, 0.0069094513}},
+		{625100.5, SE_URANUS, geo, [6]float64{54.8291032987, 0.0866036733, 19.5279620540, 0.0615358457, 0.0001537276, 0.0011913634}},
+		{625100.5, SE_NEPTUNE, geo, [6]float64{109.2761720759, 1.2038528083, 30.7416281891, 0.0221345582, -0.0003916213, 0.0144420979}},
+		{625100.5, SE_PLUTO, geo, [6]float64{49.9119440085, 1.8883944328, 40.4582119341, 0.0276168164, 0.0011044337, -0.0007595485}},
+		{2451545.0, SE_SUN, geo, [6]float64{280.3681665583, 0.0002323318, 0.9833276503, 1.0194320212, -0.0000008867, -0.0000073427}},
+		{2451545.0, SE_MERCURY, geo, [6]float64{271.8881253093, -0.9947531533, 1.4154660708, 1.5562501903, -0.0974941557, 0.0046202407}},
+		{2451545.0, SE_VENUS, geo, [6]float64{241.5649051341, 2.0663687344, 1.1375745890, 1.2090388882, -0.0280726203, 0.0064858953}},
+		{2451545.0, SE_MARS, geo, [6]float64{327.9627402813, -1.0677921622, 1.8496834273, 0.7756727457, 0.0124754712, 0.0054248042}},
+		{2451545.0, SE_JUPITER, geo, [6]float64{25.2530001972, -1.2621766595, 4.6211698699, 0.0407588241, 0.0051733619, 0.0155207760}},
+		{2451545.0, SE_SATURN, geo, [6]float64{40.3956536907, -2.4448260618, 8.6527853483, -0.0199461120, 0.0047431385, 0.0143793155}},
+		{2451545.0, SE_URANUS, geo, [6]float64{314.8091859736, -0.6583330590, 20.7271619070, 0.0503432052, 0.0002500319, 0.0099098774}},
+		{2451545.0, SE_NEPTUNE, geo, [6]float64{303.1929549455, 0.2349913519, 31.0245221944, 0.0355699604, -0.0002236643, 0.0067554826}},
+		{2451545.0, SE_PLUTO, geo, [6]float64{251.4546828770, 10.8552013821, 31.0643960702, 0.0351530726, 0.0014673306, -0.0079140794}},
+		{2817900.5, SE_SUN, geo, [6]float64{297.7415058182, 0.0001210333, 0.9837478532, 1.0184743723, 0.0000252813, -0.0000053521}},
+		{2817900.5, SE_MERCURY, geo, [6]float64{292.3525930814, 3.0727890541, 0.6782552828, -1.2697244139, 0.1315923103, 0.0049433832}},
+		{2817900.5, SE_VENUS, geo, [6]float64{317.6251132189, -1.5493768505, 1.5704022511, 1.2506824298, -0.0112883216, -0.0033592617}},
+		{2817900.5, SE_MARS, geo, [6]float64{125.7578497756, 4.2618482867, 0.6203283893, -0.3926241373, 0.0219893626, -0.0002262526}},
+		{2817900.5, SE_JUPITER, geo, [6]float64{253.1551365564, 0.7870289889, 6.0654035934, 0.1858868229, 0.0004210477, -0.0115609657}},
+		{2817900.5, SE_SATURN, geo, [6]float64{75.2343738481, -1.8207585298, 8.3652953456, -0.0486744098, 0.0037168386, 0.0112257939}},
+		{2817900.5, SE_URANUS, geo, [6]float64{310.2621111525, -0.5858762847, 20.6037554411, 0.0581685907, 0.0000215770, 0.0039452121}},
+		{2817900.5, SE_NEPTUNE, geo, [6]float64{347.1226221994, -0.7114407931, 30.6143847331, 0.0271667062, 0.0001539580, 0.0132008583}},
+		{2817900.5, SE_PLUTO, geo, [6]float64{288.1121300283, 4.7121837724, 32.8189078834, 0.0357911577, -0.0012062885, -0.0023085613}},
+		{2451545.0, SE_MERCURY, helio, [6]float64{253.7716576187, -3.0219534439, 0.4664704982, 2.7450160015, -0.3037160985, 0.0003523090}},
+		{2451545.0, SE_VENUS, helio, [6]float64{182.5923186474, 3.2647130124, 0.7202123869, 1.6184486284, -0.0262420437, 0.0001034131}},
+		{2451545.0, SE_MARS, helio, [6]float64{359.4384058410, -1.4197786666, 1.3912032587, 0.6259479942, 0.0129540084, 0.0005159973}},
+		{2451545.0, SE_JUPITER, helio, [6]float64{36.2880508388, -1.1745998108, 4.9653824620, 0.0911863518, 0.0008997123, 0.0001300717}},
+		{2451545.0, SE_SATURN, helio, [6]float64{45.7164259293, -2.3031965209, 9.1838582896, 0.0361864726, 0.0005846375, -0.0002154625}},
+		{2451545.0, SE_URANUS, helio, [6]float64{316.4135567775, -0.6848448029, 19.9240118374, 0.0109180635, -0.0000639459, 0.0000976966}},
+		{2451545.0, SE_NEPTUNE, helio, [6]float64{303.9239225367, 0.2420252926, 30.1206135396, 0.0059979137, -0.0001774202, -0.0000351301}},
+		{2451545.0, SE_PLUTO, helio, [6]float64{250.5411493634, 11.1616760108, 30.2232234313, 0.0065478991, -0.0014811496, 0.0002950169}},
+		{2451545.0, SE_SUN, equ, [6]float64{281.2881678235, -23.0333040184, 0.9833276503, 1.1043381228, 0.0793657230, -0.0000073427}},
+		{2451545.0, SE_MERCURY, equ, [6]float64{272.0916428412, -24.4210005317, 1.4155248967, 1.7103280182, -0.0748717506, 0.0046120952}},
+		{2451545.0, SE_VENUS, equ, [6]float64{239.9037514007, -18.4526471165, 1.1376892190, 1.2422137743, -0.2686874113, 0.0064844513}},
+		{2451545.0, SE_MARS, equ, [6]float64{330.5282601825, -13.1791382201, 1.8496034248, 0.7427598977, 0.2803142232, 0.0054228977}},
+		{2451545.0, SE_JUPITER, equ, [6]float64{23.8721676499, 8.5968234833, 4.6211329382, 0.0365332460, 0.0196665215, 0.0155226234}},
+		{2451545.0, SE_SATURN, equ, [6]float64{38.7678387074, 12.6169093178, 8.6527478013, -0.0208748494, -0.0016568534, 0.0143867060}},
+		{2451545.0, SE_URANUS, equ, [6]float64{317.4850747001, -17.0184813469, 20.7271609626, 0.0502707327, 0.0149992908, 0.0098799997}},
+		{2451545.0, SE_NEPTUNE, equ, [6]float64{305.4436686437, -19.2122388965, 31.0245088003, 0.0367053953, 0.0079825843, 0.0067052213}},
+		{2451545.0, SE_PLUTO, equ, [6]float64{251.4291121378, -11.3968585928, 31.0644592621, 0.0350315416, -0.0029764223, -0.0079612723}},
+	}
+	defer SweSetEphePath("")
+	SweSetEphePath(t.TempDir())
+	for _, tt := range tests {
+		xx, iflgret, prov, err := SweCalc(tt.tjd, tt.ipl, tt.iflag)
+		if err != nil || iflgret&SEFLG_EPHMASK != SEFLG_MOSEPH || prov != (Provenance{Iephe: SEFLG_MOSEPH, Denum: 403}) {
+			t.Errorf("SweCalc(%.1f, %d, %d): flags %d, provenance %+v, error %v", tt.tjd, tt.ipl, tt.iflag, iflgret,
+				prov, err)
+			continue
+		}
+		for i := range xx {
+			tol := 1e-9
+			if i >= 3 {
+				tol = 1e-7
+			}
+			if math.Abs(xx[i]-tt.want[i]) > tol {
+				t.Errorf("SweCalc(%.1f, %d, %d) = %.10f; want %.10f", tt.tjd, tt.ipl, tt.iflag, xx, tt.want)
+				break
+			}
+		}
+	}
+	// before the start of the Moshier ephemeris
+	_, iflgret, _, err := SweCalc(MOSHPLEPH_START-1, SE_MARS, geo)
+	if iflgret != ERR || err == nil || !strings.Contains(err.Error(), "outside Moshier planet range") {
+		t.Errorf("SweCalc before the Moshier range: flags %d, error %v; want ERR and a range error", iflgret, err)
+	}
+}
+
+// testSeorbel contains test elements for fictitious bodies, with comments, an empty line, T terms and a geocentric
+// orbit.
+const testSeorbel = `# test elements for fictitious bodies
+J1900, J1900, 163.7409, 40.99837, 0.00460, 171.4333, 129.8325, 1.0833, Cupido Neely # comment
+  2368547.66, 2431456.5, 0.0, 77.775, 0.3, 0.7, 0, 0, Isis-Transpluto
+
+J2000, JDATE, 252.8987988 + 707550.7341 * T, 0.13744, 0.019, 322.212069+1670.056*T, 47.787931-1670.056*T, 7.5, Vulcan
+b1950, J2000, 0.5*T + 10.5, 0.0167, 0.01, 20 - T2, 30, 5, Moonlet, geo
+J2000, B1950, 0.0, 2.7, 0.0, 0, 0, 0, Zero
+`
+
+func TestSweCalcFict(t *testing.T) {
+	// values from the C version of the Swiss Ephemeris
+	geo := int32(SEFLG_MOSEPH | SEFLG_SPEED)
+	helio := geo | SEFLG_HELCTR
+	equ := geo | SEFLG_EQUATORIAL | SEFLG_TRUEPOS | SEFLG_J2000
+	xyz := geo | SEFLG_XYZ | SEFLG_NOABERR
+	type fictTest struct {
+		tjd   float64
+		ipl   int
+		iflag int32
+		want  [6]float64
+	}
+	// without seorbel.txt: the built-in elements of Neely
+	builtin := []fictTest{
+		{2415020.5, 40, geo, [6]float64{105.2912295308, -0.4632892486, 40.2000924463, -0.0209364142, 0.0000509443, -0.0016211690}},
+		{2415020.5, 40, helio, [6]float64{105.1631963665, -0.4522759207, 41.1794882763, 0.0038075673, 0.0000671611, 0.0000034295}},
+		{2415020.5, 40, equ, [6]float64{108.0239765091, 21.9506497386, 40.2000945653, -0.0224965640, 0.0026385661, -0.0015502712}},
+		{2415020.5, 40, xyz, [6]float64{-10.5975350427, 38.7767201238, -0.3250540731, 0.0145844121, 0.0023793771, 0.0000488564}},
+		{2451545.0, 40, geo, [6]float64{243.8964412901, 0.9745414582, 41.6886736345, 0.0231323597, 0.0002196082, -0.0103167326}},
+		{2451545.0, 40, helio, [6]float64{243.0821552294, 0.9933349790, 40.9022353801, 0.0038127862, -0.0000217846, -0.0000106571}},
+		{2451545.0, 40, equ, [6]float64{242.1001513878, -19.9728858227, 41.6886803358, 0.0241185264, -0.0040752150, -0.0103761785}},
+		{2451545.0, 40, xyz, [6]float64{-18.3371113019, -37.4325132863, 0.7090892573, 0.0196400008, 0.0019348554, -0.0000157624}},
+		{2460000.5, 40, geo, [6]float64{276.5589161300, 0.6162962151, 41.3237176979, 0.0163294429, 0.0001731141, -0.0147843163}},
+		{2460000.5, 40, helio, [6]float64{275.3644994812, 0.6237870151, 40.8310888316, 0.0038092442, -0.0000488763, -0.0000057299}},
+		{2460000.5, 40, equ, [6]float64{276.7667342043, -22.6734809680, 41.3237297834, 0.0175499054, 0.0009248458, -0.0148206446}},
+		{2460000.5, 40, xyz, [6]float64{4.7220553150, -41.0506301570, 0.4445241219, 0.0099447926, 0.0160629529, -0.0000341828}},
+		{2451545.0, 41, geo, [6]float64{78.1804007075, -1.0504283040, 49.8314885728, -0.0158479352, 0.0001369451, 0.0065207891}},
+		{2451545.0, 42, geo, [6]float64{185.3882907615, -0.0021170470, 59.1518709630, 0.0008255466, -0.0000006317, -0.0174020818}},
+		{2451545.0, 43, geo, [6]float64{87.7940171030, 0.0132477699, 63.9647428367, -0.0133638474, -0.0000065409, 0.0036959565}},
+		{2451545.0, 44, geo, [6]float64{201.3065053746, -0.0055875791, 70.4793696663, 0.0044989773, 0.0000003326, -0.0171260892}},
+		{2451545.0, 46, geo, [6]float64{110.4384347892, 0.0119744618, 76.2872761100, -0.0114098554, -0.0000050341, -0.0031769578}},
+		{2451545.0, 47, geo, [6]float64{214.5580753657, -0.0081501008, 84.0671386881, 0.0062943985, 0.0000014413, -0.0158799882}},
+		{2451545.0, 48, geo, [6]float64{145.7365255579, 0.0035877177, 92.7031458617, -0.0065315424, -0.0000028560, -0.0121836966}},
+		{2451545.0, 49, geo, [6]float64{54.0264966232, -20.5639494788, 462.1224381780, -0.0016502815, 0.0005227783, 0.0113966823}},
+		{2451545.0, 50, geo, [6]float64{249.3477631426, -15.5963442777, 107.6069259775, 0.0090939771, -0.0008849691, -0.0078643278}},
+		{2451545.0, 51, geo, [6]float64{203.7216613540, -0.0089956104, 35.5944099648, 0.0113187796, -0.0000035663, -0.0172388680}},
+		{2451545.0, 52, geo, [6]float64{195.7149549177, -0.0064904571, 38.1289720214, 0.0067046998, -0.0000028806, -0.0176700570}},
+		{2451545.0, 53, geo, [6]float64{223.1213954684, -0.0065493966, 35.0575105463, 0.0208030176, 0.0000004760, -0.0144594202}},
+		{2451545.0, 54, geo, [6]float64{159.6386711281, 12.8894417747, 64.4246411800, -0.0063497812, 0.0032147415, -0.0141873294}},
+		{2460000.5, 54, geo, [6]float64{172.9209333805, 14.4444174249, 67.6226052094, -0.0129076825, 0.0012518314, -0.0048175833}},
+		{2460000.5, 54, helio, [6]float64{172.6684982456, 14.2471562349, 68.5411130228, 0.0015443194, 0.0001209990, 0.0003563730}},
+		{2460000.5, 54, equ, [6]float64{179.0874581160, 16.1715098735, 67.6227474621, -0.0113891167, 0.0062741826, -0.0047080598}},
+		{2460000.5, 54, xyz, [6]float64{-64.9849447799, 8.0767163157, 16.8683235924, 0.0067066764, 0.0140673315, 0.0002287072}},
+	}
+	// with testSeorbel
+	file := []fictTest{
+		{2415020.5, 41, geo, [6]float64{101.6146738850, -0.0056752801, 74.3002184447, -0.0119191554, 0.0000037975, 0.0000314792}},
+		{2451545.0, 41, geo, [6]float64{145.7365255579, 0.0035877177, 92.7031458617, -0.0065315424, -0.0000028560, -0.0121836966}},
+		{2451545.0, 41, helio, [6]float64{145.3032337870, 0.0035588580, 93.3967185090, 0.0009907740, -0.0000033519, 0.0003619555}},
+		{2451545.0, 41, equ, [6]float64{147.9958638113, 12.9443058735, 92.7033458645, -0.0064186458, 0.0022418104, -0.0120694775}},
+		{2451545.0, 41, xyz, [6]float64{-76.6114906736, 52.1972481623, 0.0058051964, 0.0159914746, 0.0020358208, -0.0000054622}},
+		{2460000.5, 41, geo, [6]float64{153.4040606464, 0.0038359645, 95.1717393237, -0.0095338090, -0.0000036937, 0.0007252002}},
+		{2415020.5, 42, geo, [6]float64{276.4841733810, -0.5276351651, 0.8582164662, -1.5673826544, -0.3520336804, 0.0193022004}},
+		{2451545.0, 42, geo, [6]float64{277.9539937314, -0.5042293842, 1.1140407246, 3.1660294403, -0.2514376602, 0.0119332532}},
+		{2451545.0, 42, helio, [6]float64{260.5889823266, -4.0798080215, 0.1382535022, 19.0468917108, -2.0969859918, -0.0008328543}},
+		{2451545.0, 42, equ, [6]float64{278.7185111364, -23.7039925025, 1.1141221299, 3.4692191070, -0.0596605914, 0.0118528617}},
+		{2451545.0, 42, xyz, [6]float64{0.1542640386, -1.1032648024, -0.0098039905, 0.0626101960, -0.0032489686, -0.0049936683}},
+		{2460000.5, 42, geo, [6]float64{328.5657117277, -0.8440834693, 1.0265528855, 1.8698992065, -0.1448127618, 0.0409847356}},
+		{2415020.5, 43, geo, [6]float64{58.7201018419, 2.5013619831, 0.0165368244, 0.8058118815, 0.0607870192, 0.0000004010}},
+		{2451545.0, 43, geo, [6]float64{60.6166881847, 2.5515476773, 0.0165370579, 0.8057885404, 0.0605389072, 0.0000004207}},
+		{2451545.0, 43, helio, [6]float64{99.7604501513, 0.0421156607, 0.9960846751, 1.0166926287, 0.0010078236, -0.0000465029}},
+		{2451545.0, 43, equ, [6]float64{57.8897576674, 22.7754050375, 0.0165359898, 0.8393436469, 0.2295382708, 0.0000004389}},
+		{2451545.0, 43, xyz, [6]float64{0.0081069827, 0.0143947628, 0.0007361523, -0.0002026105, 0.0001137238, 0.0000174742}},
+		{2460000.5, 43, geo, [6]float64{60.7745869169, 2.5417610155, 0.0165377077, 0.8057504635, 0.0606573438, 0.0000004452}},
+		{2415020.5, 44, geo, [6]float64{186.4702024186, -0.0010636402, 2.4521207469, 0.2016566542, 0.0000370431, -0.0136592041}},
+		{2451545.0, 44, geo, [6]float64{341.9980254430, 0.0006542201, 3.0249106255, 0.3454042594, 0.0000196130, 0.0120380216}},
+		{2451545.0, 44, helio, [6]float64{0.6910359095, 0.0006485828, 2.7000000000, 0.2221964320, 0.0000251248, -0.0000000000}},
+		{2451545.0, 44, equ, [6]float64{343.4073941846, -7.0570174849, 3.0248520167, 0.3217719683, 0.1316971410, 0.0120356339}},
+		{2451545.0, 44, xyz, [6]float64{2.8768736681, -0.9347096816, 0.0000345343, 0.0170822883, 0.0136270028, 0.0000011703}},
+		{2460000.5, 44, geo, [6]float64{58.1694443659, 0.0096543403, 2.6525279772, 0.2577594501, -0.0000385593, 0.0134467605}},
+	}
+	check := func(tests []fictTest, fnam string) {
+		for _, tt := range tests {
+			xx, iflgret, prov, err := SweCalc(tt.tjd, tt.ipl, tt.iflag)
+			want := Provenance{Iephe: SEFLG_MOSEPH, Fnam: fnam, Denum: 403}
+			if fnam == "" && tt.ipl <= SE_POSEIDON {
+				want.FictEl = SE_FICTEL_NEELY
+			}
+			if err != nil || iflgret&SEFLG_EPHMASK != SEFLG_MOSEPH || prov != want {
+				t.Errorf("SweCalc(%.1f, %d, %d): flags %d, provenance %+v, error %v", tt.tjd, tt.ipl, tt.iflag, iflgret,
+					prov, err)
+				continue
+			}
+			for i := range xx {
+				// the speed of the light deflection is a difference over DEFL_SPEED_INTV, which amplifies rounding
+				// differences
+				tol := 1e-9
+				if i >= 3 {
+					tol = 1e-7
+				}
+				if math.Abs(xx[i]-tt.want[i]) > tol {
+					t.Errorf("SweCalc(%.1f, %d, %d) = %.10f; want %.10f", tt.tjd, tt.ipl, tt.iflag, xx, tt.want)
+					break
+				}
+			}
+		}
+	}
+	defer SweSetEphePath("")
+	SweSetEphePath(t.TempDir())
+	check(builtin, "")
+	for ipl, want := range map[int]string{SE_FICT_OFFSET: "Cupido", SE_FICT_OFFSET + 8: "Isis-Transpluto",
+		SE_FICT_OFFSET + 14: "Pickering", SE_FICT_OFFSET + 15: "name not found", SE_SUN: "name not found"} {
+		if got := SweGetFictName(ipl); got != want {
+			t.Errorf("SweGetFictName(%d) = %q; want %q", ipl, got, want)
+		}
+	}
+	_, iflgret, _, err := SweCalc(2451545.0, SE_FICT_OFFSET+16, geo)
+	if iflgret != ERR || err == nil || err.Error() != "error no elements for fictitious body no      16" {
+		t.Errorf("SweCalc without elements: flags %d, error %v; want ERR", iflgret, err)
+	}
+
+	dir := t.TempDir()
+	fnam := filepath.Join(dir, SE_FICTFILE)
+	if err := os.WriteFile(fnam, []byte(testSeorbel), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SweSetEphePath(dir)
+	check(file, fnam)
+	for ipl, want := range map[int]string{SE_FICT_OFFSET: "Cupido Neely", SE_FICT_OFFSET + 3: "Moonlet",
+		SE_FICT_OFFSET + 5: "name not found"} {
+		if got := SweGetFictName(ipl); got != want {
+			t.Errorf("SweGetFictName(%d) = %q; want %q", ipl, got, want)
+		}
+	}
+	_, iflgret, _, err = SweCalc(2451545.0, SE_FICT_OFFSET+5, geo)
+	if iflgret != ERR || err == nil ||
+		err.Error() != "error in file seorbel.txt, line       7: elements for planet       5 not found" {
+		t.Errorf("SweCalc of a missing body: flags %d, error %v; want ERR", iflgret, err)
+	}
+	// the file name is not kept when the file is removed
+	if err := os.Remove(fnam); err != nil {
+		t.Fatal(err)
+	}
+	SweSetEphePath(dir)
+	check(builtin[:1], "")
+}
+
+func TestReadElementsFileErrors(t *testing.T) {
+	// messages from the C version of the Swiss Ephemeris
+	tests := []struct {
+		line, want string
+	}{
+		{"J2000, J2000, 10, 40, 0.1, 0, 0, 0", "nine elements required"},
+		{"J2000, J2000, 10, 40, 1.0, 0, 0, 0, Parabola", "eccentricity invalid (no parabolic or hyperbolic orbits allowed)"},
+		{"J2000, J2000, 10, -40, 0.1, 0, 0, 0, Negative", "semi-axis value invalid"},
+		{"J2100, J2000, 10, 40, 0.1, 0, 0, 0, Epoch", "invalid epoch"},
+		{"J2000, JXXX, 10, 40, 0.1, 0, 0, 0, Equinox", "invalid equinox"},
+		{"J2000, J2000, 10 + x, 40, 0.1, 0, 0, 0, Term", "mean anomaly value invalid"},
+	}
+	defer SweSetEphePath("")
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, SE_FICTFILE), []byte("# test\n"+tt.line+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		SweSetEphePath(dir)
+		want := "error in file seorbel.txt, line       2: " + tt.want
+		if _, iflgret, _, err := SweCalc(2451545.0, SE_FICT_OFFSET, SEFLG_MOSEPH); iflgret != ERR || err == nil ||
+			err.Error() != want {
+			t.Errorf("SweCalc with %q: flags %d, error %v; want %q", tt.line, iflgret, err, want)
+		}
+	}
+}
+
+func TestCheckTTerms(t *testing.T) {
+	tests := []struct {
+		sinp string
+		t    float64
+		want float64
+		retc int
+	}{
+		{"7.5", 36525, 7.5, 0},
+		{"0", 36525, 0, 0},
+		{"0.5*T + 10.5", 73050, 11.5, 1},
+		{"20 - T2", 73050, 16, 1},
+		{" 1 + 2 * T + 3*T3 ", 73050, 29, 1},
+		{"-T", 36525, -1, 1},
+		{"10 + x", 36525, 10, ERR},
+	}
+	for _, tt := range tests {
+		if got, retc := checkTTerms(tt.t, tt.sinp); math.Abs(got-tt.want) > 1e-12 || retc != tt.retc {
+			t.Errorf("checkTTerms(%.0f, %q) = %v, %d; want %v, %d", tt.t, tt.sinp, got, retc, tt.want, tt.retc)
+		}
+	}
+}
+
+func TestSweRegisterElements(t *testing.T) {
+	defer SweClearElements()
+	defer SweSetEphePath("")
+	SweSetEphePath(t.TempDir())
+	geo := int32(SEFLG_MOSEPH | SEFLG_SPEED)
+	// the elements of Isis-Transpluto and Zero in testSeorbel, values from the C version of the Swiss Ephemeris
+	tests := []struct {
+		name                                               string
+		epoch, equinox, mano, sema, ecce, parg, node, incl float64
+		iflag                                              int32
+		want                                               [6]float64
+	}{
+		{"Isis-Transpluto", 2368547.66, 2431456.5, 0, 77.775, 0.3, 0.7, 0, 0, geo,
+			[6]float64{145.7365255579, 0.0035877177, 92.7031458617, -0.0065315424, -0.0000028560, -0.0121836966}},
+		{"Zero", J2000, B1950, 0, 2.7, 0, 0, 0, 0, geo | SEFLG_EQUATORIAL | SEFLG_TRUEPOS | SEFLG_J2000,
+			[6]float64{343.4073941846, -7.0570174849, 3.0248520167, 0.3217719683, 0.1316971410, 0.0120356339}},
+	}
+	for i, tt := range tests {
+		ipl, err := SweRegisterElements(tt.name, tt.epoch, tt.equinox, tt.mano, tt.sema, tt.ecce, tt.parg, tt.node,
+			tt.incl)
+		if ipl != SE_FICT_REG+i || err != nil {
+			t.Fatalf("SweRegisterElements(%q) = %d, %v; want %d", tt.name, ipl, err, SE_FICT_REG+i)
+		}
+		if name := SweGetFictName(ipl); name != tt.name {
+			t.Errorf("SweGetFictName(%d) = %q; want %q", ipl, name, tt.name)
+		}
+		xx, _, prov, err := SweCalc(2451545.0, ipl, tt.iflag)
+		if err != nil || prov != (Provenance{Iephe: SEFLG_MOSEPH, Denum: 403}) {
+			t.Errorf("SweCalc(%d): provenance %+v, error %v", ipl, prov, err)
+		}
+		for j := range xx {
+			if math.Abs(xx[j]-tt.want[j]) > 1e-7 {
+				t.Errorf("SweCalc(%d) = %.10f; want %.10f", ipl, xx, tt.want)
+				break
+			}
+		}
+	}
+	// the equinox of date is JDATE in seorbel.txt
+	ipl, err := SweRegisterElements("Equinox of date", J2000, 0, 10, 5, 0.1, 20, 30, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	line := "J2000, JDATE, 10, 5, 0.1, 20, 30, 2, Equinox of date\n"
+	if err := os.WriteFile(filepath.Join(dir, SE_FICTFILE), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SweSetEphePath(dir)
+	for _, tjd := range []float64{2415020.5, 2460000.5} {
+		xreg, _, _, err1 := SweCalc(tjd, ipl, geo)
+		xfile, _, _, err2 := SweCalc(tjd, SE_FICT_OFFSET, geo)
+		if err1 != nil || err2 != nil || xreg != xfile {
+			t.Errorf("SweCalc(%.1f) = %.10f, %v; want %.10f, %v as from seorbel.txt", tjd, xreg, err1, xfile, err2)
+		}
+	}
+	// invalid elements
+	for _, el := range [][8]float64{{0, J2000, 0, 1, 0, 0, 0, 0}, {J2000, J2000, 0, 0, 0, 0, 0, 0},
+		{J2000, J2000, 0, 1, 1, 0, 0, 0}, {J2000, J2000, math.NaN(), 1, 0, 0, 0, 0}} {
+		if ipl, err := SweRegisterElements("Invalid", el[0], el[1], el[2], el[3], el[4], el[5], el[6], el[7]); err == nil {
+			t.Errorf("SweRegisterElements(%v) = %d; want an error", el, ipl)
+		}
+	}
+	// the numbers are used again after clearing
+	SweClearElements()
+	if _, iflgret, _, err := SweCalc(2451545.0, ipl, geo); iflgret != ERR || err == nil {
+		t.Errorf("SweCalc of a cleared body: flags %d, error %v; want ERR", iflgret, err)
+	}
+	if ipl, err := SweRegisterElements("New", J2000, J2000, 0, 1, 0, 0, 0, 0); ipl != SE_FICT_REG || err != nil {
+		t.Errorf("SweRegisterElements after SweClearElements = %d, %v; want %d", ipl, err, SE_FICT_REG)
+	}
+}
+
+func TestSweCalcFictEl(t *testing.T) {
+	// values from the C version of the Swiss Ephemeris, compiled without SE_NEELY
+	geo := int32(SEFLG_MOSEPH | SEFLG_SPEED)
+	helio := geo | SEFLG_HELCTR
+	equ := geo | SEFLG_EQUATORIAL | SEFLG_TRUEPOS | SEFLG_J2000
+	xyz := geo | SEFLG_XYZ | SEFLG_NOABERR
+	tests := []struct {
+		tjd   float64
+		ipl   int
+		iflag int32
+		want  [6]float64
+	}{
+		{2451545.0, 40, geo, [6]float64{243.9330260648, -0.0118796170, 41.7853148080, 0.0230761458, 0.0000017860, -0.0102986192}},
+		{2460000.5, 40, geo, [6]float64{276.3841150060, -0.0156636731, 41.4884272415, 0.0161866595, 0.0000041637, -0.0148072470}},
+		{2460000.5, 40, helio, [6]float64{275.1925063938, -0.0158508048, 40.9983700000, 0.0037786619, 0.0000088825, 0.0000000000}},
+		{2460000.5, 40, equ, [6]float64{276.6088303604, -23.3128998531, 41.4884406623, 0.0174882983, 0.0007313230, -0.0148435348}},
+		{2460000.5, 40, xyz, [6]float64{4.6153720040, -41.2309084010, -0.0113430377, 0.0099354829, 0.0160485481, 0.0000070648}},
+		{2451545.0, 41, geo, [6]float64{78.2461899330, 0.0132247758, 49.7552213675, -0.0158740336, -0.0000073213, 0.0064986883}},
+		{2451545.0, 42, geo, [6]float64{185.4356933566, -0.0021275721, 59.1216016833, 0.0008409777, -0.0000006310, -0.0174008110}},
+		{2451545.0, 43, geo, [6]float64{87.9310515143, 0.0132466050, 63.8563398592, -0.0133913074, -0.0000065406, 0.0036608178}},
+		{2451545.0, 44, geo, [6]float64{201.2072632665, -0.0055674192, 70.5398603592, 0.0044703850, 0.0000003285, -0.0171320388}},
+		{2451545.0, 45, geo, [6]float64{48.9757646142, 0.0107241226, 73.1190171335, -0.0070176049, -0.0000061306, 0.0135751928}},
+		{2451545.0, 46, geo, [6]float64{110.3258212086, 0.0119850128, 76.4771600772, -0.0113878291, -0.0000050306, -0.0031435854}},
+		{2451545.0, 47, geo, [6]float64{214.5508472037, -0.0081485140, 83.8916778678, 0.0063072567, 0.0000014329, -0.0158809824}},
+	}
+	defer SweSetEphePath("")
+	SweSetEphePath(t.TempDir())
+	// SweCalc uses the Neely elements
+	xneely, _, prov, _ := SweCalc(tests[0].tjd, tests[0].ipl, tests[0].iflag)
+	if prov.FictEl != SE_FICTEL_NEELY {
+		t.Errorf("SweCalc(%.1f, %d, %d): FictEl %d; want SE_FICTEL_NEELY", tests[0].tjd, tests[0].ipl,
+			tests[0].iflag, prov.FictEl)
+	}
+	for _, tt := range tests {
+		xx, _, prov, err := SweCalcFictEl(tt.tjd, tt.ipl, tt.iflag, SE_FICTEL_CLASSIC)
+		if err != nil || prov != (Provenance{Iephe: SEFLG_MOSEPH, Denum: 403, FictEl: SE_FICTEL_CLASSIC}) {
+			t.Errorf("SweCalcFictEl(%.1f, %d, %d): provenance %+v, error %v", tt.tjd, tt.ipl, tt.iflag, prov, err)
+			continue
+		}
+		for i := range xx {
+			tol := 1e-9
+			if i >= 3 {
+				tol = 1e-7
+			}
+			if math.Abs(xx[i]-tt.want[i]) > tol {
+				t.Errorf("SweCalcFictEl(%.1f, %d, %d) = %.10f; want %.10f", tt.tjd, tt.ipl, tt.iflag, xx, tt.want)
+				break
+			}
+		}
+	}
+	// the other fictitious bodies have the same elements in both sets
+	if _, _, prov, _ := SweCalcFictEl(2451545.0, SE_ISIS, geo, SE_FICTEL_CLASSIC); prov.FictEl != 0 {
+		t.Errorf("SweCalcFictEl(SE_ISIS): FictEl %d; want 0", prov.FictEl)
+	}
+	if _, iflgret, _, err := SweCalcFictEl(2451545.0, 40, geo, 3); iflgret != ERR || err == nil {
+		t.Errorf("SweCalcFictEl with elements 3: flags %d, error %v; want ERR", iflgret, err)
+	}
+	if err := SweCheckFictElements(3); err == nil {
+		t.Error("SweCheckFictElements(3): no error")
+	}
+	// the save area must not return the classic position
+	if xx, _, _, _ := SweCalc(tests[0].tjd, tests[0].ipl, tests[0].iflag); xx != xneely {
+		t.Errorf("SweCalc after SweCalcFictEl(SE_FICTEL_CLASSIC) = %.10f; want %.10f", xx, xneely)
+	}
+}
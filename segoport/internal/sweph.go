@@ -1,12 +1,15 @@
 package internal
 
 import (
+	"bufio"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -18,6 +21,706 @@ func dotProd(x, y [3]float64) float64 {
 	return x[0]*y[0] + x[1]*y[1] + x[2]*y[2]
 }
 
+// ===== 0080 ===== defines sweph.c-0080 =============================================================================
+
+const (
+	IS_PLANET        = 0
+	IS_MOON          = 1
+	IS_ANY_BODY      = 2
+	IS_MAIN_ASTEROID = 3
+
+	DO_SAVE = true
+	NO_SAVE = false
+)
+
+// ===== 0182 ===== pnoext2int sweph.c-0182 ==========================================================================
+
+// PNOEXT2INT converts the external planet numbers SE_SUN .. SE_VESTA to the internal numbers SEI_*.
+var PNOEXT2INT = []int{SEI_SUN, SEI_MOON, SEI_MERCURY, SEI_VENUS, SEI_MARS, SEI_JUPITER, SEI_SATURN, SEI_URANUS,
+	SEI_NEPTUNE, SEI_PLUTO, 0, 0, 0, 0, SEI_EARTH, SEI_CHIRON, SEI_PHOLUS, SEI_CERES, SEI_PALLAS, SEI_JUNO, SEI_VESTA}
+
+// ===== 0309 ===== swe_calc sweph.c-0309 ============================================================================
+
+// SweCalc computes the position of body ipl for the Julian Day tjd (TT).
+// ipl is SE_SUN .. SE_VESTA, SE_ECL_NUT, SE_AST_OFFSET + MPC number, or SE_PLMOON_OFFSET + planet * 100 + moon for
+// planetary moons, e.g. 9501 for Io; 9599 is the center of body of Jupiter, or SE_FICT_OFFSET + number of the body
+// for fictitious bodies from seorbel.txt or the built-in elements, e.g. 40 for Cupido, or SE_COMET_OFFSET + n for the
+// comets and asteroids of SweLoadMpcFile, numbered by SweFindMpcObject. iflag contains the flags SEFLG_*. With
+// SEFLG_CENTER_BODY, the center of body of Jupiter .. Pluto is computed instead of the barycenter of the planet
+// system.
+// Returns longitude, latitude and distance (or x, y and z with SEFLG_XYZ) and their speeds, the flags that were used,
+// the source of the position and an error. In case of an error the flags are ERR, otherwise the error is a warning
+// and the position is valid. If the files of the requested ephemeris are not available, the next ephemeris of the
+// chain is used, see SweSetEpheChain.
+// Port: tracing and the reminder to call swe_set_ephe_path() first are skipped. The provenance is added. The Uranian
+// planets without seorbel.txt have the elements of Neely, see SweCalcFictEl.
+func SweCalc(tjd float64, ipl int, iflag int32) ([6]float64, int32, Provenance, error) {
+	return SweCalcFictEl(tjd, ipl, iflag, SE_FICTEL_NEELY)
+}
+
+// SweCalcFictEl is SweCalc with the built-in elements fictEl of the Uranian planets Cupido .. Poseidon, which are
+// used if there is no seorbel.txt: SE_FICTEL_NEELY or SE_FICTEL_CLASSIC.
+// Port: not part of C, where the elements are chosen at compile time with SE_NEELY.
+func SweCalcFictEl(tjd float64, ipl int, iflag, fictEl int32) ([6]float64, int32, Provenance, error) {
+	var x [6]float64
+	var serr string
+	iplmoon := 0
+	iflgsave := iflag
+	useSpeed3 := false
+	returnError := func() ([6]float64, int32, Provenance, error) {
+		if serr == "" {
+			serr = fmt.Sprintf("error in computation of body %d", ipl)
+		}
+		return [6]float64{}, ERR, Provenance{}, calcError(serr)
+	}
+	if err := SweCheckFictElements(fictEl); err != nil {
+		return [6]float64{}, ERR, Provenance{}, err
+	}
+	// function calls for Pluto with asteroid number 134340 are treated as calls for Pluto as main body SE_PLUTO.
+	// Reason: Our numerical integrator takes into account Pluto perturbation and therefore crashes with body 134340
+	// Pluto.
+	if ipl == SE_AST_OFFSET+134340 {
+		ipl = SE_PLUTO
+	}
+	// if ephemeris flag != ephemeris flag of last call, we clear the save area, to prevent swecalc() using previously
+	// computed data for current calculation. except with ipl = SE_ECL_NUT which is not dependent on ephemeris, and
+	// except if change is from ephemeris = 0 to ephemeris = SEFLG_DEFAULTEPH or vice-versa.
+	epheflag := iflag & SEFLG_EPHMASK
+	if epheflag&SEFLG_SPKEPH != 0 {
+		epheflag = SEFLG_SPKEPH
+	} else if epheflag&SEFLG_MOSEPH != 0 {
+		epheflag = SEFLG_MOSEPH
+	} else if epheflag&SEFLG_JPLEPH != 0 {
+		epheflag = SEFLG_JPLEPH
+	} else {
+		epheflag = SEFLG_SWIEPH
+	}
+	swiInitSwedIfStart()
+	swed.FileErr = nil
+	if swed.LastEpheFlag != epheflag {
+		freePlanets()
+		// close and free ephemeris files
+		if ipl != SE_ECL_NUT { // because file will not be reopened with this ipl
+			if swed.JplFileIsOpen {
+				swiCloseJplFile()
+				swed.JplFileIsOpen = false
+			}
+			for i := 0; i < SEI_NEPHFILES; i++ {
+				if swed.Fidat[i].Fptr != nil {
+					swed.Fidat[i].Fptr.Close()
+				}
+				swed.Fidat[i] = FileData{}
+			}
+			swed.LastEpheFlag = epheflag
+		}
+	}
+	// high precision speed prevails fast speed
+	if iflag&SEFLG_SPEED3 != 0 && iflag&SEFLG_SPEED != 0 {
+		iflag = iflag &^ SEFLG_SPEED3
+	}
+	if iflag&SEFLG_SPEED3 != 0 {
+		useSpeed3 = true
+	}
+	// topocentric with SEFLG_SPEED is not good if aberration is included. in such cases we calculate speed from three
+	// positions
+	if iflag&SEFLG_SPEED != 0 && iflag&SEFLG_TOPOCTR != 0 && iflag&SEFLG_NOABERR == 0 {
+		useSpeed3 = true
+	}
+	// cartesian flag excludes radians flag
+	if iflag&SEFLG_XYZ != 0 && iflag&SEFLG_RADIANS != 0 {
+		iflag = iflag &^ SEFLG_RADIANS
+	}
+	// planetary center of body or planetary moon: either planet is called with SEFLG_CENTER_BODY or center of body
+	// with ipl = 9n99 is called. we want to handle both cases the same way.
+	if iflag&SEFLG_CENTER_BODY != 0 && ipl <= SE_PLUTO && iflag&SEFLG_TEST_PLMOON != SEFLG_TEST_PLMOON {
+		iplmoon = ipl*100 + 9099 // planetary center of body
+	}
+	// planet center of body or planetary moon is called using 9... number: moon number and planet number
+	if ipl >= SE_PLMOON_OFFSET && ipl < SE_AST_OFFSET && iflag&SEFLG_TEST_PLMOON != SEFLG_TEST_PLMOON {
+		iplmoon = ipl // planetary center of body or planetary moon
+		ipl = (ipl - 9000) / 100
+		iflag |= SEFLG_CENTER_BODY
+	}
+	// with Mercury to Mars, we do not have center of body different from barycenter
+	if iflag&SEFLG_CENTER_BODY != 0 && ipl <= SE_MARS && iplmoon%100 == 99 {
+		iplmoon = 0
+		iflag &^= SEFLG_CENTER_BODY
+	}
+	if iflag&SEFLG_CENTER_BODY != 0 || iplmoon > 0 {
+		swiForceAppPosEtc()
+	}
+	// pointer to save area
+	var sd *SavePositions
+	if ipl < SE_NPLANETS && ipl >= SE_SUN {
+		sd = &swed.Savedat[ipl]
+	} else {
+		// other bodies, e.g. asteroids called with ipl = SE_AST_OFFSET + MPC#
+		sd = &swed.Savedat[SE_NPLANETS]
+	}
+	// if position is available in save area, it is returned. this is the case, if tjd = tsave and iflag = iflgsave.
+	// coordinate flags can be neglected, because save area provides all coordinate types.
+	// if ipl > SE_AST(EROID)_OFFSET, ipl must be checked, because all asteroids called by MPC number share the same
+	// save area.
+	if sd.Tsave != tjd || tjd == 0 || ipl != sd.Ipl || iplmoon != 0 || sd.FictEl != fictEl ||
+		sd.Iflgsave&^SEFLG_COORDSYS != iflag&^SEFLG_COORDSYS {
+		// otherwise, new position must be computed
+		sd.Tsave = tjd
+		sd.Ipl = ipl
+		if !useSpeed3 {
+			// with high precision speed from one call of swecalc() (FAST speed)
+			if sd.Iflgsave = swecalc(tjd, ipl, iplmoon, iflag, fictEl, sd.Xsaves[:], &serr); sd.Iflgsave == ERR {
+				return returnError()
+			}
+		} else {
+			// with speed from three calls of swecalc(), slower and less accurate. (SLOW speed, for test only)
+			var x0, x2 [24]float64
+			var dt float64
+			switch ipl {
+			case SE_MOON:
+				dt = MOON_SPEED_INTV
+			case SE_OSCU_APOG, SE_TRUE_NODE:
+				// this is the optimum dt with Moshier ephemeris, but not with JPL ephemeris or SWISSEPH.
+				dt = NODE_CALC_INTV_MOSH
+			default:
+				dt = PLAN_SPEED_INTV
+			}
+			if sd.Iflgsave = swecalc(tjd-dt, ipl, iplmoon, iflag, fictEl, x0[:], &serr); sd.Iflgsave == ERR {
+				return returnError()
+			}
+			if sd.Iflgsave = swecalc(tjd+dt, ipl, iplmoon, iflag, fictEl, x2[:], &serr); sd.Iflgsave == ERR {
+				return returnError()
+			}
+			if sd.Iflgsave = swecalc(tjd, ipl, iplmoon, iflag, fictEl, sd.Xsaves[:], &serr); sd.Iflgsave == ERR {
+				return returnError()
+			}
+			denormalizePositions(x0[:], sd.Xsaves[:], x2[:])
+			calcSpeed(x0[:], sd.Xsaves[:], x2[:], dt)
+		}
+		sd.FictEl = fictEl
+		sd.Prov = calcProvenance(tjd, ipl, iplmoon, sd.Iflgsave, iflgsave, fictEl)
+		// Port: the note about the fallback is deleted when the file of the next ephemeris is opened, but it is kept here
+		if sd.Prov.Fallback && serr == "" {
+			serr = fallbackNote(sd.Prov.Iephe)
+		}
+	}
+	xs := sd.Xsaves[:] // ecliptic coordinates
+	if iflag&SEFLG_EQUATORIAL != 0 {
+		xs = sd.Xsaves[12:] // equatorial coordinates
+	}
+	if iflag&SEFLG_XYZ != 0 {
+		xs = xs[6:] // cartesian coordinates
+	}
+	n := 3
+	if ipl == SE_ECL_NUT {
+		n = 4
+	}
+	copy(x[:n], xs[:n])
+	if iflag&(SEFLG_SPEED3|SEFLG_SPEED) != 0 {
+		copy(x[3:], xs[3:6])
+	}
+	if iflag&SEFLG_RADIANS != 0 {
+		if ipl == SE_ECL_NUT {
+			for j := 0; j < 4; j++ {
+				x[j] *= DEGTORAD
+			}
+		} else {
+			for j := 0; j < 2; j++ {
+				x[j] *= DEGTORAD
+			}
+			if iflag&(SEFLG_SPEED3|SEFLG_SPEED) != 0 {
+				for j := 3; j < 5; j++ {
+					x[j] *= DEGTORAD
+				}
+			}
+		}
+	}
+	// iflag from previous call of swe_calc(), without coordinate system flags, add correct coordinate system flags
+	iflag = sd.Iflgsave&^SEFLG_COORDSYS | iflgsave&SEFLG_COORDSYS
+	// if no ephemeris has been specified, do not return chosen ephemeris
+	if iflgsave&SEFLG_EPHMASK == 0 {
+		iflag = iflag &^ SEFLG_DEFAULTEPH
+	}
+	if serr != "" {
+		return x, iflag, sd.Prov, calcError(serr)
+	}
+	return x, iflag, sd.Prov, nil
+}
+
+// calcError returns the error for the message serr of SweCalc. If an ephemeris file was damaged, the error is, or
+// wraps, the *CorruptFileError of that file.
+// Port: added.
+func calcError(serr string) error {
+	switch {
+	case swed.FileErr == nil:
+		return errors.New(serr)
+	case serr == swed.FileErr.Error():
+		return swed.FileErr
+	default:
+		return fmt.Errorf("%s: %w", serr, swed.FileErr)
+	}
+}
+
+// ===== 0565 ===== swe_calc_ut sweph.c-0565 =========================================================================
+
+// SweCalcUt is SweCalc for the Julian Day tjdUt in Universal Time.
+func SweCalcUt(tjdUt float64, ipl int, iflag int32) ([6]float64, int32, Provenance, error) {
+	return SweCalcUtFictEl(tjdUt, ipl, iflag, SE_FICTEL_NEELY)
+}
+
+// SweCalcUtFictEl is SweCalcFictEl for the Julian Day tjdUt in Universal Time.
+// Port: not part of C.
+func SweCalcUtFictEl(tjdUt float64, ipl int, iflag, fictEl int32) ([6]float64, int32, Provenance, error) {
+	iflag = plausIflag(iflag, int32(ipl), tjdUt, nil)
+	epheflag := iflag & SEFLG_EPHMASK
+	if epheflag == 0 {
+		epheflag = SEFLG_SWIEPH
+		iflag |= SEFLG_SWIEPH
+	}
+	deltat, _ := sweDeltatEx(tjdUt, iflag)
+	x, retval, prov, err := SweCalcFictEl(tjdUt+deltat, ipl, iflag, fictEl)
+	// if ephe required is not ephe returned, adjust delta t
+	if retval != ERR && retval&SEFLG_EPHMASK != epheflag {
+		deltat, _ = sweDeltatEx(tjdUt, retval)
+		x, retval, prov, err = SweCalcFictEl(tjdUt+deltat, ipl, iflag, fictEl)
+	}
+	return x, retval, prov, err
+}
+
+// ===== 0587 ===== swecalc sweph.c-0587 =============================================================================
+
+// swecalc computes body ipl (and the planetary moon or center of body iplmoon) and writes the position in all
+// coordinate systems to x (24 values, as in PlanData.Xreturn). Returns the flags that were used, or ERR.
+// Port: sidereal and topocentric positions return an error. fictEl are the built-in elements of the Uranian planets.
+func swecalc(tjd float64, ipl, iplmoon int, iflag, fictEl int32, x []float64, serr *string) int32 {
+	var xp []float64
+	var serr2 string
+	epheflag := int32(SEFLG_DEFAULTEPH)
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	returnError := func() int32 {
+		clear(x[:24])
+		return ERR
+	}
+	// iflag plausible?
+	iflag = plausIflag(iflag, int32(ipl), tjd, serr)
+	// which ephemeris is wanted, which is used?
+	if iflag&SEFLG_MOSEPH != 0 {
+		epheflag = SEFLG_MOSEPH
+	}
+	if iflag&SEFLG_SWIEPH != 0 {
+		epheflag = SEFLG_SWIEPH
+	}
+	if iflag&SEFLG_JPLEPH != 0 {
+		epheflag = SEFLG_JPLEPH
+	}
+	// Port: SPK kernels, see SweLoadSpkFile
+	if iflag&SEFLG_SPKEPH != 0 {
+		epheflag = SEFLG_SPKEPH
+	}
+	// no barycentric calculations with Moshier ephemeris
+	if iflag&SEFLG_BARYCTR != 0 && iflag&SEFLG_MOSEPH != 0 {
+		if serr != nil {
+			*serr = "barycentric Moshier positions are not supported."
+		}
+		return ERR
+	}
+	if epheflag != SEFLG_MOSEPH && !swed.EphePathIsSet && !swed.JplFileIsOpen {
+		SweSetEphePath("")
+	}
+	// Port: sidereal and topocentric positions are not yet supported
+	if iflag&(SEFLG_SIDEREAL|SEFLG_TOPOCTR) != 0 {
+		if serr != nil {
+			*serr = "sidereal and topocentric positions are not supported."
+		}
+		return ERR
+	}
+	// obliquity of ecliptic 2000 and of date
+	swiCheckEcliptic(tjd, iflag)
+	// nutation
+	swiCheckNutation(tjd, iflag)
+	// select planet and ephemeris
+	switch {
+	case ipl == SE_ECL_NUT:
+		// ecliptic and nutation
+		x[0] = swed.Oec.Eps + swed.Nut.Nutlo[1] // true ecliptic
+		x[1] = swed.Oec.Eps                     // mean ecliptic
+		x[2] = swed.Nut.Nutlo[0]                // nutation in longitude
+		x[3] = swed.Nut.Nutlo[1]                // nutation in obliquity
+		for i := 0; i <= 3; i++ {
+			x[i] *= RADTODEG
+		}
+		return iflag
+	case ipl == SE_MOON:
+		pdp := &swed.Pldat[SEI_MOON]
+		xp = pdp.Xreturn[:]
+		for {
+			var retc int
+			switch epheflag {
+			case SEFLG_SWIEPH:
+				retc = sweplan(tjd, SEI_MOON, SEI_FILE_MOON, iflag, DO_SAVE, nil, nil, nil, nil, serr)
+				if retc == ERR {
+					return returnError()
+				}
+			case SEFLG_MOSEPH:
+				if swiMoshmoon(tjd, DO_SAVE, nil, serr) == ERR {
+					return returnError()
+				}
+				// for hel. position, we need earth as well
+				if swiMoshplan(tjd, SEI_EARTH, DO_SAVE, nil, nil, serr) == ERR {
+					return returnError()
+				}
+			case SEFLG_JPLEPH:
+				retc = jplplan(tjd, SEI_MOON, iflag, DO_SAVE, nil, nil, nil, serr)
+				// read error or corrupt file
+				if retc == ERR {
+					return returnError()
+				}
+				// jpl ephemeris not on disk or date beyond ephemeris range
+				if retc == BEYOND_EPH_LIMITS {
+					retc = NOT_AVAILABLE
+				}
+			case SEFLG_SPKEPH:
+				if retc = spkplan(tjd, SEI_MOON, iflag, DO_SAVE, nil, nil, nil, serr); retc == ERR {
+					return returnError()
+				}
+			}
+			// if the ephemeris is not available, switch to the next one of the chain
+			if retc == NOT_AVAILABLE {
+				if iflag = epheFallback(iflag, serr); iflag == 0 {
+					return returnError()
+				}
+				epheflag = iflag & SEFLG_EPHMASK
+				continue
+			}
+			break
+		}
+		// heliocentric, lighttime etc.
+		if appPosEtcMoon(iflag, serr) != OK {
+			return returnError()
+		}
+	case ipl == SE_SUN && iflag&SEFLG_BARYCTR != 0:
+		// barycentric sun must be handled separately, because the internal planet numbers of the barycentric sun and
+		// of the barycentric earth are the same: SEI_EARTH = SEI_SUN = 0.
+		xp = pedp.Xreturn[:]
+		// sweplan() provides barycentric sun as a by-product in save area; it is saved in swed.Pldat[SEI_SUNBARY].X
+		for {
+			var retc int
+			switch epheflag {
+			case SEFLG_SWIEPH:
+				retc = sweplan(tjd, SEI_EARTH, SEI_FILE_PLANET, iflag, DO_SAVE, nil, nil, nil, nil, serr)
+				// there is no barycentric Moshier ephemeris
+				if retc == NOT_AVAILABLE && nextEphe(epheflag) == SEFLG_MOSEPH {
+					return returnError()
+				}
+			case SEFLG_JPLEPH:
+				// open ephemeris, if still closed
+				var ss [3]float64
+				if !swed.JplFileIsOpen {
+					retc = openJplFile(ss[:], swed.JplFnam, swed.EphePath, serr)
+				}
+				if retc == OK {
+					retc = swiPleph(tjd, J_SUN, J_SBARY, psdp.X[:], serr)
+					if retc == ERR || retc == BEYOND_EPH_LIMITS {
+						swiCloseJplFile()
+						swed.JplFileIsOpen = false
+						return returnError()
+					}
+				}
+			case SEFLG_SPKEPH:
+				retc = spkplan(tjd, SEI_SUNBARY, iflag, NO_SAVE, psdp.X[:], nil, nil, serr)
+			default:
+				retc = notSupportedEphe(epheflag, serr)
+			}
+			if retc == ERR {
+				return returnError()
+			}
+			// if the ephemeris is not available, switch to the next one of the chain
+			if retc == NOT_AVAILABLE {
+				if iflag = epheFallback(iflag, serr); iflag == 0 {
+					return returnError()
+				}
+				epheflag = iflag & SEFLG_EPHMASK
+				continue
+			}
+			break
+		}
+		psdp.Teval = tjd
+		// flags
+		if appPosEtcSbar(iflag, serr) != OK {
+			return returnError()
+		}
+		// iflag has possibly changed
+		iflag = pedp.Xflgs
+		// barycentric sun is now in save area of barycentric earth. in case a barycentric earth computation follows
+		// for the same date, the planetary functions will return the barycentric SUN unless we force a new
+		// computation of pedp.Xreturn. this can be done by initializing the save of iflag.
+		pedp.Xflgs = -1
+	case ipl >= SE_SUN && ipl <= SE_PLUTO || ipl == SE_EARTH:
+		// main planet, mercury - pluto
+		if iflag&SEFLG_HELCTR != 0 {
+			if ipl == SE_SUN {
+				// heliocentric position of Sun does not exist
+				clear(x[:24])
+				return iflag
+			}
+		} else if iflag&SEFLG_BARYCTR == 0 && ipl == SE_EARTH {
+			// geocentric position of Earth does not exist
+			clear(x[:24])
+			return iflag
+		}
+		// internal planet number
+		ipli := PNOEXT2INT[ipl]
+		pdp := &swed.Pldat[ipli]
+		xp = pdp.Xreturn[:]
+		if mainPlanet(tjd, ipli, iplmoon, epheflag, iflag, serr) == ERR {
+			return returnError()
+		}
+		// iflag has possibly changed in mainPlanet()
+		iflag = pdp.Xflgs
+	case ipl == SE_MEAN_NODE:
+		// mean lunar node, for comment s. swiMeanNode()
+		if iflag&(SEFLG_HELCTR|SEFLG_BARYCTR) != 0 {
+			// heliocentric/barycentric lunar node not allowed
+			clear(x[:24])
+			return iflag
+		}
+		ndp := &swed.Nddat[SEI_MEAN_NODE]
+		xp = ndp.Xreturn[:]
+		xp2 := ndp.X[:]
+		if swiMeanNode(tjd, xp2, serr) == ERR {
+			return returnError()
+		}
+		// speed (is almost constant; variation < 0.001 arcsec)
+		if swiMeanNode(tjd-MEAN_NODE_SPEED_INTV, xp2[3:], serr) == ERR {
+			return returnError()
+		}
+		xp2[3] = SweDifrad2n(xp2[0], xp2[3]) / MEAN_NODE_SPEED_INTV
+		xp2[4], xp2[5] = 0, 0
+		ndp.Teval = tjd
+		ndp.Xflgs = -1
+		// lighttime etc.
+		if appPosEtcMean(SEI_MEAN_NODE, iflag, serr) != OK {
+			return returnError()
+		}
+		// to avoid infinitesimal deviations from latitude = 0 that result from conversions
+		if iflag&SEFLG_SIDEREAL == 0 && iflag&SEFLG_J2000 == 0 {
+			ndp.Xreturn[1] = 0.0  // ecl. latitude
+			ndp.Xreturn[4] = 0.0  //               speed
+			ndp.Xreturn[5] = 0.0  //      radial   speed
+			ndp.Xreturn[8] = 0.0  // z coordinate
+			ndp.Xreturn[11] = 0.0 //               speed
+		}
+	case ipl == SE_MEAN_APOG:
+		// mean lunar apogee ('dark moon', 'lilith'), for comment s. swiMeanApog()
+		if iflag&(SEFLG_HELCTR|SEFLG_BARYCTR) != 0 {
+			// heliocentric/barycentric lunar apogee not allowed
+			clear(x[:24])
+			return iflag
+		}
+		ndp := &swed.Nddat[SEI_MEAN_APOG]
+		xp = ndp.Xreturn[:]
+		xp2 := ndp.X[:]
+		if swiMeanApog(tjd, xp2, serr) == ERR {
+			return returnError()
+		}
+		// speed (is not constant! variation ~= several arcsec)
+		if swiMeanApog(tjd-MEAN_NODE_SPEED_INTV, xp2[3:], serr) == ERR {
+			return returnError()
+		}
+		for i := 0; i <= 1; i++ {
+			xp2[3+i] = SweDifrad2n(xp2[i], xp2[3+i]) / MEAN_NODE_SPEED_INTV
+		}
+		xp2[5] = 0
+		ndp.Teval = tjd
+		ndp.Xflgs = -1
+		// lighttime etc.
+		if appPosEtcMean(SEI_MEAN_APOG, iflag, serr) != OK {
+			return returnError()
+		}
+		// to avoid infinitesimal deviations from r-speed = 0 that result from conversions
+		ndp.Xreturn[5] = 0.0 // speed
+	case ipl == SE_TRUE_NODE:
+		// osculating lunar node ('true node')
+		if iflag&(SEFLG_HELCTR|SEFLG_BARYCTR) != 0 {
+			// heliocentric/barycentric lunar node not allowed
+			clear(x[:24])
+			return iflag
+		}
+		ndp := &swed.Nddat[SEI_TRUE_NODE]
+		xp = ndp.Xreturn[:]
+		retc := lunarOscElem(tjd, SEI_TRUE_NODE, iflag, serr)
+		iflag = ndp.Xflgs
+		// to avoid infinitesimal deviations from latitude = 0 that result from conversions
+		if iflag&SEFLG_SIDEREAL == 0 && iflag&SEFLG_J2000 == 0 {
+			ndp.Xreturn[1] = 0.0  // ecl. latitude
+			ndp.Xreturn[4] = 0.0  //               speed
+			ndp.Xreturn[8] = 0.0  // z coordinate
+			ndp.Xreturn[11] = 0.0 //               speed
+		}
+		if retc == ERR {
+			return returnError()
+		}
+	case ipl == SE_OSCU_APOG:
+		// osculating lunar apogee
+		if iflag&(SEFLG_HELCTR|SEFLG_BARYCTR) != 0 {
+			// heliocentric/barycentric lunar apogee not allowed
+			clear(x[:24])
+			return iflag
+		}
+		ndp := &swed.Nddat[SEI_OSCU_APOG]
+		xp = ndp.Xreturn[:]
+		retc := lunarOscElem(tjd, SEI_OSCU_APOG, iflag, serr)
+		iflag = ndp.Xflgs
+		if retc == ERR {
+			return returnError()
+		}
+	case ipl == SE_INTP_APOG || ipl == SE_INTP_PERG:
+		// interpolated lunar apogee and perigee
+		if iflag&(SEFLG_HELCTR|SEFLG_BARYCTR) != 0 {
+			// heliocentric/barycentric lunar apogee not allowed
+			clear(x[:24])
+			return iflag
+		}
+		if tjd < MOSHLUEPH_START || tjd > MOSHLUEPH_END {
+			if serr != nil {
+				*serr = fmt.Sprintf("Interpolated apsides are restricted to JD %8.1f - JD %8.1f", MOSHLUEPH_START,
+					MOSHLUEPH_END)
+			}
+			return returnError()
+		}
+		ipli := SEI_INTP_APOG
+		if ipl == SE_INTP_PERG {
+			ipli = SEI_INTP_PERG
+		}
+		ndp := &swed.Nddat[ipli]
+		xp = ndp.Xreturn[:]
+		retc := intpApsides(tjd, ipli, iflag, serr)
+		iflag = ndp.Xflgs
+		if retc == ERR {
+			return returnError()
+		}
+	case ipl >= SE_FICT_OFFSET && ipl <= SE_FICT_MAX || ipl >= SE_COMET_OFFSET && ipl < SE_PLMOON_OFFSET:
+		// fictitious planets (Isis-Transpluto and Uranian planets); Port: and comets and asteroids from MPC files
+		// internal planet number
+		ipli := SEI_ANYBODY
+		pdp := &swed.Pldat[ipli]
+		xp = pdp.Xreturn[:]
+		for {
+			// the earth for geocentric position
+			retc := mainPlanet(tjd, SEI_EARTH, 0, epheflag, iflag, serr)
+			// iflag (ephemeris bit) has possibly changed in mainPlanet()
+			iflag = swed.Pldat[SEI_EARTH].Xflgs
+			// planet from osculating elements
+			if oscElPlan(tjd, pdp.X[:], ipl, ipli, pedp.X[:], psdp.X[:], fictEl, serr) != OK {
+				return returnError()
+			}
+			if retc == ERR {
+				return returnError()
+			}
+			retc = appPosEtcPlanOsc(ipl, ipli, iflag, fictEl, serr)
+			if retc == ERR {
+				return returnError()
+			}
+			// appPosEtcPlanOsc() might have failed, if t(light-time) is beyond ephemeris range. in this case redo
+			// with the next ephemeris of the chain (C: with Moshier)
+			if retc == NOT_AVAILABLE || retc == BEYOND_EPH_LIMITS {
+				if iflag = epheFallback(iflag, serr); iflag == 0 {
+					return returnError()
+				}
+				epheflag = iflag & SEFLG_EPHMASK
+				continue
+			}
+			break
+		}
+	case ipl >= SE_CHIRON && ipl <= SE_VESTA || ipl > SE_PLMOON_OFFSET:
+		// minor planets, planetary moons
+		var ipli int
+		// internal planet number
+		if ipl < SE_NPLANETS {
+			ipli = PNOEXT2INT[ipl]
+		} else if ipl <= SE_AST_OFFSET+MPC_VESTA && ipl > SE_AST_OFFSET {
+			ipli = SEI_CERES + ipl - SE_AST_OFFSET - 1
+			ipl = SE_CERES + ipl - SE_AST_OFFSET - 1
+		} else { // any asteroid except
+			ipli = SEI_ANYBODY
+		}
+		ipliAst := ipli
+		if ipli == SEI_ANYBODY {
+			ipliAst = ipl
+		}
+		pdp := &swed.Pldat[ipli]
+		xp = pdp.Xreturn[:]
+		ifno := SEI_FILE_MAIN_AST
+		if ipliAst > SE_PLMOON_OFFSET {
+			ifno = SEI_FILE_ANY_AST
+		}
+		if ipli == SEI_CHIRON && (tjd < CHIRON_START || tjd > CHIRON_END) {
+			if serr != nil {
+				*serr = fmt.Sprintf("Chiron's ephemeris is restricted to JD %8.1f - JD %8.1f", CHIRON_START,
+					CHIRON_END)
+			}
+			return ERR
+		}
+		if ipli == SEI_PHOLUS && (tjd < PHOLUS_START || tjd > PHOLUS_END) {
+			if serr != nil {
+				*serr = fmt.Sprintf("Pholus's ephemeris is restricted to JD %8.1f - JD %8.1f", PHOLUS_START,
+					PHOLUS_END)
+			}
+			return ERR
+		}
+		for {
+			// earth and sun are also needed
+			if mainPlanet(tjd, SEI_EARTH, 0, epheflag, iflag, serr) == ERR {
+				return returnError()
+			}
+			// iflag (ephemeris bit) has possibly changed in mainPlanet()
+			iflag = swed.Pldat[SEI_EARTH].Xflgs
+			if iflag&SEFLG_SPKEPH == 0 {
+				break
+			}
+			// Port: asteroid from the SPK kernels, or from the next ephemeris of the chain
+			retc := spkplan(tjd, ipliAst, iflag, DO_SAVE, nil, nil, nil, serr)
+			if retc == ERR {
+				return returnError()
+			}
+			if retc == OK {
+				break
+			}
+			if iflag = epheFallback(iflag, serr); iflag == 0 {
+				return returnError()
+			}
+			epheflag = iflag & SEFLG_EPHMASK
+		}
+		if serr != nil {
+			serr2 = *serr
+			*serr = ""
+		}
+		// asteroid
+		if iflag&SEFLG_SPKEPH == 0 {
+			if retc := sweph(tjd, ipliAst, ifno, iflag, psdp.X[:], DO_SAVE, nil, serr); retc == ERR ||
+				retc == NOT_AVAILABLE {
+				return returnError()
+			}
+		}
+		// Port: if the position for t(light-time) is beyond the file range, C redoes the computation with Moshier.
+		if appPosEtcPlan(ipliAst, 0, iflag, serr) != OK {
+			return returnError()
+		}
+		// add warnings from earth/sun computation
+		if serr != nil && *serr == "" && serr2 != "" {
+			*serr = "sun: " + serr2
+		}
+	default:
+		// invalid body number
+		if serr != nil {
+			*serr = fmt.Sprintf("illegal planet number %d.", ipl)
+		}
+		return returnError()
+	}
+	copy(x[:24], xp)
+	return iflag
+}
+
 // free_planets sweph.c-1158
 func freePlanets() {
 	// Free planets data space
@@ -44,7 +747,10 @@ func freePlanets() {
 func swiInitSwedIfStart() int32 {
 	if !swed.SwedIsInitialised {
 		swed.EphePath = SE_EPHE_PATH
-		// Port: skipped JPL file
+		swed.JplFnam = SE_FNAME_DFT
+		if swed.AstroModels == nil {
+			swed.AstroModels = make([]int32, SEI_NMODELS)
+		}
 		sweSetTidAcc(SE_TIDAL_AUTOMATIC)
 		swed.SwedIsInitialised = true
 		return 1
@@ -64,6 +770,11 @@ func swiCloseKeepTopoEtc() {
 		swed.Fidat[i] = FileData{} // Reset to zero value
 	}
 
+	// Close JPL file
+	swiCloseJplFile()
+	swed.JplFileIsOpen = false
+	swed.JplDenum = 0
+
 	freePlanets() // Using our previously translated function
 
 	// Reset various structures to zero values
@@ -112,7 +823,13 @@ func SweClose() {
 	swed.Nutv = Nut{}
 	swed.AstroModels = make([]int32, SEI_NMODELS)
 
-	// Port: removed part that closes JPL files
+	// close JPL file
+	swiCloseJplFile()
+	swed.JplFileIsOpen = false
+	swed.JplDenum = 0
+
+	// Port: unload SPK kernels
+	spkCloseFiles()
 
 	// Close fixed stars file
 	if swed.FixFp != nil {
@@ -132,12 +849,13 @@ func SweClose() {
 	swed.LastEpheFlag = 0
 	swed.Dpsi = nil
 	swed.Deps = nil
+	swed.EopDpsiLoaded = 0 // Port: dpsi and deps are read again after closing
 
-	if swed.NFixstarsRecords {
+	if swed.NFixstarsRecords > 0 {
 		swed.FixedStars = nil
-		swed.NFixstarsReal = false
-		swed.NFixstarsNamed = false
-		swed.NFixstarsRecords = false
+		swed.NFixstarsReal = 0
+		swed.NFixstarsNamed = 0
+		swed.NFixstarsRecords = 0
 	}
 }
 
@@ -166,22 +884,198 @@ func SweSetEphePath(path string) {
 		s += string(os.PathSeparator)
 	}
 	swed.EphePath = s
+	// Port: dpsi and deps are read again from the new path if needed, as well as the index of asteroid files and the
+	// fixed stars
+	swed.EopDpsiLoaded = 0
+	swed.AstIndex = nil
+	swed.FixedStars = nil
+	swed.NFixstarsReal = 0
+	swed.NFixstarsNamed = 0
+	swed.NFixstarsRecords = 0
 
 	// Try to open lunar ephemeris to get DE number and set tidal acceleration
-	// TODO enable iflag if SweCalc is enabled again
-	//iflag := int32(SEFLG_SWIEPH | SEFLG_J2000 | SEFLG_TRUEPOS | SEFLG_ICRS)
+	iflag := int32(SEFLG_SWIEPH | SEFLG_J2000 | SEFLG_TRUEPOS | SEFLG_ICRS)
 	swed.LastEpheFlag = 2
+	_, _, _, _ = SweCalc(J2000, SE_MOON, iflag)
+	if swed.Fidat[SEI_FILE_MOON].Fptr != nil {
+		swiSetTidAcc(0, 0, swed.Fidat[SEI_FILE_MOON].SwephDenum)
+	}
 
-	// TODO enable xx if SweCalc is enabled again
-	// var xx []float64 // TODO check if slice is OK, originally array with length 6
+}
 
-	// TODO enable SweCalc  . Check why this call is necessary.
-	//	SweCalc(J2000, SE_MOON, iflag, xx, nil)
+// ===== 1379 ===== load_dpsi_deps sweph.c-1379 ======================================================================
 
-	if swed.Fidat[SEI_FILE_MOON].Fptr != nil {
-		swiSetTidAcc(0, 0, swed.Fidat[SEI_FILE_MOON].SwephDenum)
+// loadDpsiDeps reads the celestial pole offsets dpsi and deps (IAU 1980) from the IERS files eop_1962_today.txt and
+// eop_finals.txt in the ephemeris path. They are used to reproduce JPL Horizons with SEFLG_JPLHOR.
+// The result is indicated in swed.EopDpsiLoaded:
+// 1 or 2 = loaded (2 if also eop_finals.txt was read), -1 = eop_1962_today.txt not found, -2 = eop_1962_today.txt
+// corrupt, -3 = eop_finals.txt corrupt (data from eop_1962_today.txt are available, however).
+// Port: the files are read only once, also if they are missing or corrupt. SweSetEphePath and SweSetJplFile reset
+// swed.EopDpsiLoaded to 0, so that they are read again on the next call.
+func loadDpsiDeps() {
+	const TJDOFS = 2400000.5
+	var n, mjd, mjdsv int
+	if swed.EopDpsiLoaded != 0 {
+		return
+	}
+	fp, err := SwiFopen(-1, DPSI_DEPS_IAU1980_FILE_EOPC04, swed.EphePath)
+	if err != nil {
+		swed.EopDpsiLoaded = ERR
+		return
+	}
+	swed.Dpsi = make([]float64, SWE_DATA_DPSI_DEPS)
+	swed.Deps = make([]float64, SWE_DATA_DPSI_DEPS)
+	swed.EopTjdBegHorizons = DPSI_DEPS_IAU1980_TJD0_HORIZONS
+	cpos := make([]string, 16)
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		swiCutstr(strings.TrimLeft(scanner.Text(), " "), " ", cpos, 16)
+		if atoi(cpos[0]) == 0 {
+			continue
+		}
+		mjd = atoi(cpos[3])
+		// is file in one-day steps?
+		if (mjdsv > 0 && mjd-mjdsv != 1) || n >= SWE_DATA_DPSI_DEPS {
+			// we cannot return error but we note it as follows:
+			swed.EopDpsiLoaded = -2
+			fp.Close()
+			return
+		}
+		if n == 0 {
+			swed.EopTjdBeg = float64(mjd) + TJDOFS
+		}
+		swed.Dpsi[n] = atof(cpos[8])
+		swed.Deps[n] = atof(cpos[9])
+		n++
+		mjdsv = mjd
+	}
+	fp.Close()
+	if n == 0 {
+		swed.EopDpsiLoaded = -2
+		return
+	}
+	swed.EopTjdEnd = float64(mjd) + TJDOFS
+	swed.EopDpsiLoaded = 1
+	// file finals.all may have some more data, and especially estimations for the near future
+	fp, err = SwiFopen(-1, DPSI_DEPS_IAU1980_FILE_FINALS, swed.EphePath)
+	if err != nil {
+		return // return without error as existence of file is not mandatory
+	}
+	defer fp.Close()
+	scanner = bufio.NewScanner(fp)
+	for scanner.Scan() {
+		s := scanner.Text()
+		if len(s) < 8 {
+			continue
+		}
+		mjd = atoi(s[7:])
+		if float64(mjd)+TJDOFS <= swed.EopTjdEnd {
+			continue
+		}
+		if n >= SWE_DATA_DPSI_DEPS {
+			return
+		}
+		// are data in one-day steps?
+		if mjdsv > 0 && mjd-mjdsv != 1 {
+			// no error, as we do have data; however, if this file is usefull, then swed.EopDpsiLoaded will be set to 2
+			swed.EopDpsiLoaded = -3
+			return
+		}
+		// dpsi, deps Bulletin B
+		dpsi := atofAt(s, 168)
+		deps := atofAt(s, 178)
+		if dpsi == 0 {
+			// try dpsi, deps Bulletin A
+			dpsi = atofAt(s, 99)
+			deps = atofAt(s, 118)
+		}
+		if dpsi == 0 {
+			swed.EopDpsiLoaded = 2
+			return
+		}
+		swed.EopTjdEnd = float64(mjd) + TJDOFS
+		swed.Dpsi[n] = dpsi / 1000.0
+		swed.Deps[n] = deps / 1000.0
+		n++
+		mjdsv = mjd
+	}
+	swed.EopDpsiLoaded = 2
+}
+
+// atofAt returns atof of the part of s that starts at position pos, or 0 if s is shorter.
+func atofAt(s string, pos int) float64 {
+	if pos >= len(s) {
+		return 0
+	}
+	return atof(s[pos:])
+}
+
+// swiCheckJplhor checks if SEFLG_JPLHOR can be used for tjd: the files with dpsi and deps are loaded if they have not
+// been tried yet.
+// If they are not available or if they do not cover tjd, SEFLG_JPLHOR is replaced with SEFLG_JPLHOR_APPROX and an
+// error that explains the reason is returned together with the changed flags.
+// Dates before 1962 are treated as by JPL Horizons, with the first values of the file.
+// Port: separated from plaus_iflag, the check for the range of dates has been added.
+func swiCheckJplhor(iflag int32, tjd float64) (int32, error) {
+	var err error
+	if iflag&SEFLG_JPLHOR == 0 {
+		return iflag, nil
+	}
+	swiInitSwedIfStart()
+	loadDpsiDeps()
+	switch swed.EopDpsiLoaded {
+	case 0:
+		err = errors.New("dpsi and deps not loaded; default to SEFLG_JPLHOR_APPROX")
+	case -1:
+		err = fmt.Errorf("file %s not found; default to SEFLG_JPLHOR_APPROX", DPSI_DEPS_IAU1980_FILE_EOPC04)
+	case -2:
+		err = fmt.Errorf("file %s corrupt; default to SEFLG_JPLHOR_APPROX", DPSI_DEPS_IAU1980_FILE_EOPC04)
+	case -3:
+		err = fmt.Errorf("file %s corrupt; default to SEFLG_JPLHOR_APPROX", DPSI_DEPS_IAU1980_FILE_FINALS)
+	default:
+		if tjd > swed.EopTjdEnd {
+			err = fmt.Errorf("jd %f beyond end of dpsi and deps data (jd %f); default to SEFLG_JPLHOR_APPROX", tjd,
+				swed.EopTjdEnd)
+		}
+	}
+	if err != nil {
+		iflag &^= SEFLG_JPLHOR
+		iflag |= SEFLG_JPLHOR_APPROX
+		return iflag, err
 	}
+	// SEFLG_JPLHOR requires SEFLG_ICRS, if calculated with precession/nutation IAU 1980 and corrections dpsi, deps
+	return iflag | SEFLG_ICRS, nil
+}
+
+// ===== 1474 ===== swe_set_jpl_file sweph.c-1474 ====================================================================
 
+// SweSetJplFile sets the name of the JPL ephemeris file (default de431.eph). A directory in fname is ignored, the file
+// is searched in the ephemeris path. Also closes all open files and deletes all planetary data, as SweSetEphePath.
+// Port: returns an error if the file cannot be opened; SEFLG_JPLEPH then falls back to the next ephemeris of the chain.
+func SweSetJplFile(fname string) error {
+	var ss [3]float64
+	var serr string
+	// close all open files and delete all planetary data
+	swiCloseKeepTopoEtc()
+	swiInitSwedIfStart()
+	// if path is contained in fname, it is filled into the path variable
+	if len(fname) >= AS_MAXCH {
+		fname = fname[:AS_MAXCH-1]
+	}
+	if i := strings.LastIndex(fname, DIR_GLUE); i >= 0 {
+		fname = fname[i+1:]
+	}
+	swed.JplFnam = fname
+	// Port: dpsi and deps are read again for the new file
+	swed.EopDpsiLoaded = 0
+	// open ephemeris
+	if openJplFile(ss[:], swed.JplFnam, swed.EphePath, &serr) != OK {
+		return errors.New(serr)
+	}
+	if swed.JplDenum >= 403 {
+		loadDpsiDeps()
+	}
+	return nil
 }
 
 // ===== 1530 ========== calc_epsilon sweph.c-1530 ===================================================================
@@ -195,57 +1089,668 @@ func calcEpsilon(tjd float64, iflag int32, e *Epsilon) {
 	e.Ceps = math.Cos(e.Eps)
 }
 
-// ----- 2359 ===========  swi_fopen sweph.c-2359 ====================================================================
-// TODO Port: changed this code a lot, do need to check, maybe compare with C# version
-func SwiFopen(ifno int, fname, ephepath string) (*os.File, error) {
-	// var fnamp string
-	var err error
-	var cpos []string
-	//if ifno >= 0 {
-	//	fnamp = swed.Fidat[ifno].Fnam
-	//} else {
-	//	fnamp = ""
-	//}
-
-	// Split path using PATH_SEPARATOR
-	//paths := strings.Split(ephepath, PATH_SEPARATOR)
-	swiCutstr(ephepath, PATH_SEPARATOR, cpos, 20)
-	for _, path := range cpos {
-		s := path
+// ===== 1561 ===== main_planet sweph.c-1561 =========================================================================
 
-		// Handle current directory case
-		if s == "." {
-			s = ""
-		} else if s != "" && !strings.HasSuffix(s, DIR_GLUE) {
-			s += DIR_GLUE
+// mainPlanet computes the main planet ipli (internal planet number) and converts it to an apparent position.
+// With SEFLG_CENTER_BODY, the offset of the center of body or planetary moon iplmoon from the barycenter of the
+// planet system is read first.
+// If the files of the ephemeris are not available, the next ephemeris of the chain is tried, see SweSetEpheChain.
+// Port: C falls back from the JPL ephemeris to the Moshier ephemeris if the date is beyond the range of the JPL file;
+// here the chain is used in this case as well.
+func mainPlanet(tjd float64, ipli, iplmoon int, epheflag, iflag int32, serr *string) int {
+	if iflag&SEFLG_CENTER_BODY != 0 && ipli >= SEI_MARS && ipli <= SEI_PLUTO {
+		// jupiter center of body, relative to jupiter barycenter
+		if retc := sweph(tjd, iplmoon, SEI_FILE_ANY_AST, iflag, nil, DO_SAVE, nil, serr); retc == ERR ||
+			retc == NOT_AVAILABLE {
+			return ERR
 		}
-
-		// Check path length
-		if len(s)+len(fname) >= AS_MAXCH {
-			return nil, fmt.Errorf("error: file path and name must be shorter than %d", AS_MAXCH)
+	}
+	// geocentric, lighttime etc.
+	appPosEtc := func() int {
+		if ipli == SEI_SUN {
+			return appPosEtcSun(iflag, serr)
 		}
-		fullPath := filepath.Join(s, fname)
-		// Store the full path if ifno >= 0
-		//if ifno >= 0 {
-		//	fnamp = fullPath
-		//}
-		// Try to open the file
-		fp, err := os.Open(fullPath)
-		if err == nil {
-			return fp, nil
+		return appPosEtcPlan(ipli, iplmoon, iflag, serr)
+	}
+	for {
+		var retc int
+		switch epheflag {
+		case SEFLG_JPLEPH:
+			if retc = jplplan(tjd, ipli, iflag, DO_SAVE, nil, nil, nil, serr); retc == OK {
+				// the time for light-time may be beyond the ephemeris range
+				retc = appPosEtc()
+			}
+			// read error or corrupt file
+			if retc == ERR {
+				return ERR
+			}
+			if retc == OK {
+				return OK
+			}
+			// jpl ephemeris not on disk or date beyond ephemeris range
+			retc = NOT_AVAILABLE
+		case SEFLG_SPKEPH:
+			if retc = spkplan(tjd, ipli, iflag, DO_SAVE, nil, nil, nil, serr); retc == OK {
+				// the time for light-time may be beyond the range of the kernels
+				retc = appPosEtc()
+			}
+			if retc == ERR || retc == OK {
+				return retc
+			}
+		case SEFLG_SWIEPH:
+			// compute barycentric planet (+ earth, sun, moon)
+			if retc = sweplan(tjd, ipli, SEI_FILE_PLANET, iflag, DO_SAVE, nil, nil, nil, nil, serr); retc == ERR {
+				return ERR
+			}
+		case SEFLG_MOSEPH:
+			if retc = swiMoshplan(tjd, ipli, DO_SAVE, nil, nil, serr); retc == ERR {
+				return ERR
+			}
+		}
+		// if the ephemeris is not available, switch to the next one of the chain
+		if retc == NOT_AVAILABLE {
+			if iflag = epheFallback(iflag, serr); iflag == 0 {
+				return ERR
+			}
+			epheflag = iflag & SEFLG_EPHMASK
+			continue
 		}
+		break
 	}
+	if appPosEtc() != OK {
+		return ERR
+	}
+	return OK
+}
 
-	// File not found in any path
-	err = fmt.Errorf("SwissEph file '%s' not found in PATH '%s'", fname, ephepath)
-	return nil, err
+// ===== 1696 ===== main_planet_bary sweph.c-1696 ====================================================================
+
+// mainPlanetBary computes the barycentric (heliocentric with Moshier) positions of a planet, the earth and the sun
+// and, with the Swiss Ephemeris, the geocentric moon, without light-time etc.
+// tjd		julian day
+// ipli		internal planet number
+// doSave	write new positions in save area swed.Pldat
+// xp, xe, xs, xm	planet, earth, sun and moon; the planet may be the same slice as the earth
+// Port: if the files are not available, the next ephemeris of the chain is used (see SweSetEpheChain); C falls back
+// from JPL to the Swiss Ephemeris to Moshier.
+func mainPlanetBary(tjd float64, ipli int, epheflag, iflag int32, doSave bool, xp, xe, xs, xm []float64,
+	serr *string) int {
+	for {
+		var retc int
+		switch epheflag {
+		case SEFLG_JPLEPH:
+			// read error or corrupt file
+			if retc = jplplan(tjd, ipli, iflag, doSave, xp, xe, xs, serr); retc == ERR || retc == BEYOND_EPH_LIMITS {
+				return retc
+			}
+		case SEFLG_SPKEPH:
+			if retc = spkplan(tjd, ipli, iflag, doSave, xp, xe, xs, serr); retc == ERR {
+				return ERR
+			}
+		case SEFLG_SWIEPH:
+			// compute barycentric planet (+ earth, sun, moon)
+			if retc = sweplan(tjd, ipli, SEI_FILE_PLANET, iflag, doSave, xp, xe, xs, xm, serr); retc == ERR {
+				return ERR
+			}
+		case SEFLG_MOSEPH:
+			if swiMoshplan(tjd, ipli, doSave, xp, xe, serr) == ERR {
+				return ERR
+			}
+			clear(xs[:6])
+		}
+		// if the ephemeris is not available, switch to the next one of the chain
+		if retc == NOT_AVAILABLE {
+			if iflag = epheFallback(iflag, serr); iflag == 0 {
+				return ERR
+			}
+			epheflag = iflag & SEFLG_EPHMASK
+			continue
+		}
+		return OK
+	}
 }
 
-// ===== 2406 ============ swi_get_dewnum sweph.c-2406 ==============================================================
-// Port: removed a few lines for moshier and jpl
+// ===== 1759 ===== swemoon sweph.c-1759 =============================================================================
+
+// swemoon computes the moon from the Swiss Ephemeris file: geocentric cartesian equatorial coordinates J2000.
+// tjd		julian day
+// doSave	write the position in the save area swed.Pldat[SEI_MOON]
+// xpret	position and speed of the moon, may be nil
+// Returns OK, ERR or NOT_AVAILABLE if there is no file for tjd.
+func swemoon(tjd float64, iflag int32, doSave bool, xpret []float64, serr *string) int {
+	var xx [6]float64
+	pdp := &swed.Pldat[SEI_MOON]
+	xp := xx[:]
+	if doSave {
+		xp = pdp.X[:]
+	}
+	// if planet has already been computed for this date, return. if speed flag has been turned on, recompute planet
+	speedf1 := pdp.Xflgs & SEFLG_SPEED
+	speedf2 := iflag & SEFLG_SPEED
+	if tjd == pdp.Teval && pdp.Iephe == SEFLG_SWIEPH && (speedf2 == 0 || speedf1 != 0) {
+		xp = pdp.X[:]
+	} else {
+		// call sweph for moon
+		if retc := sweph(tjd, SEI_MOON, SEI_FILE_MOON, iflag, nil, doSave, xp, serr); retc != OK {
+			return retc
+		}
+		if doSave {
+			pdp.Teval = tjd
+			pdp.Xflgs = -1
+			pdp.Iephe = SEFLG_SWIEPH
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp)
+	}
+	return OK
+}
+
+// ===== 1819 ===== sweplan sweph.c-1819=============================================================================
+
+// sweplan computes a planet from the Swiss Ephemeris files in barycentric cartesian equatorial coordinates J2000.
+// Under certain conditions, also the barycentric sun, the barycentric earth and the geocentric moon are computed.
+// tjd		julian day
+// ipli		internal planet number
+// ifno		ephemeris file number
+// doSave	write new positions in save area swed.Pldat
+// xpret	position and speed of the planet
+// xperet	of the earth
+// xpsret	of the barycentric sun
+// xpmret	of the moon
+// The return slices can be nil.
+func sweplan(tjd float64, ipli, ifno int, iflag int32, doSave bool, xpret, xperet, xpsret, xpmret []float64,
+	serr *string) int {
+	var xxp, xxm, xxs, xxe [6]float64
+	pdp := &swed.Pldat[ipli]
+	pebdp := &swed.Pldat[SEI_EMB]
+	psbdp := &swed.Pldat[SEI_SUNBARY]
+	pmdp := &swed.Pldat[SEI_MOON]
+	// xps (barycentric sun) may be necessary because some planets on sweph file are heliocentric, other ones are
+	// barycentric. without xps, the heliocentric ones cannot be returned barycentrically.
+	doSunbary := doSave || ipli == SEI_SUNBARY || pdp.Iflg&SEI_FLG_HELIO != 0 || xpsret != nil ||
+		iflag&SEFLG_HELCTR != 0
+	doEarth := doSave || ipli == SEI_EARTH || xperet != nil
+	if ipli == SEI_MOON {
+		doEarth = true
+		doSunbary = true
+	}
+	doMoon := doSave || ipli == SEI_MOON || ipli == SEI_EARTH || xperet != nil || xpmret != nil
+	xp, xpe, xps, xpm := xxp[:], xxe[:], xxs[:], xxm[:]
+	if doSave {
+		xp, xpe, xps, xpm = pdp.X[:], pebdp.X[:], psbdp.X[:], pmdp.X[:]
+	}
+	speedf2 := iflag & SEFLG_SPEED
+	// if a body has already been computed for this date, it is taken from the save area.
+	// if speed flag has been turned on, it is recomputed
+	isComputed := func(bdp *PlanData) bool {
+		return tjd == bdp.Teval && bdp.Iephe == SEFLG_SWIEPH && (speedf2 == 0 || bdp.Xflgs&SEFLG_SPEED != 0)
+	}
+	// barycentric sun
+	if doSunbary {
+		if isComputed(psbdp) {
+			copy(xps, psbdp.X[:])
+		} else if retc := sweph(tjd, SEI_SUNBARY, SEI_FILE_PLANET, iflag, nil, doSave, xps, serr); retc != OK {
+			return retc
+		}
+		if xpsret != nil {
+			copy(xpsret[:6], xps)
+		}
+	}
+	// moon
+	if doMoon {
+		if isComputed(pmdp) {
+			copy(xpm, pmdp.X[:])
+		} else {
+			retc := sweph(tjd, SEI_MOON, SEI_FILE_MOON, iflag, nil, doSave, xpm, serr)
+			if retc == ERR {
+				return retc
+			}
+			// if moon file doesn't exist, take moshier moon
+			// Port: only for the earth and if the Moshier ephemeris follows the Swiss Ephemeris in the ephemeris chain.
+			// the moon itself is computed with the next ephemeris of the chain, see swecalc().
+			if swed.Fidat[SEI_FILE_MOON].Fptr == nil && ipli != SEI_MOON && nextEphe(SEFLG_SWIEPH) == SEFLG_MOSEPH {
+				if serr != nil && len(*serr)+35 < AS_MAXCH {
+					*serr += " \nusing Moshier eph. for moon; "
+				}
+				if retc = swiMoshmoon(tjd, doSave, xpm, serr); retc != OK {
+					return retc
+				}
+			} else if retc != OK {
+				return retc
+			}
+		}
+		if xpmret != nil {
+			copy(xpmret[:6], xpm)
+		}
+	}
+	// barycentric earth
+	if doEarth {
+		if isComputed(pebdp) {
+			copy(xpe, pebdp.X[:])
+		} else {
+			if retc := sweph(tjd, SEI_EMB, SEI_FILE_PLANET, iflag, nil, doSave, xpe, serr); retc != OK {
+				return retc
+			}
+			// earth from emb and moon
+			embofs((*[3]float64)(xpe), (*[3]float64)(xpm))
+			// speed is needed, if
+			// 1. true position is being computed before applying light-time etc. this is the position saved in
+			//    pdp.X. in this case, speed is needed for light-time correction.
+			// 2. the speed flag has been specified.
+			if doSave || iflag&SEFLG_SPEED != 0 {
+				embofs((*[3]float64)(xpe[3:]), (*[3]float64)(xpm[3:]))
+			}
+		}
+		if xperet != nil {
+			copy(xperet[:6], xpe)
+		}
+	}
+	switch ipli {
+	case SEI_MOON:
+		copy(xp, xpm)
+	case SEI_EARTH: // = SEI_SUN
+		copy(xp, xpe)
+	default:
+		// planet
+		// Port: C returns here without filling xpret, if the planet has already been computed
+		if isComputed(pdp) {
+			copy(xp, pdp.X[:])
+			break
+		}
+		if retc := sweph(tjd, ipli, ifno, iflag, nil, doSave, xp, serr); retc != OK {
+			return retc
+		}
+		// if planet is heliocentric, it must be transformed to barycentric
+		if pdp.Iflg&SEI_FLG_HELIO != 0 {
+			// now barycentric planet
+			for i := 0; i <= 2; i++ {
+				xp[i] += xps[i]
+			}
+			if doSave || iflag&SEFLG_SPEED != 0 {
+				for i := 3; i <= 5; i++ {
+					xp[i] += xps[i]
+				}
+			}
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp)
+	}
+	return OK
+}
+
+// ===== 1988 ===== jplplan sweph.c-1988 =============================================================================
+
+// jplplan computes a planet from the JPL ephemeris file in barycentric cartesian equatorial coordinates J2000 (the
+// moon geocentric). Under certain conditions, also the barycentric sun and the barycentric earth are computed.
+// tjd		julian day
+// ipli		internal planet number
+// doSave	write new positions in save area swed.Pldat
+// xpret	position and speed of the planet
+// xperet	of the earth
+// xpsret	of the barycentric sun
+// The return slices can be nil.
+// We assume Teph ~= TDB ~= TT. The maximum error is < 0.002 sec, corresponding to an ephemeris error < 0.001 arcsec
+// for the moon.
+func jplplan(tjd float64, ipli int, iflag int32, doSave bool, xpret, xperet, xpsret []float64, serr *string) int {
+	var ss [3]float64
+	var xxp, xxe, xxs [6]float64
+	ictr := J_SBARY
+	pdp := &swed.Pldat[ipli]
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	xp, xpe, xps := xxp[:], xxe[:], xxs[:]
+	if doSave {
+		xp, xpe, xps = pdp.X[:], pedp.X[:], psdp.X[:]
+	}
+	doEarth := doSave || ipli == SEI_EARTH || xperet != nil || ipli == SEI_MOON
+	doSunbary := doSave || ipli == SEI_SUNBARY || xpsret != nil || ipli == SEI_MOON
+	if ipli == SEI_MOON {
+		ictr = J_EARTH
+	}
+	// open ephemeris, if still closed
+	if !swed.JplFileIsOpen {
+		if retc := openJplFile(ss[:], swed.JplFnam, swed.EphePath, serr); retc != OK {
+			return retc
+		}
+	}
+	// pleph computes the body with the save area bdp and closes the JPL file on error
+	pleph := func(ntarg, ncent int, x []float64, bdp *PlanData) int {
+		retc := swiPleph(tjd, ntarg, ncent, x, serr)
+		if doSave {
+			bdp.Teval = tjd
+			bdp.Xflgs = -1 // new light-time etc. required
+			bdp.Iephe = SEFLG_JPLEPH
+		}
+		if retc != OK {
+			swiCloseJplFile()
+			swed.JplFileIsOpen = false
+		}
+		return retc
+	}
+	if doEarth {
+		// barycentric earth
+		if tjd != pedp.Teval || tjd == 0 {
+			if retc := pleph(J_EARTH, J_SBARY, xpe, pedp); retc != OK {
+				return retc
+			}
+		} else {
+			xpe = pedp.X[:]
+		}
+		if xperet != nil {
+			copy(xperet[:6], xpe)
+		}
+	}
+	if doSunbary {
+		// barycentric sun
+		if tjd != psdp.Teval || tjd == 0 {
+			if retc := pleph(J_SUN, J_SBARY, xps, psdp); retc != OK {
+				return retc
+			}
+		} else {
+			xps = psdp.X[:]
+		}
+		if xpsret != nil {
+			copy(xpsret[:6], xps)
+		}
+	}
+	switch {
+	case ipli == SEI_EARTH:
+		// earth is wanted
+		copy(xp, xpe)
+	case ipli == SEI_SUNBARY:
+		// sunbary is wanted
+		copy(xp, xps)
+	case tjd == pdp.Teval && pdp.Iephe == SEFLG_JPLEPH:
+		// planet already computed
+		xp = pdp.X[:]
+	default:
+		// other planet
+		if retc := pleph(PNOINT2JPL[ipli], ictr, xp, pdp); retc != OK {
+			return retc
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp)
+	}
+	return OK
+}
+
+// ===== 2124 ===== sweph sweph.c-2124 ===============================================================================
+
+// sweph reads the position of a body from a Swiss Ephemeris file, in cartesian equatorial coordinates J2000.
+// tjd		julian day
+// ipli		internal planet number, or the external number of an asteroid or planetary moon
+// ifno		ephemeris file number
+// xsunb	barycentric sun, to convert the heliocentric asteroids to barycentric positions, may be nil
+// doSave	write new positions in save area swed.Pldat
+// xpret	position and speed of the body, may be nil
+// Returns OK, ERR or NOT_AVAILABLE if there is no file for tjd.
+func sweph(tjd float64, ipli, ifno int, iflag int32, xsunb []float64, doSave bool, xpret []float64,
+	serr *string) int {
+	var xx [6]float64
+	ipl := ipli
+	if ipli > SE_AST_OFFSET || ipli > SE_PLMOON_OFFSET {
+		ipl = SEI_ANYBODY
+	}
+	pdp := &swed.Pldat[ipl]
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	fdp := &swed.Fidat[ifno]
+	xp := xx[:]
+	if doSave {
+		xp = pdp.X[:]
+	}
+	// if planet has already been computed for this date, return. if speed flag has been turned on, recompute planet
+	speedf1 := pdp.Xflgs & SEFLG_SPEED
+	speedf2 := iflag & SEFLG_SPEED
+	if tjd == pdp.Teval && pdp.Iephe == SEFLG_SWIEPH && (speedf2 == 0 || speedf1 != 0) && ipl < SEI_ANYBODY {
+		if xpret != nil {
+			copy(xpret[:6], pdp.X[:])
+		}
+		return OK
+	}
+	// get correct ephemeris file
+	if fdp.Fptr != nil {
+		// if tjd is beyond file range, close old file. if new asteroid, close old file.
+		if tjd < fdp.Tfstart || tjd > fdp.Tfend || (ipl == SEI_ANYBODY && ipli != pdp.Ibdy) {
+			fdp.Fptr.Close()
+			fdp.Fptr = nil
+			pdp.Refep = nil
+			pdp.Segp = nil
+		}
+	}
+	fname := swiGenFilename(tjd, ipli)
+	// if sweph file not open, find and open it
+	if fdp.Fptr == nil {
+		subdirnam := ""
+		if i := strings.LastIndex(fname, DIR_GLUE); i >= 0 {
+			subdirnam = fname[:i+1]
+		}
+		s := fname
+		for {
+			fp, err := SwiFopen(ifno, s, swed.EphePath)
+			if err == nil {
+				fdp.Fptr = fp
+				break
+			}
+			if serr != nil {
+				*serr = err.Error()
+			}
+			if ipli > SE_PLMOON_OFFSET && ipli < SE_AST_OFFSET {
+				// if it is a planetary moon, also try without the directory "sat/"
+				if subdirnam != "" && strings.HasPrefix(s, subdirnam) {
+					s = s[len(subdirnam):]
+					continue
+				}
+			} else if ipli > SE_AST_OFFSET {
+				// if it is a numbered asteroid file, try also for short files (..s.se1). On the second try, the
+				// inserted 's' will be seen and not tried again.
+				i := strings.Index(s, ".")
+				if i > 0 && s[i-1] != 's' {
+					s = s[:i] + "s." + SE_FILE_SUFFIX
+					continue
+				}
+				// if we still have 'ast0' etc. in front of the filename, we remove it now, remove the 's' also, and
+				// try in the main ephemeris directory instead of the asteroid subdirectory.
+				if i > 0 {
+					s = s[:i-1] + s[i:]
+				}
+				if subdirnam != "" && strings.HasPrefix(s, subdirnam) {
+					s = s[len(subdirnam):]
+					continue
+				}
+			}
+			return NOT_AVAILABLE
+		}
+		// during the search error messages may have been built, delete them
+		if serr != nil {
+			*serr = ""
+		}
+		if err := readConst(ifno); err != nil {
+			if serr != nil {
+				*serr = err.Error()
+			}
+			// Port: the typed error is saved, so that SweCalc can return it
+			swed.FileErr = err
+			return ERR
+		}
+	}
+	// if first ephemeris file (J-3000), it might start a mars period after -3000. if last ephemeris file (J3000), it
+	// might end a 4000-day-period before 3000.
+	if tjd < fdp.Tfstart || tjd > fdp.Tfend {
+		if serr != nil {
+			sp := fname[strings.LastIndex(fname, DIR_GLUE)+1:]
+			var s string
+			switch {
+			case ipli > SE_AST_OFFSET:
+				s = fmt.Sprintf("asteroid No. %d (%s): ", ipli-SE_AST_OFFSET, sp)
+			case ipli > SE_PLMOON_OFFSET:
+				if strings.Contains(fname, "99.") {
+					s = fmt.Sprintf("plan. COB No. %d (%s): ", ipli, sp)
+				} else {
+					s = fmt.Sprintf("plan. moon No. %d (%s): ", ipli, sp)
+				}
+			case ipli > SEI_PLUTO:
+				s = fmt.Sprintf("asteroid eph. file (%s): ", sp)
+			case ipli != SEI_MOON:
+				s = fmt.Sprintf("planets eph. file (%s): ", sp)
+			default:
+				s = fmt.Sprintf("moon eph. file (%s): ", sp)
+			}
+			if tjd < fdp.Tfstart {
+				s += fmt.Sprintf("jd %f < lower limit %f;", tjd, fdp.Tfstart)
+			} else {
+				s += fmt.Sprintf("jd %f > upper limit %f;", tjd, fdp.Tfend)
+			}
+			if len(*serr)+len(s) < AS_MAXCH {
+				*serr += s
+			}
+		}
+		return NOT_AVAILABLE
+	}
+	// get planet's position
+	// get new segment, if necessary
+	if pdp.Segp == nil || tjd < pdp.Tseg0 || tjd > pdp.Tseg1 {
+		retc, msg := getNewSegment(tjd, ipl, ifno)
+		if retc != OK {
+			if serr != nil {
+				*serr = msg
+			}
+			return retc
+		}
+		// rotate cheby coeffs back to equatorial system. if necessary, add reference orbit.
+		if pdp.Iflg&SEI_FLG_ROTATE != 0 {
+			rotBack(ipl)
+		} else {
+			pdp.Neval = pdp.Ncoe
+		}
+	}
+	// evaluate chebyshew polynomial for tjd
+	t := (tjd - pdp.Tseg0) / pdp.Dseg
+	t = t*2 - 1
+	// speed is needed, if
+	// 1. true position is being computed before applying light-time etc. this is the position saved in pdp.X. in
+	//    this case, speed is needed for light-time correction.
+	// 2. the speed flag has been specified.
+	needSpeed := doSave || iflag&SEFLG_SPEED != 0
+	for i := 0; i <= 2; i++ {
+		xp[i] = swiEcheb(t, pdp.Segp[i*pdp.Ncoe:], pdp.Neval)
+		if needSpeed {
+			xp[i+3] = swiEdcheb(t, pdp.Segp[i*pdp.Ncoe:], pdp.Neval) / pdp.Dseg * 2
+		} else {
+			xp[i+3] = 0
+		}
+	}
+	// if planet wanted is barycentric sun: current sepl* files do not have barycentric sun, but have heliocentric
+	// earth and barycentric earth. So barycentric sun must be computed from heliocentric earth and barycentric
+	// earth: the computation above gives heliocentric earth, therefore we have to compute barycentric earth and
+	// subtract heliocentric earth from it. this may be necessary with calls from sweplan() and from appPosEtcSun()
+	// (light-time).
+	if ipl == SEI_SUNBARY && pdp.Iflg&SEI_FLG_EMBHEL != 0 {
+		// sweph() calls sweph() for EMB. a new calculation must be forced in any case, otherwise EARTH (instead of
+		// EMB) will possibly be taken from the save area. to force new computation, set pedp.Teval = 0 and restore it
+		// after call of sweph(EMB).
+		var xemb [6]float64
+		tsv := pedp.Teval
+		pedp.Teval = 0
+		if retc := sweph(tjd, SEI_EMB, ifno, iflag|SEFLG_SPEED, nil, NO_SAVE, xemb[:], serr); retc != OK {
+			return retc
+		}
+		pedp.Teval = tsv
+		for i := 0; i <= 2; i++ {
+			xp[i] = xemb[i] - xp[i]
+		}
+		if needSpeed {
+			for i := 3; i <= 5; i++ {
+				xp[i] = xemb[i] - xp[i]
+			}
+		}
+	}
+	// asteroids are heliocentric. if JPL or SWISSEPH, convert to barycentric
+	if xsunb != nil && iflag&(SEFLG_JPLEPH|SEFLG_SWIEPH) != 0 && ipl >= SEI_ANYBODY {
+		for i := 0; i <= 2; i++ {
+			xp[i] += xsunb[i]
+		}
+		if needSpeed {
+			for i := 3; i <= 5; i++ {
+				xp[i] += xsunb[i]
+			}
+		}
+	}
+	if doSave {
+		pdp.Teval = tjd
+		pdp.Xflgs = -1 // do new computation of light-time etc.
+		if ifno == SEI_FILE_PLANET || ifno == SEI_FILE_MOON {
+			pdp.Iephe = SEFLG_SWIEPH
+		} else {
+			pdp.Iephe = psdp.Iephe
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp)
+	}
+	return OK
+}
+
+// ----- 2359 ===========  swi_fopen sweph.c-2359 ====================================================================
+// TODO Port: changed this code a lot, do need to check, maybe compare with C# version
+func SwiFopen(ifno int, fname, ephepath string) (*os.File, error) {
+	var err error
+	cpos := make([]string, 20)
+
+	// Split path using PATH_SEPARATOR
+	//paths := strings.Split(ephepath, PATH_SEPARATOR)
+	np := swiCutstr(ephepath, PATH_SEPARATOR, cpos, 20)
+	for _, path := range cpos[:np] {
+		s := path
+
+		// Handle current directory case
+		if s == "." {
+			s = ""
+		} else if s != "" && !strings.HasSuffix(s, DIR_GLUE) {
+			s += DIR_GLUE
+		}
+
+		// Check path length
+		if len(s)+len(fname) >= AS_MAXCH {
+			return nil, fmt.Errorf("error: file path and name must be shorter than %d", AS_MAXCH)
+		}
+		fullPath := filepath.Join(s, fname)
+		// Store the full path if ifno >= 0, it is checked against the name in the file header
+		if ifno >= 0 {
+			swed.Fidat[ifno].Fnam = fullPath
+		}
+		// Try to open the file
+		fp, err := os.Open(fullPath)
+		if err == nil {
+			return fp, nil
+		}
+	}
+
+	// File not found in any path
+	err = fmt.Errorf("SwissEph file '%s' not found in PATH '%s'", fname, ephepath)
+	return nil, err
+}
+
+// ===== 2406 ============ swi_get_dewnum sweph.c-2406 ==============================================================
 
 func swiGetDenum(ipli int32, iflag int32) int32 {
 	var fdp *FileData
+	if iflag&SEFLG_MOSEPH != 0 {
+		return 403
+	}
+	if iflag&SEFLG_JPLEPH != 0 {
+		if swed.JplDenum > 0 {
+			return swed.JplDenum
+		}
+		return SE_DE_NUMBER
+	}
+	// Port: the SPK kernels do not provide a DE number; their frame J2000 is the ICRF, as with DE403 and later
+	if iflag&SEFLG_SPKEPH != 0 {
+		return SE_DE_NUMBER
+	}
 	switch {
 	case ipli > SE_AST_OFFSET:
 		fdp = &swed.Fidat[SEI_FILE_ANY_AST]
@@ -263,81 +1768,1101 @@ func swiGetDenum(ipli int32, iflag int32) int32 {
 	default:
 		fdp = &swed.Fidat[SEI_FILE_PLANET]
 	}
-	if fdp != nil {
-		if fdp.SwephDenum != 0 {
-			return fdp.SwephDenum
+	if fdp != nil {
+		if fdp.SwephDenum != 0 {
+			return fdp.SwephDenum
+		}
+		return SE_DE_NUMBER
+	}
+	return SE_DE_NUMBER
+}
+
+// ===== 2444 ===== calc_center_body sweph.c-2444 ===================================================================
+
+// calcCenterBody adds the offset xcom of the center of body (or planetary moon) from the barycenter of the planet
+// system to the position xx of planet ipli, if SEFLG_CENTER_BODY is set. Mercury to Mars have no such offset.
+func calcCenterBody(ipli int, iflag int32, xx, xcom []float64) {
+	if iflag&SEFLG_CENTER_BODY == 0 {
+		return
+	}
+	if ipli < SEI_MARS || ipli > SEI_PLUTO {
+		return
+	}
+	for i := 0; i <= 5; i++ {
+		xx[i] += xcom[i]
+	}
+}
+
+// ===== 2464 ===== app_pos_etc_plan sweph.c-2464 ====================================================================
+
+// appPosEtcPlan converts planets from barycentric to geocentric, apparent positions, precession and nutation
+// according to flags.
+// ipli		internal planet number, or the external number of an asteroid or planetary moon
+// iplmoon	center of body or planetary moon, used with SEFLG_CENTER_BODY
+// Port: without topocentric positions.
+func appPosEtcPlan(ipli, iplmoon int, iflag int32, serr *string) int {
+	var xx, xx0, xobs, xobs2, xearth, xsun, xcom, xxsp, xxsv [6]float64
+	var dx [3]float64
+	var ifno, ibody int
+	var pdp *PlanData
+	pedp := &swed.Pldat[SEI_EARTH]
+	oe := &swed.Oec2000
+	epheflag := iflag & SEFLG_EPHMASK
+	dtsaveForDefl := 0.0
+	// ephemeris file
+	switch {
+	case ipli > SE_PLMOON_OFFSET:
+		ifno = SEI_FILE_ANY_AST
+		ibody = IS_ANY_BODY
+		pdp = &swed.Pldat[SEI_ANYBODY]
+	case ipli == SEI_CHIRON || ipli == SEI_PHOLUS || ipli == SEI_CERES || ipli == SEI_PALLAS || ipli == SEI_JUNO ||
+		ipli == SEI_VESTA:
+		ifno = SEI_FILE_MAIN_AST
+		ibody = IS_MAIN_ASTEROID
+		pdp = &swed.Pldat[ipli]
+	default:
+		ifno = SEI_FILE_PLANET
+		ibody = IS_PLANET
+		pdp = &swed.Pldat[ipli]
+	}
+	t := pdp.Teval
+	// if the same conversions have already been done for the same date, then return
+	flg1 := iflag &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	flg2 := pdp.Xflgs &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	if flg1 == flg2 {
+		pdp.Xflgs = iflag
+		pdp.Iephe = iflag & SEFLG_EPHMASK
+		return OK
+	}
+	// the conversions will be done with xx[].
+	xx = pdp.X
+	// center body of planet, if SEFLG_CENTER_BODY (which is checked inside function)
+	calcCenterBody(ipli, iflag, xx[:], swed.Pldat[SEI_ANYBODY].X[:])
+	xx0 = xx
+	// if heliocentric position is wanted
+	if iflag&SEFLG_HELCTR != 0 &&
+		(pdp.Iephe == SEFLG_JPLEPH || pdp.Iephe == SEFLG_SWIEPH || pdp.Iephe == SEFLG_SPKEPH) {
+		for i := 0; i <= 5; i++ {
+			xx[i] -= swed.Pldat[SEI_SUNBARY].X[i]
+		}
+	}
+	// observer: barycentric position of geocenter
+	xobs = pedp.X
+	// light-time geocentric
+	if iflag&SEFLG_TRUEPOS == 0 {
+		// number of iterations - 1
+		niter := 0 // SEFLG_MOSEPH or planet from osculating elements
+		if pdp.Iephe == SEFLG_JPLEPH || pdp.Iephe == SEFLG_SWIEPH || pdp.Iephe == SEFLG_SPKEPH {
+			niter = 1
+		}
+		if iflag&SEFLG_SPEED != 0 {
+			// Apparent speed is influenced by the fact that dt changes with time. This makes a difference of several
+			// hundredths of an arc second / day. To take this into account, we compute
+			// 1. true position - apparent position at time t - 1.
+			// 2. true position - apparent position at time t.
+			// 3. the difference between the two is the part of the daily motion that results from the change of dt.
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xx[i] - xx[i+3]
+				xxsv[i] = xxsp[i]
+			}
+			for j := 0; j <= niter; j++ {
+				for i := 0; i <= 2; i++ {
+					dx[i] = xxsp[i]
+					if iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+						dx[i] -= xobs[i] - xobs[i+3]
+					}
+				}
+				// new dt
+				dt := math.Sqrt(SquareSum(dx[:])) * AUNIT / CLIGHT / 86400.0
+				for i := 0; i <= 2; i++ { // rough apparent position at t-1
+					xxsp[i] = xxsv[i] - dt*xx0[i+3]
+				}
+			}
+			// true position - apparent position at time t-1
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xxsv[i] - xxsp[i]
+			}
+		}
+		// dt and t(apparent)
+		for j := 0; j <= niter; j++ {
+			for i := 0; i <= 2; i++ {
+				dx[i] = xx[i]
+				if iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+					dx[i] -= xobs[i]
+				}
+			}
+			dt := math.Sqrt(SquareSum(dx[:])) * AUNIT / CLIGHT / 86400.0
+			// new t
+			t = pdp.Teval - dt
+			dtsaveForDefl = dt
+			for i := 0; i <= 2; i++ { // rough apparent position at t
+				xx[i] = xx0[i] - dt*xx0[i+3]
+			}
+		}
+		// part of daily motion resulting from change of dt
+		if iflag&SEFLG_SPEED != 0 {
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xx0[i] - xx[i] - xxsp[i]
+			}
+		}
+		// new position, accounting for light-time (accurate)
+		if iflag&SEFLG_CENTER_BODY != 0 && ipli >= SEI_MARS && ipli <= SEI_PLUTO {
+			// jupiter center of body, relative to jupiter barycenter
+			if retc := sweph(t, iplmoon, SEI_FILE_ANY_AST, iflag, nil, NO_SAVE, xcom[:], serr); retc == ERR ||
+				retc == NOT_AVAILABLE {
+				return ERR
+			}
+		}
+		var retc int
+		switch epheflag {
+		case SEFLG_SWIEPH:
+			if ibody == IS_PLANET {
+				retc = sweplan(t, ipli, ifno, iflag, NO_SAVE, xx[:], xearth[:], xsun[:], nil, serr)
+			} else { // asteroid
+				retc = sweplan(t, SEI_EARTH, SEI_FILE_PLANET, iflag, NO_SAVE, xearth[:], nil, xsun[:], nil, serr)
+				if retc == OK {
+					retc = sweph(t, ipli, ifno, iflag, xsun[:], NO_SAVE, xx[:], serr)
+				}
+			}
+		case SEFLG_MOSEPH:
+			// with moshier or other ephemerides, subtraction of dt * speed is sufficient (has been done in light-time
+			// iteration above). if speed flag is true, we call swiMoshplan() for new t. this does not increase
+			// position precision, but speed precision, which becomes better than 0.01"/day. for precise speed, we
+			// need earth as well.
+			if iflag&SEFLG_SPEED != 0 && iflag&(SEFLG_HELCTR|SEFLG_BARYCTR) == 0 {
+				if ibody == IS_PLANET {
+					retc = swiMoshplan(t, ipli, NO_SAVE, xxsv[:], xearth[:], serr)
+				} else { // if asteroid
+					retc = sweph(t, ipli, ifno, iflag, nil, NO_SAVE, xxsv[:], serr)
+					if retc == OK {
+						retc = swiMoshplan(t, SEI_EARTH, NO_SAVE, xearth[:], xearth[:], serr)
+					}
+				}
+				if retc != OK {
+					return retc
+				}
+				// only speed is taken from this computation, otherwise position calculations with and without speed
+				// would not agree. The difference would be about 0.01", which is far below the intrinsic error of
+				// the moshier ephemeris.
+				for i := 3; i <= 5; i++ {
+					xx[i] = xxsv[i]
+				}
+			}
+		case SEFLG_JPLEPH:
+			// closeOnError closes the JPL file after a read error or a date beyond the ephemeris range
+			closeOnError := func(retc int) int {
+				if retc != OK {
+					swiCloseJplFile()
+					swed.JplFileIsOpen = false
+				}
+				return retc
+			}
+			if ibody == IS_PLANET {
+				retc = closeOnError(swiPleph(t, PNOINT2JPL[ipli], J_SBARY, xx[:], serr))
+			} else { // asteroid
+				// first sun
+				closeOnError(swiPleph(t, J_SUN, J_SBARY, xsun[:], serr))
+				// asteroid
+				retc = sweph(t, ipli, ifno, iflag, xsun[:], NO_SAVE, xx[:], serr)
+			}
+			if retc != OK {
+				return retc
+			}
+			// for accuracy in speed, we need earth as well
+			if iflag&SEFLG_SPEED != 0 && iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+				if retc = closeOnError(swiPleph(t, J_EARTH, J_SBARY, xearth[:], serr)); retc != OK {
+					return retc
+				}
+			}
+		case SEFLG_SPKEPH:
+			// for accuracy in speed, we need earth as well
+			var xpe []float64
+			if iflag&SEFLG_SPEED != 0 && iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+				xpe = xearth[:]
+			}
+			retc = spkplan(t, ipli, iflag, NO_SAVE, xx[:], xpe, nil, serr)
+		}
+		if retc != OK {
+			return retc
+		}
+		calcCenterBody(ipli, iflag, xx[:], xcom[:])
+		if iflag&SEFLG_HELCTR != 0 &&
+			(pdp.Iephe == SEFLG_JPLEPH || pdp.Iephe == SEFLG_SWIEPH || pdp.Iephe == SEFLG_SPKEPH) {
+			for i := 0; i <= 5; i++ {
+				xx[i] -= swed.Pldat[SEI_SUNBARY].X[i]
+			}
+		}
+		if iflag&SEFLG_SPEED != 0 {
+			// observer position for t(light-time)
+			xobs2 = xearth
+		}
+	}
+	// conversion to geocenter
+	if iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+		// subtract earth
+		for i := 0; i <= 5; i++ {
+			xx[i] -= xobs[i]
+		}
+		// Apparent speed is also influenced by the change of dt during motion. Neglect of this would result in an
+		// error of several 0.01"
+		if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_SPEED != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] -= xxsp[i-3]
+			}
+		}
+	}
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
+	}
+	// relativistic deflection of light. SEFLG_NOGDEFL is on, if SEFLG_HELCTR or SEFLG_BARYCTR
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOGDEFL == 0 {
+		swiDeflectLight(xx[:], dtsaveForDefl, iflag)
+	}
+	// 'annual' aberration of light. SEFLG_NOABERR is on, if SEFLG_HELCTR or SEFLG_BARYCTR
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOABERR == 0 {
+		swiAberrLight(xx[:], xobs[:], iflag)
+		// Apparent speed is also influenced by the difference of speed of the earth between t and t-dt. Neglecting
+		// this would involve an error of several 0.1"
+		if iflag&SEFLG_SPEED != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] += xobs[i] - xobs2[i]
+			}
+		}
+	}
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
+	}
+	// ICRS to J2000
+	if iflag&SEFLG_ICRS == 0 && swiGetDenum(int32(ipli), epheflag) >= 403 {
+		SwiBias(xx[:], t, iflag, false)
+	}
+	// save J2000 coordinates; required for sidereal positions
+	xxsv = xx
+	// precession, equator 2000 -> equator of date
+	if iflag&SEFLG_J2000 == 0 {
+		swiPrecess(xx[:], pdp.Teval, iflag, J2000_TO_J)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(xx[:], pdp.Teval, iflag, J2000_TO_J)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(pdp, iflag, xx[:], xxsv[:], oe, serr)
+}
+
+// ===== 2776 ===== app_pos_rest sweph.c-2776 ========================================================================
+
+// appPosRest applies nutation to the equatorial position xx and stores the position in all coordinate systems in
+// pdp.Xreturn. x2000 is the position for J2000, oe the obliquity of the ecliptic to be used.
+// Port: sidereal positions are not supported, x2000 is not used.
+func appPosRest(pdp *PlanData, iflag int32, xx, x2000 []float64, oe *Epsilon, serr *string) int {
+	// nutation
+	if iflag&SEFLG_NONUT == 0 {
+		SwiNutate(xx, iflag, false)
+	}
+	// now we have equatorial cartesian coordinates; save them
+	copy(pdp.Xreturn[18:24], xx[:6])
+	// transformation to ecliptic.
+	swiCoortrf2(xx, xx, oe.Seps, oe.Ceps)
+	if iflag&SEFLG_SPEED != 0 {
+		swiCoortrf2(xx[3:], xx[3:], oe.Seps, oe.Ceps)
+	}
+	if iflag&SEFLG_NONUT == 0 {
+		swiCoortrf2(xx, xx, swed.Nut.Snut, swed.Nut.Cnut)
+		if iflag&SEFLG_SPEED != 0 {
+			swiCoortrf2(xx[3:], xx[3:], swed.Nut.Snut, swed.Nut.Cnut)
+		}
+	}
+	// now we have ecliptic cartesian coordinates
+	copy(pdp.Xreturn[6:12], xx[:6])
+	// transformation to polar coordinates
+	swiCartpolSp(pdp.Xreturn[18:], pdp.Xreturn[12:])
+	swiCartpolSp(pdp.Xreturn[6:], pdp.Xreturn[:])
+	// radians to degrees
+	for i := 0; i < 2; i++ {
+		pdp.Xreturn[i] *= RADTODEG // ecliptic
+		pdp.Xreturn[i+3] *= RADTODEG
+		pdp.Xreturn[i+12] *= RADTODEG // equator
+		pdp.Xreturn[i+15] *= RADTODEG
+	}
+	// save, what has been done
+	pdp.Xflgs = iflag
+	pdp.Iephe = iflag & SEFLG_EPHMASK
+	return OK
+}
+
+// ===== 3364 ===== app_pos_etc_plan_osc sweph.c-3364 ================================================================
+
+// appPosEtcPlanOsc converts a fictitious body from osculating elements (see swiOscElPlan) from barycentric to
+// geocentric and computes the apparent position, precession and nutation according to flags.
+// ipl		body number (SE_FICT_OFFSET .., Port: or SE_COMET_OFFSET .. for SweLoadMpcFile)
+// ipli		body number in planetary data structure
+// fictEl	built-in elements of the Uranian planets
+// Port: without topocentric positions, fictEl is added.
+func appPosEtcPlanOsc(ipl, ipli int, iflag, fictEl int32, serr *string) int {
+	var xx, xearth, xsun, xmoon, xxsv, xobs, xobs2 [6]float64
+	var dx, xxsp [3]float64
+	var dt, dtsaveForDefl float64
+	pdp := &swed.Pldat[ipli]
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	epheflag := int32(SEFLG_DEFAULTEPH)
+	if iflag&SEFLG_MOSEPH != 0 {
+		epheflag = SEFLG_MOSEPH
+	} else if iflag&SEFLG_SWIEPH != 0 {
+		epheflag = SEFLG_SWIEPH
+	} else if iflag&SEFLG_JPLEPH != 0 {
+		epheflag = SEFLG_JPLEPH
+	} else if iflag&SEFLG_SPKEPH != 0 {
+		epheflag = SEFLG_SPKEPH
+	}
+	// the conversions will be done with xx[].
+	xx = pdp.X
+	// barycentric position is required; = heliocentric position with Moshier ephemeris
+	// observer: geocenter
+	if iflag&SEFLG_BARYCTR != 0 {
+		// xobs = 0
+	} else if iflag&SEFLG_HELCTR != 0 {
+		if iflag&SEFLG_MOSEPH == 0 {
+			xobs = psdp.X
+		}
+	} else {
+		xobs = pedp.X
+	}
+	// light-time
+	if iflag&SEFLG_TRUEPOS == 0 {
+		niter := 1
+		if iflag&SEFLG_SPEED != 0 {
+			// Apparent speed is influenced by the fact that dt changes with motion. This makes a difference of several
+			// hundredths of an arc second. To take this into account, we compute
+			// 1. true position - apparent position at time t - 1.
+			// 2. true position - apparent position at time t.
+			// 3. the difference between the two is the daily motion resulting from the change of dt.
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xx[i] - xx[i+3]
+				xxsv[i] = xxsp[i]
+			}
+			for j := 0; j <= niter; j++ {
+				for i := 0; i <= 2; i++ {
+					dx[i] = xxsp[i]
+					if iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+						dx[i] -= xobs[i] - xobs[i+3]
+					}
+				}
+				// new dt
+				dt = math.Sqrt(SquareSum(dx[:])) * AUNIT / CLIGHT / 86400.0
+				for i := 0; i <= 2; i++ {
+					xxsp[i] = xxsv[i] - dt*pdp.X[i+3] // rough apparent position
+				}
+			}
+			// true position - apparent position at time t-1
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xxsv[i] - xxsp[i]
+			}
+		}
+		// dt and t(apparent)
+		for j := 0; j <= niter; j++ {
+			for i := 0; i <= 2; i++ {
+				dx[i] = xx[i]
+				if iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+					dx[i] -= xobs[i]
+				}
+			}
+			// new dt
+			dt = math.Sqrt(SquareSum(dx[:])) * AUNIT / CLIGHT / 86400.0
+			dtsaveForDefl = dt
+			// new position: subtract t * speed
+			for i := 0; i <= 2; i++ {
+				xx[i] = pdp.X[i] - dt*pdp.X[i+3]
+				xx[i+3] = pdp.X[i+3]
+			}
+		}
+		if iflag&SEFLG_SPEED != 0 {
+			// part of daily motion resulting from change of dt
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = pdp.X[i] - xx[i] - xxsp[i]
+			}
+			t := pdp.Teval - dt
+			// for accuracy in speed, we will need earth as well
+			retc := mainPlanetBary(t, SEI_EARTH, epheflag, iflag, NO_SAVE, xearth[:], xearth[:], xsun[:], xmoon[:],
+				serr)
+			if oscElPlan(t, xx[:], ipl, ipli, xearth[:], xsun[:], fictEl, serr) != OK {
+				return ERR
+			}
+			if retc != OK {
+				return retc
+			}
+			xobs2 = xearth
+		}
+	}
+	// conversion to geocenter
+	for i := 0; i <= 5; i++ {
+		xx[i] -= xobs[i]
+	}
+	if iflag&SEFLG_TRUEPOS == 0 {
+		// Apparent speed is also influenced by the change of dt during motion. Neglect of this would result in an
+		// error of several 0.01"
+		if iflag&SEFLG_SPEED != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] -= xxsp[i-3]
+			}
+		}
+	}
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
+	}
+	// relativistic deflection of light. SEFLG_NOGDEFL is on, if SEFLG_HELCTR or SEFLG_BARYCTR
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOGDEFL == 0 {
+		swiDeflectLight(xx[:], dtsaveForDefl, iflag)
+	}
+	// 'annual' aberration of light. SEFLG_NOABERR is on, if SEFLG_HELCTR or SEFLG_BARYCTR
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOABERR == 0 {
+		swiAberrLight(xx[:], xobs[:], iflag)
+		// Apparent speed is also influenced by the difference of speed of the earth between t and t-dt. Neglecting
+		// this would involve an error of several 0.1"
+		if iflag&SEFLG_SPEED != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] += xobs[i] - xobs2[i]
+			}
+		}
+	}
+	// save J2000 coordinates; required for sidereal positions
+	xxsv = xx
+	// precession, equator 2000 -> equator of date
+	oe := &swed.Oec2000
+	if iflag&SEFLG_J2000 == 0 {
+		swiPrecess(xx[:], pdp.Teval, iflag, J2000_TO_J)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(xx[:], pdp.Teval, iflag, J2000_TO_J)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(pdp, iflag, xx[:], xxsv[:], oe, serr)
+}
+
+// ===== 3551 ===== swi_precess_speed sweph.c-3551 ===================================================================
+
+// swiPrecessSpeed corrects the speed of the cartesian equatorial position xx for precession.
+// direction is J2000_TO_J or J_TO_J2000, as for swiPrecess.
+func swiPrecessSpeed(xx []float64, t float64, iflag int32, direction int) {
+	var oe *Epsilon
+	var fac float64
+	tprec := (t - J2000) / 36525.0
+	precModel := swed.AstroModels[SE_MODEL_PREC_LONGTERM]
+	if precModel == 0 {
+		precModel = SEMOD_PREC_DEFAULT
+	}
+	if direction == J2000_TO_J {
+		fac = 1
+		oe = &swed.Oec
+	} else {
+		fac = -1
+		oe = &swed.Oec2000
+	}
+	// first correct rotation. this costs some sines and cosines, but neglect might involve an error > 1"/day
+	swiPrecess(xx[3:], t, iflag, direction)
+	// then add 0.137"/day
+	swiCoortrf2(xx, xx, oe.Seps, oe.Ceps)
+	swiCoortrf2(xx[3:], xx[3:], oe.Seps, oe.Ceps)
+	swiCartpolSp(xx, xx)
+	if precModel == SEMOD_PREC_VONDRAK_2011 {
+		dpre, _ := swiLdpPeps(t)
+		dpre2, _ := swiLdpPeps(t + 1)
+		xx[3] += (dpre2 - dpre) * fac
+	} else {
+		// formula from Montenbruck, German 1994, p. 18
+		xx[3] += (50.290966 + 0.0222226*tprec) / 3600 / 365.25 * DEGTORAD * fac
+	}
+	swiPolcartSp(xx, xx)
+	swiCoortrf2(xx, xx, -oe.Seps, oe.Ceps)
+	swiCoortrf2(xx[3:], xx[3:], -oe.Seps, oe.Ceps)
+}
+
+// ===== 3588 ===== swi_nutate sweph.c-3588 =========================================================================
+
+// SwiNutate multiplies cartesian equatorial coordinates with previously calculated nutation matrix. also corrects speed.
+func SwiNutate(xx []float64, iflag int32, backward bool) {
+	x := make([]float64, 6)
+	xv := make([]float64, 6)
+	for i := 0; i <= 2; i++ {
+		if backward {
+			x[i] = xx[0]*swed.Nut.Matrix[i][0] +
+				xx[1]*swed.Nut.Matrix[i][1] +
+				xx[2]*swed.Nut.Matrix[i][2]
+		} else {
+			x[i] = xx[0]*swed.Nut.Matrix[0][i] +
+				xx[1]*swed.Nut.Matrix[1][i] +
+				xx[2]*swed.Nut.Matrix[2][i]
+		}
+	}
+	if (iflag & SEFLG_SPEED) != 0 {
+		// Correct speed:
+		// First correct rotation
+		for i := 0; i <= 2; i++ {
+			if backward {
+				x[i+3] = xx[3]*swed.Nut.Matrix[i][0] +
+					xx[4]*swed.Nut.Matrix[i][1] +
+					xx[5]*swed.Nut.Matrix[i][2]
+			} else {
+				x[i+3] = xx[3]*swed.Nut.Matrix[0][i] +
+					xx[4]*swed.Nut.Matrix[1][i] +
+					xx[5]*swed.Nut.Matrix[2][i]
+			}
+		}
+		// Then apparent motion due to change of nutation during day.
+		// This makes a difference of 0.01"
+		for i := 0; i <= 2; i++ {
+			if backward {
+				xv[i] = xx[0]*swed.Nutv.Matrix[i][0] +
+					xx[1]*swed.Nutv.Matrix[i][1] +
+					xx[2]*swed.Nutv.Matrix[i][2]
+			} else {
+				xv[i] = xx[0]*swed.Nutv.Matrix[0][i] +
+					xx[1]*swed.Nutv.Matrix[1][i] +
+					xx[2]*swed.Nutv.Matrix[2][i]
+			}
+			// New speed
+			xx[i+3] = x[i+3] + (x[i]-xv[i])/NUT_SPEED_INTV
+		}
+	}
+	// New position
+	for i := 0; i <= 2; i++ {
+		xx[i] = x[i]
+	}
+}
+
+// ===== 3646 ===== aberr_light sweph.c-3646 =========================================================================
+
+// aberrLight computes 'annual' aberration of the position xx, without correction of the speed
+// xx		planet's position accounted for light-time and gravitational light deflection
+// xe		earth's position and speed
+func aberrLight(xx, xe []float64) {
+	var v [3]float64
+	u := xx[:3]
+	ru := math.Sqrt(SquareSum(u))
+	for i := 0; i <= 2; i++ {
+		v[i] = xe[i+3] / 24.0 / 3600.0 / CLIGHT * AUNIT
+	}
+	v2 := SquareSum(v[:])
+	b1 := math.Sqrt(1 - v2)
+	f1 := DotProduct(u, v[:]) / ru
+	f2 := 1.0 + f1/(1.0+b1)
+	for i := 0; i <= 2; i++ {
+		xx[i] = (b1*xx[i] + f2*ru*v[i]) / (1.0 + f1)
+	}
+}
+
+// ===== 3671 ===== swi_aberr_light_ex sweph.c-3671 ==================================================================
+
+// swiAberrLightEx computes 'annual' aberration; the speed is the difference of the aberrated positions at t and t - dt
+// xx		planet's position accounted for light-time and gravitational light deflection
+// xe		earth's position and speed
+// xeDt	earth's position and speed at t - dt
+// dt		time difference for which xeDt is given
+func swiAberrLightEx(xx, xe, xeDt []float64, dt float64, iflag int32) {
+	var xx2 [3]float64
+	xxs := [6]float64(xx[:6])
+	aberrLight(xx, xe)
+	// correction of speed. the influence of aberration on apparent velocity can reach 0.4"/day
+	if iflag&SEFLG_SPEED != 0 {
+		for i := 0; i <= 2; i++ {
+			xx2[i] = xxs[i] - dt*xxs[i+3]
+		}
+		aberrLight(xx2[:], xeDt)
+		for i := 0; i <= 2; i++ {
+			xx[i+3] = (xx[i] - xx2[i]) / dt
+		}
+	}
+}
+
+// ===== 3714 ===== swi_aberr_light sweph.c-3714 =====================================================================
+
+// swiAberrLight computes 'annual' aberration
+// xx		planet's position accounted for light-time and gravitational light deflection
+// xe		earth's position and speed
+func swiAberrLight(xx, xe []float64, iflag int32) {
+	var xxs, v, u, xx2 [6]float64
+	intv := PLAN_SPEED_INTV
+	copy(u[:], xx[:6])
+	xxs = u
+	ru := math.Sqrt(SquareSum(u[:]))
+	for i := 0; i <= 2; i++ {
+		v[i] = xe[i+3] / 24.0 / 3600.0 / CLIGHT * AUNIT
+	}
+	v2 := SquareSum(v[:])
+	b1 := math.Sqrt(1 - v2)
+	f1 := DotProduct(u[:], v[:]) / ru
+	f2 := 1.0 + f1/(1.0+b1)
+	for i := 0; i <= 2; i++ {
+		xx[i] = (b1*xx[i] + f2*ru*v[i]) / (1.0 + f1)
+	}
+	if iflag&SEFLG_SPEED != 0 {
+		// correction of speed, the influence of aberration on apparent velocity can reach 0.4"/day
+		for i := 0; i <= 2; i++ {
+			u[i] = xxs[i] - intv*xxs[i+3]
+		}
+		ru = math.Sqrt(SquareSum(u[:]))
+		f1 = DotProduct(u[:], v[:]) / ru
+		f2 = 1.0 + f1/(1.0+b1)
+		for i := 0; i <= 2; i++ {
+			xx2[i] = (b1*u[i] + f2*ru*v[i]) / (1.0 + f1)
+		}
+		for i := 0; i <= 2; i++ {
+			dx1 := xx[i] - xxs[i]
+			dx2 := xx2[i] - u[i]
+			dx1 -= dx2
+			xx[i+3] += dx1 / intv
+		}
+	}
+}
+
+// ===== 3742 ===== swi_deflect_light sweph.c-3742 ===================================================================
+
+// swiDeflectLight computes relativistic light deflection by the sun
+// xx		planet's position accounted for light-time
+// dt		dt of light-time
+func swiDeflectLight(xx []float64, dt float64, iflag int32) {
+	var xx2, xx3, u, e, q, xsun, xearth [6]float64
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	iephe := pedp.Iephe
+	xearth = pedp.X
+	if iflag&SEFLG_TOPOCTR != 0 {
+		for i := 0; i <= 5; i++ {
+			xearth[i] += swed.Topd.Xobs[i]
+		}
+	}
+	// deflected computes the deflected position xd = ru * (u + g1/g2 * (uq * e - ue * q)) and returns ru. u, e and q
+	// are normalized in place.
+	deflected := func(xd []float64) float64 {
+		ru := math.Sqrt(SquareSum(u[:]))
+		rq := math.Sqrt(SquareSum(q[:]))
+		re := math.Sqrt(SquareSum(e[:]))
+		for i := 0; i <= 2; i++ {
+			u[i] /= ru
+			q[i] /= rq
+			e[i] /= re
+		}
+		uq := DotProduct(u[:], q[:])
+		ue := DotProduct(u[:], e[:])
+		qe := DotProduct(q[:], e[:])
+		// When a planet approaches the center of the sun in superior conjunction, the formula for the deflection
+		// angle as given in Expl. Suppl. p. 136 cannot be used. The deflection seems to increase rapidly towards
+		// infinity. The reason is that the formula considers the sun as a point mass. AA recommends to set
+		// deflection = 0 in such a case. However, to get a continous motion, we modify the formula for a
+		// non-point-mass, taking into account the mass distribution within the sun. For more info, s. Meff().
+		sina := math.Sqrt(1 - ue*ue) // sin(angle) between sun and planet
+		sinSunr := SUN_RADIUS / re   // sine of sun radius (= sun radius)
+		meffFact := 1.0
+		if sina < sinSunr {
+			meffFact = Meff(sina / sinSunr)
+		}
+		g1 := 2.0 * HELGRAVCONST * meffFact / CLIGHT / CLIGHT / AUNIT / re
+		g2 := 1.0 + qe
+		for i := 0; i <= 2; i++ {
+			xd[i] = ru * (u[i] + g1/g2*(uq*e[i]-ue*q[i]))
+		}
+		return ru
+	}
+	isBarycentric := iephe == SEFLG_JPLEPH || iephe == SEFLG_SWIEPH || iephe == SEFLG_SPKEPH
+	// U = planetbary(t-tau) - earthbary(t) = planetgeo
+	copy(u[:3], xx[:3])
+	// Eh = earthbary(t) - sunbary(t) = earthhel
+	for i := 0; i <= 2; i++ {
+		e[i] = xearth[i]
+		if isBarycentric {
+			e[i] -= psdp.X[i]
+		}
+	}
+	// Q = planetbary(t-tau) - sunbary(t-tau) = 'planethel'
+	// first compute sunbary(t-tau) for
+	xsun = psdp.X
+	if isBarycentric {
+		for i := 0; i <= 2; i++ {
+			// this is sufficient precision
+			xsun[i] = psdp.X[i] - dt*psdp.X[i+3]
+		}
+	}
+	for i := 0; i <= 2; i++ {
+		q[i] = xx[i] + xearth[i] - xsun[i]
+	}
+	// compute deflected position
+	deflected(xx2[:])
+	if iflag&SEFLG_SPEED != 0 {
+		// correction of speed. influence of light deflection on a planet's apparent speed: for an outer planet at
+		// the solar limb with |v(planet) - v(sun)| = 1 degree, this makes a difference of 7"/day. if the planet is
+		// within the solar disc, the difference may increase to 30" or more.
+		// to compute speed, we do the same calculation as above with slightly different u, e, q, and find out the
+		// difference in deflection.
+		dtsp := -DEFL_SPEED_INTV
+		// U = planetbary(t-tau) - earthbary(t) = planetgeo
+		for i := 0; i <= 2; i++ {
+			u[i] = xx[i] - dtsp*xx[i+3]
+		}
+		// Eh = earthbary(t) - sunbary(t) = earthhel
+		for i := 0; i <= 2; i++ {
+			if isBarycentric {
+				e[i] = xearth[i] - psdp.X[i] - dtsp*(xearth[i+3]-psdp.X[i+3])
+			} else {
+				e[i] = xearth[i] - dtsp*xearth[i+3]
+			}
+		}
+		// Q = planetbary(t-tau) - sunbary(t-tau) = 'planethel'
+		for i := 0; i <= 2; i++ {
+			q[i] = u[i] + xearth[i] - xsun[i] - dtsp*(xearth[i+3]-xsun[i+3])
+		}
+		ru := deflected(xx3[:])
+		for i := 0; i <= 2; i++ {
+			dx1 := xx2[i] - xx[i]
+			dx2 := xx3[i] - u[i]*ru
+			dx1 -= dx2
+			xx[i+3] += dx1 / dtsp
+		}
+	}
+	// deflected position
+	copy(xx[:3], xx2[:3])
+}
+
+// ===== 3901 ===== app_pos_etc_sun sweph.c-3901 =====================================================================
+
+// appPosEtcSun converts the sun from barycentric to geocentric, the earth from barycentric to heliocentric, and
+// computes the apparent position, precession and nutation according to flags.
+// Port: without topocentric positions.
+func appPosEtcSun(iflag int32, serr *string) int {
+	var xx, xxsv, xearth, xsun, xobs [6]float64
+	var dx [3]float64
+	t := 0.0
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	oe := &swed.Oec2000
+	// if the same conversions have already been done for the same date, then return
+	flg1 := iflag &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	flg2 := pedp.Xflgs &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	if flg1 == flg2 {
+		pedp.Xflgs = iflag
+		pedp.Iephe = iflag & SEFLG_EPHMASK
+		return OK
+	}
+	// observer: barycentric position of geocenter
+	xobs = pedp.X
+	// true heliocentric position of earth
+	xx = xobs
+	if pedp.Iephe != SEFLG_MOSEPH && iflag&SEFLG_BARYCTR == 0 {
+		for i := 0; i <= 5; i++ {
+			xx[i] -= psdp.X[i]
+		}
+	}
+	// light-time
+	if iflag&SEFLG_TRUEPOS == 0 {
+		// with jpl and swiss ephemeris:
+		//   with geocentric computation of sun: light-time correction of barycentric sun position.
+		//   with heliocentric or barycentric computation of earth: light-time correction of barycentric earth
+		//   position.
+		// with moshier ephemeris (heliocentric!!!):
+		//   with geocentric computation of sun: nothing! (aberration will be done later)
+		//   with heliocentric or barycentric computation of earth: light-time correction of heliocentric earth
+		//   position.
+		if pedp.Iephe == SEFLG_JPLEPH || pedp.Iephe == SEFLG_SWIEPH || pedp.Iephe == SEFLG_SPKEPH ||
+			iflag&SEFLG_HELCTR != 0 ||
+			iflag&SEFLG_BARYCTR != 0 {
+			xearth = xobs
+			if pedp.Iephe != SEFLG_MOSEPH {
+				xsun = psdp.X
+			}
+			niter := 1 // # of iterations
+			for j := 0; j <= niter; j++ {
+				// distance earth-sun
+				for i := 0; i <= 2; i++ {
+					dx[i] = xearth[i]
+					if iflag&SEFLG_BARYCTR == 0 {
+						dx[i] -= xsun[i]
+					}
+				}
+				// new t
+				dt := math.Sqrt(SquareSum(dx[:])) * AUNIT / CLIGHT / 86400.0
+				t = pedp.Teval - dt
+				// new position: if geocentric sun, new sun at t'; if heliocentric or barycentric earth, new earth at t'
+				var retc int
+				switch pedp.Iephe {
+				case SEFLG_SWIEPH:
+					if iflag&SEFLG_HELCTR != 0 || iflag&SEFLG_BARYCTR != 0 {
+						retc = sweplan(t, SEI_EARTH, SEI_FILE_PLANET, iflag, NO_SAVE, xearth[:], nil, xsun[:], nil,
+							serr)
+					} else {
+						retc = sweph(t, SEI_SUNBARY, SEI_FILE_PLANET, iflag, nil, NO_SAVE, xsun[:], serr)
+					}
+				case SEFLG_MOSEPH:
+					if iflag&SEFLG_HELCTR != 0 || iflag&SEFLG_BARYCTR != 0 {
+						retc = swiMoshplan(t, SEI_EARTH, NO_SAVE, xearth[:], xearth[:], serr)
+					}
+					// with moshier there is no barycentric sun
+				case SEFLG_JPLEPH:
+					if iflag&SEFLG_HELCTR != 0 || iflag&SEFLG_BARYCTR != 0 {
+						retc = swiPleph(t, J_EARTH, J_SBARY, xearth[:], serr)
+					} else {
+						retc = swiPleph(t, J_SUN, J_SBARY, xsun[:], serr)
+					}
+					if retc != OK {
+						swiCloseJplFile()
+						swed.JplFileIsOpen = false
+					}
+				case SEFLG_SPKEPH:
+					if iflag&SEFLG_HELCTR != 0 || iflag&SEFLG_BARYCTR != 0 {
+						retc = spkplan(t, SEI_EARTH, iflag, NO_SAVE, xearth[:], nil, nil, serr)
+					} else {
+						retc = spkplan(t, SEI_SUNBARY, iflag, NO_SAVE, xsun[:], nil, nil, serr)
+					}
+				}
+				if retc != OK {
+					return retc
+				}
+			}
+			// apparent heliocentric earth
+			for i := 0; i <= 5; i++ {
+				xx[i] = xearth[i]
+				if iflag&SEFLG_BARYCTR == 0 {
+					xx[i] -= xsun[i]
+				}
+			}
+		}
+	}
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
+	}
+	// conversion to geocenter
+	if iflag&SEFLG_HELCTR == 0 && iflag&SEFLG_BARYCTR == 0 {
+		for i := 0; i <= 5; i++ {
+			xx[i] = -xx[i]
+		}
+	}
+	// 'annual' aberration of light. SEFLG_NOABERR is on, if SEFLG_HELCTR or SEFLG_BARYCTR
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOABERR == 0 {
+		swiAberrLight(xx[:], xobs[:], iflag)
+	}
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
+	}
+	// ICRS to J2000
+	if iflag&SEFLG_ICRS == 0 && swiGetDenum(SEI_SUN, iflag) >= 403 {
+		SwiBias(xx[:], t, iflag, false)
+	}
+	// save J2000 coordinates; required for sidereal positions
+	xxsv = xx
+	// precession, equator 2000 -> equator of date
+	if iflag&SEFLG_J2000 == 0 {
+		swiPrecess(xx[:], pedp.Teval, iflag, J2000_TO_J)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(xx[:], pedp.Teval, iflag, J2000_TO_J)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(pedp, iflag, xx[:], xxsv[:], oe, serr)
+}
+
+// ===== 4086 ===== app_pos_etc_moon sweph.c-4086 ====================================================================
+
+// appPosEtcMoon transforms the position of the moon: heliocentric position, barycentric position, astrometric
+// position, apparent position, precession and nutation.
+// note: for apparent positions, we consider the earth-moon system as independant. for astrometric positions
+// (SEFLG_NOABERR), we consider the motions of the earth and the moon related to the solar system barycenter.
+// Port: without topocentric positions.
+func appPosEtcMoon(iflag int32, serr *string) int {
+	var xx, xxsv, xobs, xxm, xs, xe, xobs2 [6]float64
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	pdp := &swed.Pldat[SEI_MOON]
+	oe := &swed.Oec2000
+	// if the same conversions have already been done for the same date, then return
+	flg1 := iflag &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	flg2 := pdp.Xflgs &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	if flg1 == flg2 {
+		pdp.Xflgs = iflag
+		pdp.Iephe = iflag & SEFLG_EPHMASK
+		return OK
+	}
+	// the conversions will be done with xx[].
+	xx = pdp.X
+	xxm = xx
+	// to solar system barycentric
+	for i := 0; i <= 5; i++ {
+		xx[i] += pedp.X[i]
+	}
+	// observer
+	switch {
+	case iflag&SEFLG_BARYCTR != 0:
+		for i := 0; i <= 5; i++ {
+			xxm[i] += pedp.X[i]
+		}
+	case iflag&SEFLG_HELCTR != 0:
+		xobs = psdp.X
+		for i := 0; i <= 5; i++ {
+			xxm[i] += pedp.X[i] - psdp.X[i]
+		}
+	default:
+		xobs = pedp.X
+	}
+	// light-time
+	t := pdp.Teval
+	if iflag&SEFLG_TRUEPOS == 0 {
+		dt := math.Sqrt(SquareSum(xxm[:])) * AUNIT / CLIGHT / 86400.0
+		t = pdp.Teval - dt
+		switch pdp.Iephe {
+		case SEFLG_SWIEPH:
+			if retc := sweplan(t, SEI_MOON, SEI_FILE_MOON, iflag, NO_SAVE, xx[:], xe[:], xs[:], nil, serr); retc != OK {
+				return retc
+			}
+			for i := 0; i <= 5; i++ {
+				xx[i] += xe[i]
+			}
+		case SEFLG_MOSEPH:
+			// this method results in an error of a milliarcsec in speed
+			for i := 0; i <= 2; i++ {
+				xx[i] -= dt * xx[i+3]
+				xe[i] = pedp.X[i] - dt*pedp.X[i+3]
+				xe[i+3] = pedp.X[i+3]
+				xs[i] = 0
+				xs[i+3] = 0
+			}
+		case SEFLG_JPLEPH:
+			retc := swiPleph(t, J_MOON, J_EARTH, xx[:], serr)
+			if retc == OK {
+				retc = swiPleph(t, J_EARTH, J_SBARY, xe[:], serr)
+			}
+			if retc == OK && iflag&SEFLG_HELCTR != 0 {
+				retc = swiPleph(t, J_SUN, J_SBARY, xs[:], serr)
+			}
+			if retc != OK {
+				swiCloseJplFile()
+				swed.JplFileIsOpen = false
+				return retc
+			}
+			for i := 0; i <= 5; i++ {
+				xx[i] += xe[i]
+			}
+		case SEFLG_SPKEPH:
+			if retc := spkplan(t, SEI_MOON, iflag, NO_SAVE, xx[:], xe[:], xs[:], serr); retc != OK {
+				return retc
+			}
+			for i := 0; i <= 5; i++ {
+				xx[i] += xe[i]
+			}
+		}
+		switch {
+		case iflag&SEFLG_BARYCTR != 0:
+			clear(xobs2[:])
+		case iflag&SEFLG_HELCTR != 0:
+			xobs2 = xs
+		default:
+			xobs2 = xe
+		}
+	}
+	// to correct center
+	for i := 0; i <= 5; i++ {
+		xx[i] -= xobs[i]
+	}
+	// 'annual' aberration of light. SEFLG_NOABERR is on, if SEFLG_HELCTR or SEFLG_BARYCTR
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOABERR == 0 {
+		swiAberrLight(xx[:], xobs[:], iflag)
+		// Apparent speed is also influenced by the difference of speed of the earth between t and t-dt. Neglecting
+		// this would lead to an error of several 0.1"
+		if iflag&SEFLG_SPEED != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] += xobs[i] - xobs2[i]
+			}
+		}
+	}
+	// if !speedflag, speed = 0
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
+	}
+	// ICRS to J2000
+	if iflag&SEFLG_ICRS == 0 && swiGetDenum(SEI_MOON, iflag) >= 403 {
+		SwiBias(xx[:], t, iflag, false)
+	}
+	// save J2000 coordinates; required for sidereal positions
+	xxsv = xx
+	// precession, equator 2000 -> equator of date
+	if iflag&SEFLG_J2000 == 0 {
+		swiPrecess(xx[:], pdp.Teval, iflag, J2000_TO_J)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(xx[:], pdp.Teval, iflag, J2000_TO_J)
 		}
-		return SE_DE_NUMBER
+		oe = &swed.Oec
 	}
-	return SE_DE_NUMBER
+	return appPosRest(pdp, iflag, xx[:], xxsv[:], oe, serr)
 }
 
-// ===== 2444 ===== calc_center_body sweph.c-2444 ===================================================================
+// ===== 4253 ===== app_pos_etc_sbar sweph.c-4253 ====================================================================
 
-func calcCenterBody(ipli int32, iflag int32, xx *[]float64, xcom *[]float64) error {
-	if (iflag & SEFLG_CENTER_BODY) == 0 {
-		return nil
+// appPosEtcSbar transforms the position of the barycentric sun: precession and nutation according to flags.
+// The result is stored in the save area of the earth.
+func appPosEtcSbar(iflag int32, serr *string) int {
+	pedp := &swed.Pldat[SEI_EARTH]
+	psbdp := &swed.Pldat[SEI_SUNBARY]
+	oe := &swed.Oec2000
+	// the conversions will be done with xx[].
+	xx := psbdp.X
+	// light-time
+	if iflag&SEFLG_TRUEPOS == 0 {
+		dt := math.Sqrt(SquareSum(xx[:])) * AUNIT / CLIGHT / 86400.0
+		for i := 0; i <= 2; i++ {
+			xx[i] -= dt * xx[i+3] // apparent position
+		}
 	}
-	if ipli < SEI_MARS || ipli > SEI_PLUTO {
-		return nil
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
 	}
-	for i := 0; i <= 5; i++ {
-		(*xx)[i] += (*xcom)[i]
+	// ICRS to J2000
+	if iflag&SEFLG_ICRS == 0 && swiGetDenum(SEI_SUN, iflag) >= 403 {
+		SwiBias(xx[:], pedp.Teval, iflag, false)
 	}
-	return nil
+	// save J2000 coordinates; required for sidereal positions
+	xxsv := xx
+	// precession, equator 2000 -> equator of date
+	if iflag&SEFLG_J2000 == 0 {
+		swiPrecess(xx[:], psbdp.Teval, iflag, J2000_TO_J)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(xx[:], psbdp.Teval, iflag, J2000_TO_J)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(pedp, iflag, xx[:], xxsv[:], oe, serr)
 }
 
-// ===== 3588 ===== swi_nutate sweph.c-3588 =========================================================================
+// ===== 4309 ===== app_pos_etc_mean sweph.c-4309 ====================================================================
 
-// SwiNutate multiplies cartesian equatorial coordinates with previously calculated nutation matrix. also corrects speed.
-func SwiNutate(xx []float64, iflag int32, backward bool) {
-	x := make([]float64, 6)
-	xv := make([]float64, 6)
-	for i := 0; i <= 2; i++ {
-		if backward {
-			x[i] = xx[0]*swed.Nut.Matrix[i][0] +
-				xx[1]*swed.Nut.Matrix[i][1] +
-				xx[2]*swed.Nut.Matrix[i][2]
-		} else {
-			x[i] = xx[0]*swed.Nut.Matrix[0][i] +
-				xx[1]*swed.Nut.Matrix[1][i] +
-				xx[2]*swed.Nut.Matrix[2][i]
-		}
+// appPosEtcMean transforms the mean lunar node or apogee (ipl SEI_MEAN_NODE or SEI_MEAN_APOG) from the polar
+// coordinates of the ecliptic of date to the equatorial and ecliptical coordinates according to iflag. There are no
+// heliocentric positions.
+func appPosEtcMean(ipl int, iflag int32, serr *string) int {
+	var xx, xxsv [6]float64
+	pdp := &swed.Nddat[ipl]
+	// if the same conversions have already been done for the same date, then return
+	flg1 := iflag &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	flg2 := pdp.Xflgs &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	if flg1 == flg2 {
+		pdp.Xflgs = iflag
+		pdp.Iephe = iflag & SEFLG_EPHMASK
+		return OK
 	}
-	if (iflag & SEFLG_SPEED) != 0 {
-		// Correct speed:
-		// First correct rotation
-		for i := 0; i <= 2; i++ {
-			if backward {
-				x[i+3] = xx[3]*swed.Nut.Matrix[i][0] +
-					xx[4]*swed.Nut.Matrix[i][1] +
-					xx[5]*swed.Nut.Matrix[i][2]
-			} else {
-				x[i+3] = xx[3]*swed.Nut.Matrix[0][i] +
-					xx[4]*swed.Nut.Matrix[1][i] +
-					xx[5]*swed.Nut.Matrix[2][i]
-			}
-		}
-		// Then apparent motion due to change of nutation during day.
-		// This makes a difference of 0.01"
-		for i := 0; i <= 2; i++ {
-			if backward {
-				xv[i] = xx[0]*swed.Nutv.Matrix[i][0] +
-					xx[1]*swed.Nutv.Matrix[i][1] +
-					xx[2]*swed.Nutv.Matrix[i][2]
-			} else {
-				xv[i] = xx[0]*swed.Nutv.Matrix[0][i] +
-					xx[1]*swed.Nutv.Matrix[1][i] +
-					xx[2]*swed.Nutv.Matrix[2][i]
-			}
-			// New speed
-			xx[i+3] = x[i+3] + (x[i]-xv[i])/NUT_SPEED_INTV
-		}
+	xx = pdp.X
+	// cartesian equatorial coordinates
+	swiPolcartSp(xx[:], xx[:])
+	swiCoortrf2(xx[:], xx[:], -swed.Oec.Seps, swed.Oec.Ceps)
+	swiCoortrf2(xx[3:], xx[3:], -swed.Oec.Seps, swed.Oec.Ceps)
+	if iflag&SEFLG_SPEED == 0 {
+		clear(xx[3:])
 	}
-	// New position
-	for i := 0; i <= 2; i++ {
-		xx[i] = x[i]
+	// Port: the J2000 coordinates in xxsv are only required for sidereal positions, which are not supported.
+	// if no precession, equator of date -> equator 2000
+	oe := &swed.Oec
+	if iflag&SEFLG_J2000 != 0 {
+		swiPrecess(xx[:], pdp.Teval, iflag, J_TO_J2000)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(xx[:], pdp.Teval, iflag, J_TO_J2000)
+		}
+		oe = &swed.Oec2000
 	}
+	return appPosRest(pdp, iflag, xx[:], xxsv[:], oe, serr)
 }
 
 // ========= 4360 ======== get_new_segment sweph.c-4360 =============================================================
@@ -349,24 +2874,17 @@ func SwiNutate(xx []float64, iflag int32, backward bool) {
 
 func getNewSegment(tjd float64, ipli, ifno int) (int, string) {
 	var serr string
-	var fendianValue int
 	pdp := &swed.Pldat[ipli]
 	fdp := &swed.Fidat[ifno]
 	fp := fdp.Fptr
-	freord := (fdp.Iflg & SEI_FILE_REORD) != 0
-	fendian := (fdp.Iflg & SEI_FILE_LITENDIAN) != 0
+	order := fileByteOrder(fdp.Iflg)
 	// Compute segment number
 	iseg := int32((tjd - pdp.Tfstart) / pdp.Dseg)
 	pdp.Tseg0 = pdp.Tfstart + float64(iseg)*pdp.Dseg
 	pdp.Tseg1 = pdp.Tseg0 + pdp.Dseg
-	// Get file position of coefficients
-	fpos := pdp.Lndx0 + int32(iseg)*3
-	var fposBytes [4]byte
-	fendianValue = 0
-	if fendian {
-		fendianValue = 1
-	}
-	retc, _ := doFread(&fposBytes, 3, 1, 4, fp, fpos, freord, fendianValue, ifno)
+	// Get file position of coefficients from file
+	fpos := pdp.Lndx0 + iseg*3
+	retc, _ := doFread(&fpos, 3, 1, 4, fp, fpos, order, ifno)
 	if retc != OK {
 		return returnErrorGns(fdp)
 	}
@@ -374,20 +2892,17 @@ func getNewSegment(tjd float64, ipli, ifno int) (int, string) {
 	if _, err := fp.Seek(int64(fpos), io.SeekStart); err != nil {
 		return returnErrorGns(fdp)
 	}
-	// Allocate space for Chebyshev coefficients
+	// Clear space of Chebyshev coefficients
 	if pdp.Segp == nil {
 		pdp.Segp = make([]float64, pdp.Ncoe*3)
 	}
+	clear(pdp.Segp)
 	// Read coefficients for 3 coordinates
 	for icoord := 0; icoord < 3; icoord++ {
 		idbl := icoord * pdp.Ncoe
-		// Read header
+		// first read header; first bit indicates number of sizes of packed coefficients
 		var c [4]byte
-		fendianValue = 0
-		if fendian {
-			fendianValue = 1
-		}
-		retc, _ = doFread(&c, 1, 2, 1, fp, SEI_CURR_FPOS, freord, fendianValue, ifno)
+		retc, _ = doFread(c[:2], 1, 2, 1, fp, SEI_CURR_FPOS, order, ifno)
 		if retc != OK {
 			return returnErrorGns(fdp)
 		}
@@ -397,11 +2912,7 @@ func getNewSegment(tjd float64, ipli, ifno int) (int, string) {
 		var nco int
 		if c[0]&128 != 0 {
 			nsizes = 6
-			fendianValue = 0
-			if fendian {
-				fendianValue = 1
-			}
-			retc, _ = doFread(c[2:], 1, 2, 1, fp, SEI_CURR_FPOS, freord, fendianValue, ifno)
+			retc, _ = doFread(c[2:], 1, 2, 1, fp, SEI_CURR_FPOS, order, ifno)
 			if retc != OK {
 				return returnErrorGns(fdp)
 			}
@@ -420,7 +2931,7 @@ func getNewSegment(tjd float64, ipli, ifno int) (int, string) {
 			nsize[3] = int(c[1]) % 16
 			nco = nsize[0] + nsize[1] + nsize[2] + nsize[3]
 		}
-		// Check coefficient count
+		// there may not be more coefficients than interpolation order + 1
 		if nco > pdp.Ncoe {
 			serr = fmt.Sprintf("error in ephemeris file: %d coefficients instead of %d. ",
 				nco, pdp.Ncoe)
@@ -431,60 +2942,82 @@ func getNewSegment(tjd float64, ipli, ifno int) (int, string) {
 			pdp.Segp = nil
 			return ERR, serr
 		}
-		// Unpack coefficients
-		if err := unpackCoefficients(pdp, fdp, fp, nsizes, nsize, idbl, freord, fendian, ifno); err != nil {
+		// now unpack
+		if err := unpackCoefficients(pdp, fp, nsizes, nsize, idbl, order, ifno); err != nil {
 			return returnErrorGns(fdp)
 		}
 	}
 	return OK, ""
 }
 
-// helper function for GetNewSegments
-func unpackCoefficients(pdp *PlanData, fdp *FileData, fp *os.File,
-	nsizes int, nsize [6]int, idbl int,
-	freord bool, fendian bool, ifno int) error {
+// unpackCoefficients reads and unpacks the coefficients of one coordinate. nsize contains the number of coefficients
+// that are packed into 4, 3, 2 and 1 byte(s), half bytes and quarter bytes.
+// Port: separated from get_new_segment().
+func unpackCoefficients(pdp *PlanData, fp *os.File, nsizes int, nsize [6]int, idbl int,
+	order binary.ByteOrder, ifno int) error {
 	longs := make([]uint32, MAXORD+1)
 	for i := 0; i < nsizes; i++ {
 		if nsize[i] == 0 {
 			continue
 		}
 		if i < 4 {
-			// Handle full byte packing
 			j := 4 - i
 			k := nsize[i]
-			fendianValue := 0
-			if fendian {
-				fendianValue = 1
-			}
-			retc, errStr := doFread(&longs, j, k, 4, fp, SEI_CURR_FPOS, freord, fendianValue, ifno)
+			retc, errStr := doFread(longs, j, k, 4, fp, SEI_CURR_FPOS, order, ifno)
 			if retc != OK {
-				return fmt.Errorf(errStr)
+				return errors.New(errStr)
 			}
-			for m := 0; m < k; m++ {
-				if longs[m]&1 != 0 { // negative
-					pdp.Segp[idbl+m] = -float64((longs[m]+1)/2) / 1e9 * pdp.Rmax / 2
+			for m := 0; m < k; m, idbl = m+1, idbl+1 {
+				if longs[m]&1 != 0 { // will be negative
+					pdp.Segp[idbl] = -(float64((longs[m]+1)/2) / 1e+9 * pdp.Rmax / 2)
 				} else {
-					pdp.Segp[idbl+m] = float64(longs[m]/2) / 1e9 * pdp.Rmax / 2
+					pdp.Segp[idbl] = float64(longs[m]/2) / 1e+9 * pdp.Rmax / 2
 				}
 			}
-			idbl += k
-		} else if i == 4 {
-			// Handle half byte packing
-			if err := unpackHalfBytes(pdp, fp, longs, nsize[i], idbl, freord, fendian, ifno); err != nil {
-				return err
+		} else if i == 4 { // half byte packing
+			idbl = unpackSubBytes(pdp, longs, fp, nsize[i], 2, idbl, order, ifno)
+			if idbl < 0 {
+				return errors.New("Ephemeris file is damaged")
 			}
-		} else if i == 5 {
-			// Handle quarter byte packing
-			if err := unpackQuarterBytes(pdp, fp, longs, nsize[i], idbl, freord, fendian, ifno); err != nil {
-				return err
+		} else if i == 5 { // quarter byte packing
+			idbl = unpackSubBytes(pdp, longs, fp, nsize[i], 4, idbl, order, ifno)
+			if idbl < 0 {
+				return errors.New("Ephemeris file is damaged")
 			}
 		}
 	}
 	return nil
 }
 
-// helper function for GetNewSegments
+// unpackSubBytes reads and unpacks n coefficients that are packed into half bytes (nperbyte = 2) or quarter bytes
+// (nperbyte = 4), the first coefficient in the highest bits. Returns the index of the next coefficient or -1 in case of
+// a read error.
+// Port: separated from get_new_segment(), where the loops for half and quarter bytes only differ in these numbers.
+func unpackSubBytes(pdp *PlanData, longs []uint32, fp *os.File, n, nperbyte, idbl int,
+	order binary.ByteOrder, ifno int) int {
+	k := (n + nperbyte - 1) / nperbyte
+	retc, _ := doFread(longs, 1, k, 4, fp, SEI_CURR_FPOS, order, ifno)
+	if retc != OK {
+		return -1
+	}
+	// value of the lowest bit of the first coefficient in a byte: 16 for half bytes, 64 for quarter bytes
+	o0 := uint32(256) >> (8 / nperbyte)
+	// divisor from one coefficient to the next: 16 for half bytes, 4 for quarter bytes
+	div := uint32(1) << (8 / nperbyte)
+	for m, j := 0, 0; m < k && j < n; m++ {
+		for i, o := 0, o0; i < nperbyte && j < n; i, j, idbl, o = i+1, j+1, idbl+1, o/div {
+			if longs[m]&o != 0 {
+				pdp.Segp[idbl] = -(float64((longs[m]+o)/o/2) * pdp.Rmax / 2 / 1e+9)
+			} else {
+				pdp.Segp[idbl] = float64(longs[m]/o/2) * pdp.Rmax / 2 / 1e+9
+			}
+			longs[m] %= o
+		}
+	}
+	return idbl
+}
 
+// returnErrorGns closes the file and clears the planet data, after an error in getNewSegment.
 func returnErrorGns(fdp *FileData) (int, string) {
 	if fdp.Fptr != nil {
 		fdp.Fptr.Close()
@@ -494,215 +3027,508 @@ func returnErrorGns(fdp *FileData) (int, string) {
 	return ERR, "Error in get_new_segment"
 }
 
-// TODO check this  Claude Sonnet suggested this function but it is not clear why, it is a helper function for GetNewSegments
-func unpackHalfBytes(pdp *PlanData, fp *os.File, longs []uint32, nsize, idbl int,
-	freord bool, fendian bool, ifno int) error {
-	// Calculate how many bytes we need to read
-	// Each long (4 bytes) contains 2 half-byte values
-	k := (nsize + 1) / 2
-	// Read the packed values
-	fendianValue := 0
-	if fendian {
-		fendianValue = 1
+// ===== 4509 ===== read_const sweph.c-4509 ==========================================================================
+
+// readConst reads the constants from the header of the open ephemeris file ifno and stores them in swed.
+// In case of an error, the file is closed and the planet data are cleared.
+// Port: the header is read by readFileHeader, this function only stores the result.
+func readConst(ifno int) error {
+	fdp := &swed.Fidat[ifno]
+	h, err := readFileHeader(fdp.Fptr, fdp.Fnam, ifno)
+	if err != nil {
+		if fdp.Fptr != nil {
+			fdp.Fptr.Close()
+		}
+		fdp.Fptr = nil
+		freePlanets()
+		return err
 	}
-	retc, errStr := doFread(&longs, 1, k, 4, fp, SEI_CURR_FPOS, freord, fendianValue, ifno)
-	if retc != OK {
-		return fmt.Errorf(errStr)
-	}
-	// Current position in the output array
-	currIdbl := idbl
-	// Process each long value
-	for m := 0; m < k && (m*2) < nsize; m++ {
-		value := longs[m]
-		// Process first half (16s position)
-		if (m * 2) < nsize {
-			if value&16 != 0 {
-				// Negative value
-				pdp.Segp[currIdbl] = -float64((value+16)/16/2) * pdp.Rmax / 2 / 1e9
-			} else {
-				// Positive value
-				pdp.Segp[currIdbl] = float64(value/16/2) * pdp.Rmax / 2 / 1e9
-			}
-			currIdbl++
+	fptr := fdp.Fptr
+	*fdp = h.File
+	fdp.Fptr = fptr
+	swed.Gcdat = h.Gcdat
+	if ifno == SEI_FILE_ANY_AST {
+		// save elements, they are required for swe_plan_pheno()
+		swed.Astelem = [AS_MAXCH * 10]byte{}
+		copy(swed.Astelem[:len(swed.Astelem)-1], h.Astelem)
+		swed.AstH = h.AstH
+		swed.AstG = h.AstG
+		swed.AstDiam = h.AstDiam
+	}
+	for kpl := range h.Planets {
+		ipli := int(fdp.Ipl[kpl])
+		var pdp *PlanData
+		if ipli >= SE_PLMOON_OFFSET {
+			pdp = &swed.Pldat[SEI_ANYBODY]
+		} else {
+			pdp = &swed.Pldat[ipli]
 		}
-		// Process second half (1s position)
-		if (m*2 + 1) < nsize {
-			value = value % 16 // Remove the first half
-			if value&1 != 0 {
-				// Negative value
-				pdp.Segp[currIdbl] = -float64((value+1)/2) * pdp.Rmax / 2 / 1e9
-			} else {
-				// Positive value
-				pdp.Segp[currIdbl] = float64(value/2) * pdp.Rmax / 2 / 1e9
+		pd := &h.Planets[kpl]
+		pdp.Ibdy = pd.Ibdy
+		pdp.Lndx0 = pd.Lndx0
+		pdp.Iflg = pd.Iflg
+		pdp.Ncoe = pd.Ncoe
+		pdp.Rmax = pd.Rmax
+		pdp.Tfstart = pd.Tfstart
+		pdp.Tfend = pd.Tfend
+		pdp.Dseg = pd.Dseg
+		pdp.Nndx = pd.Nndx
+		pdp.Telem = pd.Telem
+		pdp.Prot = pd.Prot
+		pdp.Dprot = pd.Dprot
+		pdp.Qrot = pd.Qrot
+		pdp.Dqrot = pd.Dqrot
+		pdp.Peri = pd.Peri
+		pdp.Dperi = pd.Dperi
+		if pd.Iflg&SEI_FLG_ELLIPSE != 0 {
+			// if switch to other eph. file
+			if pdp.Refep != nil {
+				pdp.Segp = nil
 			}
-			currIdbl++
+			pdp.Refep = pd.Refep
 		}
 	}
 	return nil
 }
 
-// TODO check this  Claude Sonnet suggested this function but it is not clear why, it is a helper function for GetNewSegments
+// CorruptFileError is returned if an ephemeris file is damaged, e.g. truncated by an incomplete download or with a
+// wrong CRC. Code is the letter that the C version adds to the error message, Reason describes the damage.
+// Port: in the C version, only an error message is returned.
+type CorruptFileError struct {
+	Fnam   string
+	Code   string
+	Reason string
+}
+
+func (e *CorruptFileError) Error() string {
+	return fmt.Sprintf("Ephemeris file %s is damaged (0%s): %s", e.Fnam, e.Code, e.Reason)
+}
 
-func unpackQuarterBytes(pdp *PlanData, fp *os.File, longs []uint32, nsize, idbl int,
-	freord bool, fendian bool, ifno int) error {
-	// Calculate how many bytes we need to read
-	// Each long (4 bytes) contains 4 quarter-byte values
-	k := (nsize + 3) / 4
-	// Read the packed values
-	fendianValue := 0
-	if fendian {
-		fendianValue = 1
+// readFileHeader reads the header of an ephemeris file: version, file name, copyright, orbital elements (if single
+// asteroid), byte order, file length, DE number, time range, planet numbers, general constants and the constants of
+// the planets.
+// fp		file pointer, positioned at the start of the file
+// fnam		file name, may include the path
+// ifno		file number (SEI_FILE_*)
+// Returns a *CorruptFileError if the file is damaged, e.g. if it is truncated or if the CRC of the header is wrong.
+// Port: contains the reading part of read_const().
+func readFileHeader(fp *os.File, fnam string, ifno int) (*FileHeader, error) {
+	const lastnam = 19
+	var sastnam string
+	var lng int32
+	var nplan int16
+	nbytesIpl := 2
+	h := &FileHeader{}
+	fdp := &h.File
+	fdp.Fnam = fnam
+	fileDamage := func(smsg, reason string) error {
+		return &CorruptFileError{Fnam: fnam, Code: smsg, Reason: reason}
 	}
-	retc, errStr := doFread(&longs, 1, k, 4, fp, SEI_CURR_FPOS, freord, fendianValue, ifno)
-	if retc != OK {
-		return fmt.Errorf(errStr)
-	}
-	// Current position in the output array
-	currIdbl := idbl
-	// Process each long value
-	for m := 0; m < k && (m*4) < nsize; m++ {
-		value := longs[m]
-		// Process four quarters (64s, 16s, 4s, 1s positions)
-		for n := 0; n < 4 && (m*4+n) < nsize; n++ {
-			switch n {
-			case 0:
-				// First quarter (64s position)
-				if value&64 != 0 {
-					pdp.Segp[currIdbl] = -float64((value+64)/64/2) * pdp.Rmax / 2 / 1e9
-				} else {
-					pdp.Segp[currIdbl] = float64(value/64/2) * pdp.Rmax / 2 / 1e9
-				}
-				value = value % 64
-			case 1:
-				// Second quarter (16s position)
-				if value&16 != 0 {
-					pdp.Segp[currIdbl] = -float64((value+16)/16/2) * pdp.Rmax / 2 / 1e9
-				} else {
-					pdp.Segp[currIdbl] = float64(value/16/2) * pdp.Rmax / 2 / 1e9
-				}
-				value = value % 16
-			case 2:
-				// Third quarter (4s position)
-				if value&4 != 0 {
-					pdp.Segp[currIdbl] = -float64((value+4)/4/2) * pdp.Rmax / 2 / 1e9
-				} else {
-					pdp.Segp[currIdbl] = float64(value/4/2) * pdp.Rmax / 2 / 1e9
-				}
-				value = value % 4
-			case 3:
-				// Fourth quarter (1s position)
-				if value&1 != 0 {
-					pdp.Segp[currIdbl] = -float64((value+1)/2) * pdp.Rmax / 2 / 1e9
-				} else {
-					pdp.Segp[currIdbl] = float64(value/2) * pdp.Rmax / 2 / 1e9
-				}
+	if fp == nil {
+		return nil, fmt.Errorf("Ephemeris file %s is not open. ", fnam)
+	}
+	// version number of file
+	s, ok := fgets(fp, AS_MAXCH)
+	if !ok || !strings.Contains(s, "\r\n") {
+		return nil, fileDamage("", "no version line")
+	}
+	s = s[:strings.IndexByte(s, '\r')]
+	sp := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' })
+	if sp < 0 {
+		return nil, fileDamage("a", "no version number")
+	}
+	// version unused so far
+	fdp.Fversion = atoi(s[sp:])
+	// correct file name?
+	s, ok = fgets(fp, AS_MAXCH)
+	if !ok || !strings.Contains(s, "\r\n") {
+		return nil, fileDamage("b", "no file name")
+	}
+	// file name, without path
+	s2 := fnam
+	if i := strings.LastIndex(fnam, DIR_GLUE); i >= 0 {
+		s2 = fnam[i+1:]
+	}
+	s2 = strings.ToLower(s2)
+	// prepare string of should-be file name
+	s = strings.ToLower(strings.TrimRight(s, "\n\r "))
+	if s2 != s {
+		return nil, fmt.Errorf("Ephemeris file name '%s' wrong; rename '%s' ", s2, s)
+	}
+	// copyright
+	s, ok = fgets(fp, AS_MAXCH)
+	if !ok || !strings.Contains(s, "\r\n") {
+		return nil, fileDamage("c", "no copyright line")
+	}
+	// orbital elements, if single asteroid
+	if ifno == SEI_FILE_ANY_AST {
+		s, ok = fgets(fp, AS_MAXCH*2)
+		if !ok || !strings.Contains(s, "\r\n") {
+			return nil, fileDamage("d", "no orbital elements")
+		}
+		// MPC number and name; will be analyzed below: search "asteroid name"
+		i := 0
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		i++
+		sastnam = s[:min(lastnam+i, len(s))]
+		// save elements, they are required for swe_plan_pheno()
+		h.Astelem = s
+		// required for magnitude
+		h.AstH = atofAt(s, 35+i)
+		h.AstG = atofAt(s, 42+i)
+		if h.AstG == 0 {
+			h.AstG = 0.15
+		}
+		// diameter in kilometers, not always given
+		if 51+i < len(s) {
+			h.AstDiam = atof(s[51+i : min(58+i, len(s))])
+		}
+		if h.AstDiam == 0 {
+			// estimate the diameter from magnitude; assume albedo = 0.15
+			h.AstDiam = 1329 / math.Sqrt(0.15) * math.Pow(10, -0.2*h.AstH)
+		}
+	}
+	// one int32 for test of byte order
+	var testendian [4]byte
+	if _, err := io.ReadFull(fp, testendian[:]); err != nil {
+		return nil, fileDamage("e", "no test of byte order")
+	}
+	// byte order of the file: the test integer is written in the byte order of the file
+	var order binary.ByteOrder
+	switch uint32(SEI_FILE_TEST_ENDIAN) {
+	case binary.LittleEndian.Uint32(testendian[:]):
+		order = binary.LittleEndian
+		fdp.Iflg = SEI_FILE_LITENDIAN
+	case binary.BigEndian.Uint32(testendian[:]):
+		order = binary.BigEndian
+		fdp.Iflg = SEI_FILE_BIGENDIAN
+	default:
+		return nil, fileDamage("f", "wrong test of byte order")
+	}
+	// Port: the bytes are never reordered (see doFread), SEI_FILE_REORD only indicates that the byte order of the
+	// file differs from the byte order of the host, as in the C version
+	if order.Uint16([]byte{1, 0}) != binary.NativeEndian.Uint16([]byte{1, 0}) {
+		fdp.Iflg |= SEI_FILE_REORD
+	}
+	read := func(trg interface{}, size, count, corrsize int, fpos int32) error {
+		if retc, _ := doFread(trg, size, count, corrsize, fp, fpos, order, ifno); retc != OK {
+			return fileDamage("", "unexpected end of file")
+		}
+		return nil
+	}
+	// length of file correct?
+	if err := read(&lng, 4, 1, 4, SEI_CURR_FPOS); err != nil {
+		return nil, err
+	}
+	fpos, err := fp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fileDamage("g", "file length unknown")
+	}
+	flen, err := fp.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fileDamage("g", "file length unknown")
+	}
+	if int64(lng) != flen {
+		return nil, fileDamage("h", fmt.Sprintf("file length %d instead of %d", flen, lng))
+	}
+	// DE number of JPL ephemeris which this file is based on
+	if err = read(&fdp.SwephDenum, 4, 1, 4, int32(fpos)); err != nil {
+		return nil, err
+	}
+	// start and end epoch of file
+	if err = read(&fdp.Tfstart, 8, 1, 8, SEI_CURR_FPOS); err != nil {
+		return nil, err
+	}
+	if err = read(&fdp.Tfend, 8, 1, 8, SEI_CURR_FPOS); err != nil {
+		return nil, err
+	}
+	// how many planets are in file?
+	if err = read(&nplan, 2, 1, 2, SEI_CURR_FPOS); err != nil {
+		return nil, err
+	}
+	if nplan > 256 {
+		nbytesIpl = 4
+		nplan %= 256
+	}
+	if nplan < 1 || nplan > 20 {
+		return nil, fileDamage("i", fmt.Sprintf("%d planets", nplan))
+	}
+	fdp.Npl = nplan
+	// which ones?
+	if err = read(fdp.Ipl[:nplan], nbytesIpl, int(nplan), 4, SEI_CURR_FPOS); err != nil {
+		return nil, err
+	}
+	// asteroid name
+	if ifno == SEI_FILE_ANY_AST {
+		var s30 [30]byte
+		// name of asteroid is taken from orbital elements record read above
+		// old astorb.dat had only 4 characters for MPC#, new astorb.dat has 5
+		j := 4
+		for j < 10 && j < len(sastnam) && sastnam[j] != ' ' {
+			j++
+		}
+		i := atoi(sastnam[:min(j, len(sastnam))])
+		if i == int(fdp.Ipl[0])-SE_AST_OFFSET || i == int(fdp.Ipl[0]) { // planetary moon
+			// element record is from bowell database
+			if j+1 < len(sastnam) {
+				fdp.Astnam = sastnam[j+1 : min(j+1+lastnam, len(sastnam))]
+			}
+			// overread old ast. name field
+			if _, err = io.ReadFull(fp, s30[:]); err != nil {
+				return nil, fileDamage("j", "no asteroid name")
 			}
-			currIdbl++
+		} else {
+			// older elements record structure: the name is taken from old name field
+			if _, err = io.ReadFull(fp, s30[:]); err != nil {
+				return nil, fileDamage("k", "no asteroid name")
+			}
+			fdp.Astnam = string(s30[:])
+		}
+		if i := strings.IndexByte(fdp.Astnam, 0); i >= 0 {
+			fdp.Astnam = fdp.Astnam[:i]
+		}
+		fdp.Astnam = strings.TrimRight(fdp.Astnam, " ")
+		if i := strings.Index(fdp.Astnam, "  "); i >= 0 {
+			fdp.Astnam = fdp.Astnam[:i]
 		}
 	}
-	return nil
+	// check CRC
+	if fpos, err = fp.Seek(0, io.SeekCurrent); err != nil {
+		return nil, fileDamage("m", "no CRC")
+	}
+	// read CRC from file
+	var ulng uint32
+	if err = read(&ulng, 4, 1, 4, SEI_CURR_FPOS); err != nil {
+		return nil, err
+	}
+	// read check area from file; must check that defined length of s is less than fpos
+	if fpos-1 > 2*AS_MAXCH {
+		return nil, fileDamage("l", fmt.Sprintf("header of %d bytes", fpos))
+	}
+	checkArea := make([]byte, fpos)
+	if _, err = fp.Seek(0, io.SeekStart); err != nil {
+		return nil, fileDamage("m", "no CRC")
+	}
+	if _, err = io.ReadFull(fp, checkArea); err != nil {
+		return nil, fileDamage("m", "no CRC")
+	}
+	if swiCrc32(checkArea) != ulng {
+		return nil, fileDamage("n", "wrong CRC")
+	}
+	if _, err = fp.Seek(fpos+4, io.SeekStart); err != nil {
+		return nil, fileDamage("m", "no CRC")
+	}
+	// read general constants: clight, aunit, helgravconst, ratme, sunradius; these constants are currently not in use
+	doubles := make([]float64, 10)
+	if err = read(doubles, 8, 5, 8, SEI_CURR_FPOS); err != nil {
+		return nil, err
+	}
+	h.Gcdat = GenConst{Clight: doubles[0], Aunit: doubles[1], Helgravconst: doubles[2], Ratme: doubles[3],
+		Sunradius: doubles[4]}
+	// read constants of planets
+	h.Planets = make([]PlanData, fdp.Npl)
+	for kpl := 0; kpl < int(fdp.Npl); kpl++ {
+		ipli := int(fdp.Ipl[kpl])
+		pdp := &h.Planets[kpl]
+		pdp.Ibdy = ipli
+		// file position of planet's index
+		if err = read(&pdp.Lndx0, 4, 1, 4, SEI_CURR_FPOS); err != nil {
+			return nil, err
+		}
+		// flags: helio/geocentric, rotation, reference ellipse
+		if err = read(&pdp.Iflg, 1, 1, 4, SEI_CURR_FPOS); err != nil {
+			return nil, err
+		}
+		// number of chebyshew coefficients / segment = interpolation order +1
+		if err = read(&pdp.Ncoe, 1, 1, 4, SEI_CURR_FPOS); err != nil {
+			return nil, err
+		}
+		// rmax = normalisation factor
+		if err = read(&lng, 4, 1, 4, SEI_CURR_FPOS); err != nil {
+			return nil, err
+		}
+		pdp.Rmax = float64(lng) / 1000.0
+		// planet's center of body, e.g. 9599 for Jupiter or Mars moons
+		if ipli >= SE_PLMOON_OFFSET && ipli < SE_AST_OFFSET {
+			if ipli%100 == 99 || (ipli-9000)/100 == SE_MARS {
+				pdp.Rmax = float64(lng) / 1000000.0
+			}
+		}
+		// start and end epoch of planetary ephemeris, segment length, and orbital elements
+		if err = read(doubles, 8, 10, 8, SEI_CURR_FPOS); err != nil {
+			return nil, err
+		}
+		pdp.Tfstart = doubles[0]
+		pdp.Tfend = doubles[1]
+		pdp.Dseg = doubles[2]
+		pdp.Nndx = int32((doubles[1] - doubles[0] + 0.1) / doubles[2])
+		pdp.Telem = doubles[3]
+		pdp.Prot = doubles[4]
+		pdp.Dprot = doubles[5]
+		pdp.Qrot = doubles[6]
+		pdp.Dqrot = doubles[7]
+		pdp.Peri = doubles[8]
+		pdp.Dperi = doubles[9]
+		// if reference ellipse is used, read its coefficients
+		if pdp.Iflg&SEI_FLG_ELLIPSE != 0 {
+			if pdp.Ncoe < 1 || pdp.Ncoe > MAXORD+1 {
+				return nil, fileDamage("o", fmt.Sprintf("%d coefficients", pdp.Ncoe))
+			}
+			pdp.Refep = make([]float64, 2*pdp.Ncoe)
+			if err = read(pdp.Refep, 8, 2*pdp.Ncoe, 8, SEI_CURR_FPOS); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return h, nil
+}
+
+// fgets reads a line of at most maxch-1 bytes from fp, including the newline, like the C function fgets.
+// Returns false if nothing could be read.
+// Port: added as a replacement for the C library function.
+func fgets(fp *os.File, maxch int) (string, bool) {
+	var sb strings.Builder
+	b := make([]byte, 1)
+	for sb.Len() < maxch-1 {
+		if n, err := fp.Read(b); n == 0 || err != nil {
+			break
+		}
+		sb.WriteByte(b[0])
+		if b[0] == '\n' {
+			break
+		}
+	}
+	return sb.String(), sb.Len() > 0
+}
+
+// ReadFileHeader reads the header of the Swiss Ephemeris file fnam (including the path), without changing the files
+// that are in use. The type of file (planets, moon, main asteroids or a single asteroid or planetary moon) is derived
+// from the file name, as generated by swiGenFilename.
+// Port: not in the C version.
+func ReadFileHeader(fnam string) (*FileHeader, error) {
+	fp, err := os.Open(fnam)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return readFileHeader(fp, filepath.ToSlash(fnam), fileNumberFromName(fnam))
+}
+
+// fileNumberFromName returns the file number (SEI_FILE_*) for the name of an ephemeris file.
+func fileNumberFromName(fnam string) int {
+	s := strings.ToLower(filepath.Base(fnam))
+	switch {
+	case strings.HasPrefix(s, "sepl"):
+		return SEI_FILE_PLANET
+	case strings.HasPrefix(s, "semo"):
+		return SEI_FILE_MOON
+	case strings.HasPrefix(s, "seas"):
+		return SEI_FILE_MAIN_AST
+	}
+	return SEI_FILE_ANY_AST
 }
 
 // ===== 4889 ===== do_fread sweph.c-4889 ===========================================================================
 
 // SWISSEPH
-// reads from a file and, if necessary, reorders bytes
+// reads from a file and converts the items from the byte order of the file
 // targ 	target pointer
 // size		size of item to be read
 // count	number of items
 // corrsize	in what size should it be returned (e.g. 3 byte int -> 4 byte int)
 // fp		file pointer
 // fpos		file position: if (fpos >= 0) then fseek
-// freord	reorder bytes or no
-// fendian	little/bigendian
+// order	byte order of the file, see fileByteOrder
 // ifno		file number
 // serr		error string
+// Port: the C version reorders the bytes if the byte order of the file differs from the byte order of the host
+// (freord and fendian). Here, the items are always decoded with the byte order of the file, independent of the host.
 
-func doFread(trg interface{}, size, count, corrsize int, fp *os.File, fpos int32,
-	freord bool, fendian int, ifno int) (int, string) {
-	totsize := size * count
+func doFread(trg interface{}, size, count, corrsize int, fp *os.File, fpos int32, order binary.ByteOrder,
+	ifno int) (int, string) {
 	// Seek to position if specified
 	if fpos >= 0 {
 		if _, err := fp.Seek(int64(fpos), io.SeekStart); err != nil {
 			return ERR, "Failed to seek in file"
 		}
 	}
-	// Create buffer for reading
-	space := make([]byte, totsize)
-	targ := make([]byte, count*corrsize)
-	// if no byte reorder has to be done, and read size == return size
-	if !freord && size == corrsize {
-		n, err := fp.Read(targ)
-		if err != nil || n != totsize {
-			serr := "Ephemeris file is damaged (1). "
-			if len(serr)+len(swed.Fidat[ifno].Fnam) < AS_MAXCH-1 {
-				serr = "Ephemeris file " + swed.Fidat[ifno].Fnam + " is damaged (2)."
-			}
-			return ERR, serr
-		}
-		// Copy data to target interface
-		copyToInterface(trg, targ)
-		return OK, ""
-	}
-	// Read into temporary buffer
-	n, err := fp.Read(space)
-	if err != nil || n != totsize {
-		serr := "Ephemeris file is damaged (3). "
+	space := make([]byte, size*count)
+	if _, err := io.ReadFull(fp, space); err != nil {
+		serr := "Ephemeris file is damaged (1). "
 		if len(serr)+len(swed.Fidat[ifno].Fnam) < AS_MAXCH-1 {
-			serr = "Ephemeris file " + swed.Fidat[ifno].Fnam + " is damaged (4)."
+			serr = "Ephemeris file " + swed.Fidat[ifno].Fnam + " is damaged (2)."
 		}
 		return ERR, serr
 	}
-	// Clear target if sizes don't match
-	if size != corrsize {
-		for i := range targ {
-			targ[i] = 0
-		}
+	if size == corrsize {
+		copyToInterface(trg, space, order)
+		return OK, ""
 	}
-	// Reorder bytes as needed
+	// widen each item to corrsize bytes, e.g. 3 byte int -> 4 byte int; the high order bytes are 0
+	targ := make([]byte, count*corrsize)
 	for i := 0; i < count; i++ {
-		for j := size - 1; j >= 0; j-- {
-			var k int
-			if freord {
-				k = size - j - 1
-			} else {
-				k = j
-			}
-
-			if size != corrsize {
-				if (fendian == SEI_FILE_BIGENDIAN && !freord) ||
-					(fendian == SEI_FILE_LITENDIAN && freord) {
-					k += corrsize - size
-				}
-			}
-
-			targ[i*corrsize+k] = space[i*size+j]
+		k := i * corrsize
+		if order == binary.BigEndian {
+			k += corrsize - size
 		}
+		copy(targ[k:k+size], space[i*size:(i+1)*size])
 	}
-	// Copy data to target interface
-	copyToInterface(trg, targ)
+	copyToInterface(trg, targ, order)
 	return OK, ""
 }
 
-// Helper function for doFread to copy bytes to interface
-func copyToInterface(dst interface{}, src []byte) {
+// fileByteOrder returns the byte order of an ephemeris file with the flags iflg (FileData.Iflg).
+// Port: not in the C version.
+func fileByteOrder(iflg int32) binary.ByteOrder {
+	if iflg&SEI_FILE_LITENDIAN != 0 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// Helper function for doFread to copy bytes to interface. The bytes in src are in the given byte order, see doFread.
+// Slices are filled in place, as far as their length allows.
+func copyToInterface(dst interface{}, src []byte, order binary.ByteOrder) {
 	switch v := dst.(type) {
+	case []byte:
+		copy(v, src)
 	case *[]byte:
 		copy(*v, src)
+	case *[4]byte:
+		copy(v[:], src)
+	case *int16:
+		*v = int16(order.Uint16(src))
+	case *int32:
+		*v = int32(order.Uint32(src))
+	case *uint32:
+		*v = order.Uint32(src)
+	case *int:
+		*v = int(int32(order.Uint32(src)))
+	case *float64:
+		*v = math.Float64frombits(order.Uint64(src))
+	case []int32:
+		for i := 0; i < len(v) && i*4+4 <= len(src); i++ {
+			v[i] = int32(order.Uint32(src[i*4:]))
+		}
 	case *[]int32:
-		data := make([]int32, len(src)/4)
-		for i := range data {
-			data[i] = int32(binary.LittleEndian.Uint32(src[i*4:]))
+		copyToInterface(*v, src, order)
+	case []uint32:
+		for i := 0; i < len(v) && i*4+4 <= len(src); i++ {
+			v[i] = order.Uint32(src[i*4:])
 		}
-		*v = data
-	case *[]float64:
-		data := make([]float64, len(src)/8)
-		for i := range data {
-			bits := binary.LittleEndian.Uint64(src[i*8:])
-			data[i] = float64(bits)
+	case *[]uint32:
+		copyToInterface(*v, src, order)
+	case []float64:
+		for i := 0; i < len(v) && i*8+8 <= len(src); i++ {
+			v[i] = math.Float64frombits(order.Uint64(src[i*8:]))
 		}
-		*v = data
-		// Add more types as needed   // TODO check, I do not see more data
+	case *[]float64:
+		copyToInterface(*v, src, order)
 	}
 }
 
@@ -723,18 +3549,6 @@ func rotBack(ipli int) {
 	chcfy := chcfx[nco:]
 	chcfz := chcfx[2*nco:]
 	tdiff := (t - pdp.Telem) / 365250.0
-	// Calculate rotation parameters
-	var qav, pav float64
-	if ipli == SEI_MOON {
-		dn := pdp.Prot + tdiff*pdp.Dprot
-		i := int(dn / TWOPI)
-		dn -= float64(i) * TWOPI
-		qav = (pdp.Qrot + tdiff*pdp.Dqrot) * math.Cos(dn)
-		pav = (pdp.Qrot + tdiff*pdp.Dqrot) * math.Sin(dn)
-	} else {
-		qav = pdp.Qrot + tdiff*pdp.Dqrot
-		pav = pdp.Prot + tdiff*pdp.Dprot
-	}
 	// Copy coefficients to working array
 	for i := 0; i < nco; i++ {
 		x[i][0] = chcfx[i]
@@ -745,39 +3559,14 @@ func rotBack(ipli int) {
 	if pdp.Iflg&SEI_FLG_ELLIPSE != 0 {
 		refepx := pdp.Refep
 		refepy := refepx[nco:]
-
-		omtild := pdp.Peri + tdiff*pdp.Dperi
-		i := int(omtild / TWOPI)
-		omtild -= float64(i) * TWOPI
-		com := math.Cos(omtild)
-		som := math.Sin(omtild)
-
+		com, som := refOrbitRotation(pdp, tdiff)
 		// Add reference orbit
 		for i := 0; i < nco; i++ {
 			x[i][0] = chcfx[i] + com*refepx[i] - som*refepy[i]
 			x[i][1] = chcfy[i] + com*refepy[i] + som*refepx[i]
 		}
 	}
-	// Construct right-handed orthonormal system
-	cosih2 := 1.0 / (1.0 + qav*qav + pav*pav)
-	// Calculate orbit pole
-	uiz := [3]float64{
-		2.0 * pav * cosih2,
-		-2.0 * qav * cosih2,
-		(1.0 - qav*qav - pav*pav) * cosih2,
-	}
-	// Calculate origin of longitudes vector
-	uix := [3]float64{
-		(1.0 + qav*qav - pav*pav) * cosih2,
-		2.0 * qav * pav * cosih2,
-		-2.0 * pav * cosih2,
-	}
-	// Calculate vector in orbital plane orthogonal to origin of longitudes
-	uiy := [3]float64{
-		2.0 * qav * pav * cosih2,
-		(1.0 - qav*qav + pav*pav) * cosih2,
-		2.0 * qav * cosih2,
-	}
+	uix, uiy, uiz := rotBackAxes(pdp, ipli, tdiff)
 	// Rotate to actual orientation in space
 	for i := 0; i < nco; i++ {
 		xrot := x[i][0]*uix[0] + x[i][1]*uiy[0] + x[i][2]*uiz[0]
@@ -795,12 +3584,61 @@ func rotBack(ipli int) {
 			x[i][2] = seps2000*yrot + ceps2000*zrot
 		}
 	}
-	// Copy results back
-	for i := 0; i < nco; i++ {
-		chcfx[i] = x[i][0]
-		chcfy[i] = x[i][1]
-		chcfz[i] = x[i][2]
+	// Copy results back
+	for i := 0; i < nco; i++ {
+		chcfx[i] = x[i][0]
+		chcfy[i] = x[i][1]
+		chcfz[i] = x[i][2]
+	}
+}
+
+// refOrbitRotation returns cosine and sine of the longitude of the perihelion of the reference ellipse, tdiff is the
+// time since the epoch of the elements in millennia.
+// Port: separated from rot_back(), it is also used by the writer of ephemeris files.
+func refOrbitRotation(pdp *PlanData, tdiff float64) (float64, float64) {
+	omtild := pdp.Peri + tdiff*pdp.Dperi
+	i := int(omtild / TWOPI)
+	omtild -= float64(i) * TWOPI
+	return math.Cos(omtild), math.Sin(omtild)
+}
+
+// rotBackAxes returns the axes of the coordinate system of the orbital plane: the origin of longitudes, the vector in
+// the orbital plane orthogonal to it and the orbit pole. tdiff is the time since the epoch of the elements in
+// millennia.
+// Port: separated from rot_back(), it is also used by the writer of ephemeris files.
+func rotBackAxes(pdp *PlanData, ipli int, tdiff float64) ([3]float64, [3]float64, [3]float64) {
+	var qav, pav float64
+	if ipli == SEI_MOON {
+		dn := pdp.Prot + tdiff*pdp.Dprot
+		i := int(dn / TWOPI)
+		dn -= float64(i) * TWOPI
+		qav = (pdp.Qrot + tdiff*pdp.Dqrot) * math.Cos(dn)
+		pav = (pdp.Qrot + tdiff*pdp.Dqrot) * math.Sin(dn)
+	} else {
+		qav = pdp.Qrot + tdiff*pdp.Dqrot
+		pav = pdp.Prot + tdiff*pdp.Dprot
+	}
+	// Construct right-handed orthonormal system
+	cosih2 := 1.0 / (1.0 + qav*qav + pav*pav)
+	// Calculate orbit pole
+	uiz := [3]float64{
+		2.0 * pav * cosih2,
+		-2.0 * qav * cosih2,
+		(1.0 - qav*qav - pav*pav) * cosih2,
+	}
+	// Calculate origin of longitudes vector
+	uix := [3]float64{
+		(1.0 + qav*qav - pav*pav) * cosih2,
+		2.0 * qav * pav * cosih2,
+		-2.0 * pav * cosih2,
+	}
+	// Calculate vector in orbital plane orthogonal to origin of longitudes
+	uiy := [3]float64{
+		2.0 * qav * pav * cosih2,
+		(1.0 - qav*qav + pav*pav) * cosih2,
+		2.0 * qav * cosih2,
 	}
+	return uix, uiy, uiz
 }
 
 // ===== 5055 ===== embofs sweph.c-5055 =============================================================================
@@ -844,6 +3682,485 @@ func nutMatrix(nu *Nut, oe *Epsilon) {
 	nu.Matrix[2][2] = cospsi*sineps*sineps0 + coseps*coseps0
 }
 
+// ===== 5167 ===== lunar_osc_elem sweph.c-5167 ======================================================================
+
+// lunarOscElem computes the osculating lunar node ('true node') and the osculating lunar apogee for tjd. Both are
+// always computed together and written to swed.Nddat[SEI_TRUE_NODE] and swed.Nddat[SEI_OSCU_APOG]; ipl tells which
+// of them is wanted.
+// J2000 true nodes are first computed for the ecliptic of date and then precessed to J2000.
+// If the files of the ephemeris are not available, the next ephemeris of the chain is tried, see SweSetEpheChain.
+// Port: sidereal positions are not supported, the code for SID_TNODE_FROM_ECL_T0 is not ported.
+func lunarOscElem(tjd float64, ipl int, iflag int32, serr *string) int {
+	var xpos, xx, xxa [3][6]float64
+	var r [2]float64
+	epheflag := int32(SEFLG_DEFAULTEPH)
+	speedIntv := NODE_CALC_INTV
+	oe := &swed.Oec
+	ndp := &swed.Nddat[ipl]
+	// if elements have already been computed for this date, return. if speed flag has been turned on, recompute
+	flg1 := iflag &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	flg2 := ndp.Xflgs &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	speedf1 := ndp.Xflgs & SEFLG_SPEED
+	speedf2 := iflag & SEFLG_SPEED
+	if tjd == ndp.Teval && tjd != 0 && flg1 == flg2 && (speedf2 == 0 || speedf1 != 0) {
+		ndp.Xflgs = iflag
+		ndp.Iephe = iflag & SEFLG_EPHMASK
+		return OK
+	}
+	// the geocentric position vector and the speed vector of the moon make up the lunar orbital plane. the position
+	// vector of the node is along the intersection line of the orbital plane and the plane of the ecliptic.
+	// to calculate the osculating node, we need one lunar position with speed.
+	// to calculate the speed of the osculating node, we need three lunar positions and the speed of each of them.
+	// the same is also true for the osculating apogee: we need three lunar positions and speeds.
+	//
+	// now three lunar positions with speeds
+	if iflag&SEFLG_MOSEPH != 0 {
+		epheflag = SEFLG_MOSEPH
+	} else if iflag&SEFLG_SWIEPH != 0 {
+		epheflag = SEFLG_SWIEPH
+	} else if iflag&SEFLG_JPLEPH != 0 {
+		epheflag = SEFLG_JPLEPH
+	} else if iflag&SEFLG_SPKEPH != 0 {
+		epheflag = SEFLG_SPKEPH
+	}
+	// there may be a moon of wrong ephemeris in save area, force new computation
+	swed.Pldat[SEI_MOON].Teval = 0
+	istart := 2
+	if iflag&SEFLG_SPEED != 0 {
+		istart = 0
+	}
+	if serr != nil {
+		*serr = ""
+	}
+	// dates of the three positions: tjd - speedIntv, tjd + speedIntv, tjd
+	tpos := func(i int) float64 {
+		switch i {
+		case 0:
+			return tjd - speedIntv
+		case 1:
+			return tjd + speedIntv
+		}
+		return tjd
+	}
+	for {
+		var retc int
+		switch epheflag {
+		case SEFLG_SWIEPH:
+			speedIntv = NODE_CALC_INTV
+			for i := istart; i <= 2; i++ {
+				t := tpos(i)
+				if retc = swemoon(t, iflag|SEFLG_SPEED, NO_SAVE, xpos[i][:], serr); retc == ERR {
+					return ERR
+				}
+				// light-time-corrected moon for apparent node (~ 0.006")
+				if iflag&SEFLG_TRUEPOS == 0 && retc >= OK {
+					dt := math.Sqrt(SquareSum(xpos[i][:])) * AUNIT / CLIGHT / 86400.0
+					if retc = swemoon(t-dt, iflag|SEFLG_SPEED, NO_SAVE, xpos[i][:], serr); retc == ERR {
+						return ERR
+					}
+				}
+				if retc == NOT_AVAILABLE {
+					break
+				}
+				// precession and nutation etc.
+				swiPlanForOscElem(iflag|SEFLG_SPEED, t, xpos[i][:])
+			}
+		case SEFLG_MOSEPH:
+			// with moshier moon, we need a greater speedIntv, because here the node and apogee oscillate wildly
+			// within small intervals
+			speedIntv = NODE_CALC_INTV_MOSH
+			for i := istart; i <= 2; i++ {
+				t := tpos(i)
+				if swiMoshmoon(t, NO_SAVE, xpos[i][:], serr) == ERR {
+					return ERR
+				}
+				// precession and nutation etc.
+				swiPlanForOscElem(iflag|SEFLG_SPEED, t, xpos[i][:])
+			}
+		case SEFLG_JPLEPH:
+			speedIntv = NODE_CALC_INTV
+			for i := istart; i <= 2; i++ {
+				t := tpos(i)
+				// read error or corrupt file
+				if retc = jplplan(t, SEI_MOON, iflag, NO_SAVE, xpos[i][:], nil, nil, serr); retc == ERR {
+					return ERR
+				}
+				// light-time-corrected moon for apparent node. this makes a difference of several milliarcseconds
+				// with the node and 0.1" with the apogee.
+				if iflag&SEFLG_TRUEPOS == 0 && retc >= OK {
+					dt := math.Sqrt(SquareSum(xpos[i][:])) * AUNIT / CLIGHT / 86400.0
+					if retc = jplplan(t-dt, SEI_MOON, iflag, NO_SAVE, xpos[i][:], nil, nil, serr); retc == ERR {
+						return ERR
+					}
+				}
+				// jpl ephemeris not on disk, or date beyond ephemeris range
+				if retc == NOT_AVAILABLE || retc == BEYOND_EPH_LIMITS {
+					retc = NOT_AVAILABLE
+					break
+				}
+				// precession and nutation etc.
+				swiPlanForOscElem(iflag|SEFLG_SPEED, t, xpos[i][:])
+			}
+		case SEFLG_SPKEPH:
+			speedIntv = NODE_CALC_INTV
+			for i := istart; i <= 2; i++ {
+				t := tpos(i)
+				if retc = spkplan(t, SEI_MOON, iflag, NO_SAVE, xpos[i][:], nil, nil, serr); retc == ERR {
+					return ERR
+				}
+				// light-time-corrected moon for apparent node
+				if iflag&SEFLG_TRUEPOS == 0 && retc == OK {
+					dt := math.Sqrt(SquareSum(xpos[i][:])) * AUNIT / CLIGHT / 86400.0
+					if retc = spkplan(t-dt, SEI_MOON, iflag, NO_SAVE, xpos[i][:], nil, nil, serr); retc == ERR {
+						return ERR
+					}
+				}
+				// kernels without the moon, or date beyond their range
+				if retc == NOT_AVAILABLE {
+					break
+				}
+				// precession and nutation etc.
+				swiPlanForOscElem(iflag|SEFLG_SPEED, t, xpos[i][:])
+			}
+		}
+		// if the ephemeris is not available, switch to the next one of the chain
+		if retc == NOT_AVAILABLE {
+			if iflag = epheFallback(iflag, serr); iflag == 0 {
+				return ERR
+			}
+			epheflag = iflag & SEFLG_EPHMASK
+			continue
+		}
+		break
+	}
+	// node with speed. node is always needed, even if apogee is wanted
+	ndnp := &swed.Nddat[SEI_TRUE_NODE]
+	// three nodes
+	for i := istart; i <= 2; i++ {
+		if math.Abs(xpos[i][5]) < 1e-15 {
+			xpos[i][5] = 1e-15
+		}
+		fac := xpos[i][2] / xpos[i][5]
+		sgn := xpos[i][5] / math.Abs(xpos[i][5])
+		for j := 0; j <= 2; j++ {
+			xx[i][j] = (xpos[i][j] - fac*xpos[i][j+3]) * sgn
+		}
+	}
+	// now we have the correct direction of the node, the intersection of the lunar plane and the ecliptic plane.
+	// the distance is the distance of the point where the tangent of the lunar motion penetrates the ecliptic plane.
+	// this can be very large, e.g. j2415080.37372. below, a new distance will be derived from the osculating ellipse.
+	// save position and speed
+	for i := 0; i <= 2; i++ {
+		ndnp.X[i] = xx[2][i]
+		if iflag&SEFLG_SPEED != 0 {
+			b := (xx[1][i] - xx[0][i]) / 2
+			a := (xx[1][i]+xx[0][i])/2 - xx[2][i]
+			ndnp.X[i+3] = (2*a + b) / speedIntv
+		} else {
+			ndnp.X[i+3] = 0
+		}
+		ndnp.Teval = tjd
+		ndnp.Iephe = epheflag
+	}
+	// apogee with speed, must be computed anyway to get the node's distance
+	ndap := &swed.Nddat[SEI_OSCU_APOG]
+	Gmsm := GEOGCONST * (1 + 1/EARTH_MOON_MRAT) / AUNIT / AUNIT / AUNIT * 86400.0 * 86400.0
+	// three apogees
+	for i := istart; i <= 2; i++ {
+		// node
+		rxy := math.Sqrt(xx[i][0]*xx[i][0] + xx[i][1]*xx[i][1])
+		cosnode := xx[i][0] / rxy
+		sinnode := xx[i][1] / rxy
+		// inclination
+		xnorm := swiCrossProd(xpos[i][:3], xpos[i][3:])
+		rxy = xnorm[0]*xnorm[0] + xnorm[1]*xnorm[1]
+		c2 := rxy + xnorm[2]*xnorm[2]
+		rxyz := math.Sqrt(c2)
+		rxy = math.Sqrt(rxy)
+		sinincl := rxy / rxyz
+		cosincl := math.Sqrt(1 - sinincl*sinincl)
+		// argument of latitude
+		cosu := xpos[i][0]*cosnode + xpos[i][1]*sinnode
+		sinu := xpos[i][2] / sinincl
+		uu := math.Atan2(sinu, cosu)
+		// semi-axis
+		rxyz = math.Sqrt(SquareSum(xpos[i][:]))
+		v2 := SquareSum(xpos[i][3:])
+		sema := 1 / (2/rxyz - v2/Gmsm)
+		// eccentricity
+		pp := c2 / Gmsm
+		ecce := math.Sqrt(1 - pp/sema)
+		// eccentric anomaly
+		cosE := 1 / ecce * (1 - rxyz/sema)
+		sinE := 1 / ecce / math.Sqrt(sema*Gmsm) * DotProduct(xpos[i][:], xpos[i][3:])
+		// true anomaly
+		ny := 2 * math.Atan(math.Sqrt((1+ecce)/(1-ecce))*sinE/(1+cosE))
+		// distance of apogee from ascending node
+		xxa[i][0] = Mod2PI(uu - ny + PI)
+		xxa[i][1] = 0                 // latitude
+		xxa[i][2] = sema * (1 + ecce) // distance
+		// transformation to ecliptic coordinates
+		copy(xxa[i][:], swiPolcart(xxa[i][:]))
+		swiCoortrf2(xxa[i][:], xxa[i][:], -sinincl, cosincl)
+		copy(xxa[i][:], swiCartpol(xxa[i][:]))
+		// adding node, we get apogee in ecl. coord.
+		xxa[i][0] += math.Atan2(sinnode, cosnode)
+		copy(xxa[i][:], swiPolcart(xxa[i][:]))
+		// new distance of node from orbital ellipse: true anomaly of node
+		ny = Mod2PI(ny - uu)
+		// eccentric anomaly
+		cosE = math.Cos(2 * math.Atan(math.Tan(ny/2)/math.Sqrt((1+ecce)/(1-ecce))))
+		// new distance
+		r[0] = sema * (1 - ecce*cosE)
+		// old node distance
+		r[1] = math.Sqrt(SquareSum(xx[i][:]))
+		// correct length of position vector
+		for j := 0; j <= 2; j++ {
+			xx[i][j] *= r[0] / r[1]
+		}
+	}
+	// save position and speed
+	for i := 0; i <= 2; i++ {
+		// apogee
+		ndap.X[i] = xxa[2][i]
+		if iflag&SEFLG_SPEED != 0 {
+			ndap.X[i+3] = (xxa[1][i] - xxa[0][i]) / speedIntv / 2
+		} else {
+			ndap.X[i+3] = 0
+		}
+		ndap.Teval = tjd
+		ndap.Iephe = epheflag
+		// node
+		ndnp.X[i] = xx[2][i]
+		if iflag&SEFLG_SPEED != 0 {
+			ndnp.X[i+3] = (xx[1][i] - xx[0][i]) / speedIntv / 2
+		} else {
+			ndnp.X[i+3] = 0
+		}
+	}
+	// precession and nutation have already been taken into account because the computation is on the basis of lunar
+	// positions that have gone through swiPlanForOscElem(). light-time is already contained in lunar positions.
+	// now compute polar and equatorial coordinates:
+	for _, ndp := range []*PlanData{ndnp, ndap} {
+		clear(ndp.Xreturn[:])
+		// cartesian ecliptic
+		copy(ndp.Xreturn[6:12], ndp.X[:])
+		// polar ecliptic
+		swiCartpolSp(ndp.Xreturn[6:], ndp.Xreturn[:])
+		// cartesian equatorial
+		swiCoortrf2(ndp.Xreturn[6:], ndp.Xreturn[18:], -oe.Seps, oe.Ceps)
+		if iflag&SEFLG_SPEED != 0 {
+			swiCoortrf2(ndp.Xreturn[9:], ndp.Xreturn[21:], -oe.Seps, oe.Ceps)
+		}
+		if iflag&SEFLG_NONUT == 0 {
+			swiCoortrf2(ndp.Xreturn[18:], ndp.Xreturn[18:], -swed.Nut.Snut, swed.Nut.Cnut)
+			if iflag&SEFLG_SPEED != 0 {
+				swiCoortrf2(ndp.Xreturn[21:], ndp.Xreturn[21:], -swed.Nut.Snut, swed.Nut.Cnut)
+			}
+		}
+		// polar equatorial
+		swiCartpolSp(ndp.Xreturn[18:], ndp.Xreturn[12:])
+		ndp.Xflgs = iflag
+		ndp.Iephe = iflag & SEFLG_EPHMASK
+		if iflag&SEFLG_J2000 != 0 {
+			// node and apogee are referred to t; the ecliptic position must be transformed to J2000
+			var x [6]float64
+			copy(x[:], ndp.Xreturn[18:24])
+			// precess to J2000
+			swiPrecess(x[:], tjd, iflag, J_TO_J2000)
+			if iflag&SEFLG_SPEED != 0 {
+				swiPrecessSpeed(x[:], tjd, iflag, J_TO_J2000)
+			}
+			copy(ndp.Xreturn[18:24], x[:])
+			swiCartpolSp(ndp.Xreturn[18:], ndp.Xreturn[12:])
+			swiCoortrf2(ndp.Xreturn[18:], ndp.Xreturn[6:], swed.Oec2000.Seps, swed.Oec2000.Ceps)
+			if iflag&SEFLG_SPEED != 0 {
+				swiCoortrf2(ndp.Xreturn[21:], ndp.Xreturn[9:], swed.Oec2000.Seps, swed.Oec2000.Ceps)
+			}
+			swiCartpolSp(ndp.Xreturn[6:], ndp.Xreturn[:])
+		}
+		// radians to degrees
+		for i := 0; i < 2; i++ {
+			ndp.Xreturn[i] *= RADTODEG // ecliptic
+			ndp.Xreturn[i+3] *= RADTODEG
+			ndp.Xreturn[i+12] *= RADTODEG // equator
+			ndp.Xreturn[i+15] *= RADTODEG
+		}
+		ndp.Xreturn[0] = SweDegnorm(ndp.Xreturn[0])
+		ndp.Xreturn[12] = SweDegnorm(ndp.Xreturn[12])
+	}
+	return OK
+}
+
+// ===== 5597 ===== intp_apsides sweph.c-5597 ========================================================================
+
+// intpApsides computes the interpolated lunar apogee or perigee (ipl SEI_INTP_APOG or SEI_INTP_PERG) with speed from
+// the Moshier lunar theory, see swiIntpApsides(), and writes it to swed.Nddat[ipl].
+// Port: sidereal positions are not supported.
+func intpApsides(tjd float64, ipl int, iflag int32, serr *string) int {
+	var xpos [3][6]float64
+	var xx [6]float64
+	speedIntv := 0.1
+	oe := &swed.Oec
+	nut := &swed.Nut
+	ndp := &swed.Nddat[ipl]
+	// if same calculation was done before, return. if speed flag has been turned on, recompute
+	flg1 := iflag &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	flg2 := ndp.Xflgs &^ SEFLG_EQUATORIAL &^ SEFLG_XYZ
+	speedf1 := ndp.Xflgs & SEFLG_SPEED
+	speedf2 := iflag & SEFLG_SPEED
+	if tjd == ndp.Teval && tjd != 0 && flg1 == flg2 && (speedf2 == 0 || speedf1 != 0) {
+		ndp.Xflgs = iflag
+		ndp.Iephe = iflag & SEFLG_MOSEPH
+		return OK
+	}
+	// now three apsides
+	t := tjd - speedIntv
+	for i := 0; i < 3; i, t = i+1, t+speedIntv {
+		if iflag&SEFLG_SPEED == 0 && i != 1 {
+			continue
+		}
+		swiIntpApsides(t, xpos[i][:], ipl)
+	}
+	// apsis with speed
+	copy(xx[:3], xpos[1][:3])
+	if iflag&SEFLG_SPEED != 0 {
+		xx[3] = SweDifrad2n(xpos[2][0], xpos[0][0]) / speedIntv / 2.0
+		xx[4] = (xpos[2][1] - xpos[0][1]) / speedIntv / 2.0
+		xx[5] = (xpos[2][2] - xpos[0][2]) / speedIntv / 2.0
+	}
+	clear(ndp.Xreturn[:])
+	// ecliptic polar to cartesian
+	swiPolcartSp(xx[:], xx[:])
+	// light-time
+	if iflag&SEFLG_TRUEPOS == 0 {
+		dt := math.Sqrt(SquareSum(xx[:])) * AUNIT / CLIGHT / 86400.0
+		for i := 1; i < 3; i++ {
+			xx[i] -= dt * xx[i+3]
+		}
+	}
+	copy(ndp.Xreturn[6:12], xx[:])
+	// equatorial cartesian
+	swiCoortrf2(ndp.Xreturn[6:], ndp.Xreturn[18:], -oe.Seps, oe.Ceps)
+	if iflag&SEFLG_SPEED != 0 {
+		swiCoortrf2(ndp.Xreturn[9:], ndp.Xreturn[21:], -oe.Seps, oe.Ceps)
+	}
+	ndp.Teval = tjd
+	ndp.Xflgs = iflag
+	ndp.Iephe = iflag & SEFLG_EPHMASK
+	if iflag&SEFLG_J2000 != 0 {
+		// node and apogee are referred to t; the ecliptic position must be transformed to J2000
+		var x [6]float64
+		copy(x[:], ndp.Xreturn[18:24])
+		// precess to J2000
+		swiPrecess(x[:], tjd, iflag, J_TO_J2000)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(x[:], tjd, iflag, J_TO_J2000)
+		}
+		copy(ndp.Xreturn[18:24], x[:])
+		swiCartpolSp(ndp.Xreturn[18:], ndp.Xreturn[12:])
+		swiCoortrf2(ndp.Xreturn[18:], ndp.Xreturn[6:], swed.Oec2000.Seps, swed.Oec2000.Ceps)
+		if iflag&SEFLG_SPEED != 0 {
+			swiCoortrf2(ndp.Xreturn[21:], ndp.Xreturn[9:], swed.Oec2000.Seps, swed.Oec2000.Ceps)
+		}
+		swiCartpolSp(ndp.Xreturn[6:], ndp.Xreturn[:])
+	} else {
+		// tropical ecliptic positions. precession has already been taken into account, but not nutation
+		if iflag&SEFLG_NONUT == 0 {
+			SwiNutate(ndp.Xreturn[18:], iflag, false)
+		}
+		// equatorial polar
+		swiCartpolSp(ndp.Xreturn[18:], ndp.Xreturn[12:])
+		// ecliptic cartesian
+		swiCoortrf2(ndp.Xreturn[18:], ndp.Xreturn[6:], oe.Seps, oe.Ceps)
+		if iflag&SEFLG_SPEED != 0 {
+			swiCoortrf2(ndp.Xreturn[21:], ndp.Xreturn[9:], oe.Seps, oe.Ceps)
+		}
+		if iflag&SEFLG_NONUT == 0 {
+			swiCoortrf2(ndp.Xreturn[6:], ndp.Xreturn[6:], nut.Snut, nut.Cnut)
+			if iflag&SEFLG_SPEED != 0 {
+				swiCoortrf2(ndp.Xreturn[9:], ndp.Xreturn[9:], nut.Snut, nut.Cnut)
+			}
+		}
+		// ecliptic polar
+		swiCartpolSp(ndp.Xreturn[6:], ndp.Xreturn[:])
+	}
+	// radians to degrees
+	for i := 0; i < 2; i++ {
+		ndp.Xreturn[i] *= RADTODEG // ecliptic
+		ndp.Xreturn[i+3] *= RADTODEG
+		ndp.Xreturn[i+12] *= RADTODEG // equator
+		ndp.Xreturn[i+15] *= RADTODEG
+	}
+	ndp.Xreturn[0] = SweDegnorm(ndp.Xreturn[0])
+	ndp.Xreturn[12] = SweDegnorm(ndp.Xreturn[12])
+	return OK
+}
+
+// ===== 5757 ===== swi_plan_for_osc_elem sweph.c-5757 ===============================================================
+
+// swiPlanForOscElem transforms the position of the moon in a way we can use it for calculation of osculating node
+// and apogee: precession and nutation (attention to speed vector!) according to flags.
+// iflag	flags
+// tjd		time for which the element is computed, i.e. date of ecliptic
+// xx		equatorial cartesian position and speed
+// Port: C takes the nutation for J2000 from swed.nut2000, which is never computed. Here it is computed.
+func swiPlanForOscElem(iflag int32, tjd float64, xx []float64) int {
+	var x [6]float64
+	var oectmp Epsilon
+	var nuttmp Nut
+	oe := &swed.Oec
+	// ICRS to J2000
+	if iflag&SEFLG_ICRS == 0 && swiGetDenum(SEI_SUN, iflag) >= 403 {
+		SwiBias(xx, tjd, iflag, false)
+	}
+	// precession, equator 2000 -> equator of date. attention: speed vector has to be rotated, but daily precession
+	// 0.137" may not be added!
+	swiPrecess(xx, tjd, iflag, J2000_TO_J)
+	swiPrecess(xx[3:], tjd, iflag, J2000_TO_J)
+	// epsilon
+	if tjd == swed.Oec.Teps {
+		oe = &swed.Oec
+	} else if tjd == J2000 {
+		oe = &swed.Oec2000
+	} else {
+		calcEpsilon(tjd, iflag, &oectmp)
+		oe = &oectmp
+	}
+	// nutation. again: speed vector must be rotated, but not added 'speed' of nutation
+	nutp := &nuttmp
+	if iflag&SEFLG_NONUT == 0 {
+		if tjd == swed.Nut.Tnut {
+			nutp = &swed.Nut
+		} else if tjd == swed.Nutv.Tnut {
+			nutp = &swed.Nutv
+		} else {
+			nutp.Nutlo = make([]float64, 2)
+			swiNutation(tjd, iflag, nutp.Nutlo)
+			nutp.Tnut = tjd
+			nutp.Snut = math.Sin(nutp.Nutlo[1])
+			nutp.Cnut = math.Cos(nutp.Nutlo[1])
+			nutMatrix(nutp, oe)
+		}
+		for i := 0; i <= 2; i++ {
+			x[i] = xx[0]*nutp.Matrix[0][i] + xx[1]*nutp.Matrix[1][i] + xx[2]*nutp.Matrix[2][i]
+		}
+		// speed: rotation only
+		for i := 0; i <= 2; i++ {
+			x[i+3] = xx[3]*nutp.Matrix[0][i] + xx[4]*nutp.Matrix[1][i] + xx[5]*nutp.Matrix[2][i]
+		}
+		copy(xx[:6], x[:])
+	}
+	// transformation to ecliptic
+	swiCoortrf2(xx, xx, oe.Seps, oe.Ceps)
+	swiCoortrf2(xx[3:], xx[3:], oe.Seps, oe.Ceps)
+	if iflag&SEFLG_NONUT == 0 {
+		swiCoortrf2(xx, xx, nutp.Snut, nutp.Cnut)
+		swiCoortrf2(xx[3:], xx[3:], nutp.Snut, nutp.Cnut)
+	}
+	return OK
+}
+
 // ===== 5857 ===== constants for meff ==  sweph.c-5857 ==============================================================
 
 type MeffEle struct {
@@ -979,6 +4296,42 @@ func Meff(r float64) float64 {
 	return m
 }
 
+// ===== 5982 ===== denormalize_positions sweph.c-5982 ===============================================================
+
+// denormalizePositions adjusts the longitudes and right ascensions of the positions x0 and x2 (24 values each) by
+// 360 degrees, so that there is no jump between them and x1.
+func denormalizePositions(x0, x1, x2 []float64) {
+	// x*[0] = ecliptic longitude, x*[12] = rectascension
+	for i := 0; i <= 12; i += 12 {
+		if x1[i]-x0[i] < -180 {
+			x0[i] -= 360
+		}
+		if x1[i]-x0[i] > 180 {
+			x0[i] += 360
+		}
+		if x1[i]-x2[i] < -180 {
+			x2[i] -= 360
+		}
+		if x1[i]-x2[i] > 180 {
+			x2[i] += 360
+		}
+	}
+}
+
+// ===== 5998 ===== calc_speed sweph.c-5998 ==========================================================================
+
+// calcSpeed computes the speeds in x1 from the positions x0, x1 and x2 at the times t - dt, t and t + dt.
+func calcSpeed(x0, x1, x2 []float64, dt float64) {
+	for j := 0; j <= 18; j += 6 {
+		for i := 0; i < 3; i++ {
+			k := j + i
+			b := (x2[k] - x0[k]) / 2
+			a := (x2[k]+x0[k])/2 - x1[k]
+			x1[k+3] = (2*a + b) / dt
+		}
+	}
+}
+
 // ===== 6012 ===== swi_check_ecliptic sweph.c-6012 ==================================================================
 
 func swiCheckEcliptic(tjd float64, iflag int32) {
@@ -999,16 +4352,21 @@ func swiCheckEcliptic(tjd float64, iflag int32) {
 
 // ===== 6029 ===== swi_check_nutation sweph.c-6029 ==================================================================
 
+// nutflag holds the flags of the last computation of nutation (static in C)
+var nutflag int32
+
 // swiCheckNutation computes nutation if it is wanted and has not yet been computed.
 // If speed flag has been turned on since last computation, nutation is recomputed.
 func swiCheckNutation(tjd float64, iflag int32) {
-	var (
-		speedf1 int32
-		speedf2 int32
-		nutflag int32 // This would need proper initialization in the actual program
-	)
-	speedf1 = nutflag & SEFLG_SPEED
-	speedf2 = iflag & SEFLG_SPEED
+	// Port: the arrays for the nutation are allocated here, SE_ECL_NUT also reads them with SEFLG_NONUT
+	if swed.Nut.Nutlo == nil {
+		swed.Nut.Nutlo = make([]float64, 2)
+	}
+	if swed.Nutv.Nutlo == nil {
+		swed.Nutv.Nutlo = make([]float64, 2)
+	}
+	speedf1 := nutflag & SEFLG_SPEED
+	speedf2 := iflag & SEFLG_SPEED
 	if (iflag&SEFLG_NONUT) == 0 &&
 		(tjd != swed.Nut.Tnut || tjd == 0 ||
 			(speedf1 == 0 && speedf2 != 0)) {
@@ -1039,8 +4397,6 @@ func swiCheckNutation(tjd float64, iflag int32) {
 func plausIflag(iflag int32, ipl int32, tjd float64, serr *string) int32 {
 	var epheflag int32 = 0
 
-	// Port: removed parts that handle JPL and Moshier
-
 	// if topocentric bit, turn helio- and barycentric bits off
 	if (iflag & SEFLG_TOPOCTR) != 0 {
 		iflag = iflag &^ (SEFLG_HELCTR | SEFLG_BARYCTR)
@@ -1069,10 +4425,626 @@ func plausIflag(iflag int32, ipl int32, tjd float64, serr *string) int32 {
 		iflag |= (SEFLG_NOGDEFL | SEFLG_NOABERR)
 	}
 
+	// either Moshier or JPL or Swiss Eph
+	if (iflag & SEFLG_MOSEPH) != 0 {
+		epheflag = SEFLG_MOSEPH
+	}
+	if (iflag & SEFLG_SWIEPH) != 0 {
+		epheflag = SEFLG_SWIEPH
+	}
+	if (iflag & SEFLG_JPLEPH) != 0 {
+		epheflag = SEFLG_JPLEPH
+	}
+	// Port: SPK kernels, see SweLoadSpkFile
+	if (iflag & SEFLG_SPKEPH) != 0 {
+		epheflag = SEFLG_SPKEPH
+	}
 	if epheflag == 0 {
 		epheflag = SEFLG_DEFAULTEPH
 	}
 
 	iflag = (iflag &^ SEFLG_EPHMASK) | epheflag
+	// SEFLG_JPLHOR only with JPL and Swiss Eph
+	if (epheflag & (SEFLG_SWIEPH | SEFLG_JPLEPH)) == 0 {
+		iflag = iflag &^ (SEFLG_JPLHOR | SEFLG_JPLHOR_APPROX)
+	}
+	// planets that have no JPL Horizons mode
+	if ipl == SE_OSCU_APOG || ipl == SE_TRUE_NODE || ipl == SE_MEAN_APOG || ipl == SE_MEAN_NODE ||
+		ipl == SE_INTP_APOG || ipl == SE_INTP_PERG {
+		iflag = iflag &^ (SEFLG_JPLHOR | SEFLG_JPLHOR_APPROX)
+	}
+	if ipl >= SE_FICT_OFFSET && ipl <= SE_FICT_MAX || ipl >= SE_COMET_OFFSET && ipl < SE_PLMOON_OFFSET {
+		iflag = iflag &^ (SEFLG_JPLHOR | SEFLG_JPLHOR_APPROX)
+	}
+	// SEFLG_JPLHOR requires the files with dpsi and deps
+	iflag, err := swiCheckJplhor(iflag, tjd)
+	if err != nil && serr != nil {
+		*serr = err.Error()
+	}
+	jplhoraModel := swed.AstroModels[SE_MODEL_JPLHORA_MODE]
+	if jplhoraModel == 0 {
+		jplhoraModel = SEMOD_JPLHORA_DEFAULT
+	}
+	if (iflag&SEFLG_JPLHOR_APPROX) != 0 && jplhoraModel == SEMOD_JPLHORA_2 {
+		iflag |= SEFLG_ICRS
+	}
 	return iflag
 }
+
+// ===== 6153 ===== fixstar_format_search_name sweph.c-6153 ==========================================================
+
+// fixstarFormatSearchName formats the search name of a star: white space is removed and the traditional name is
+// converted to lower case (the Bayer designation after the comma remains as it is).
+func fixstarFormatSearchName(star string, serr *string) (string, int32) {
+	sstar := star
+	if len(sstar) > SWI_STAR_LENGTH {
+		sstar = sstar[:SWI_STAR_LENGTH]
+	}
+	// remove whitespaces from search name
+	sstar = strings.ReplaceAll(sstar, " ", "")
+	// traditional name of star to lower case; keep uppercase with Bayer/Flamsteed designations after comma
+	if i := strings.IndexByte(sstar, ','); i >= 0 {
+		sstar = strings.ToLower(sstar[:i]) + sstar[i:]
+	} else {
+		sstar = strings.ToLower(sstar)
+	}
+	if sstar == "" {
+		*serr = "swe_fixstar(): star name empty"
+		return "", ERR
+	}
+	return sstar, OK
+}
+
+// ===== 6210 ===== fixstar_cut_string sweph.c-6210 ==================================================================
+
+// fixstarCutString cuts a comma-separated fixed star data record from sefstars.txt and fills it into stardata. The
+// position is converted to radians, the proper motion to radians per century, the parallax to radians and the radial
+// velocity to AU per century.
+// Port: the name of the star is not returned, it is the name of stardata.
+func fixstarCutString(srecord string, stardata *FixedStar, serr *string) int32 {
+	cpos := make([]string, 20)
+	i := swiCutstr(srecord, ",", cpos, 20)
+	// return trad. name, nomeclature name
+	cpos[0] = rightTrim(cpos[0])
+	cpos[1] = rightTrim(cpos[1])
+	if i < 14 {
+		if i >= 2 {
+			*serr = fmt.Sprintf("data of star '%s,%s' incomplete", cpos[0], cpos[1])
+		} else {
+			s := cpos[0]
+			if len(s) > 200 {
+				s = s[:200]
+			}
+			*serr = fmt.Sprintf("invalid line in fixed stars file: '%s'", s)
+		}
+		return ERR
+	}
+	if len(cpos[0]) > SWI_STAR_LENGTH {
+		cpos[0] = cpos[0][:SWI_STAR_LENGTH]
+	}
+	if len(cpos[1]) > SWI_STAR_LENGTH-1 {
+		cpos[1] = cpos[1][:SWI_STAR_LENGTH-1]
+	}
+	stardata.StarName = cpos[0]
+	stardata.StarBayer = cpos[1]
+	// star data
+	epoch := atof(cpos[2])
+	raH := atof(cpos[3])
+	raM := atof(cpos[4])
+	raS := atof(cpos[5])
+	deD := atof(cpos[6])
+	deM := atof(cpos[7])
+	deS := atof(cpos[8])
+	raPm := atof(cpos[9])
+	dePm := atof(cpos[10])
+	radv := atof(cpos[11])
+	parall := atof(cpos[12])
+	mag := atof(cpos[13])
+	// position and speed (equinox)
+	// ra and de in degrees
+	ra := (raS/3600.0 + raM/60.0 + raH) * 15.0
+	var de float64
+	if !strings.Contains(cpos[6], "-") {
+		de = deS/3600.0 + deM/60.0 + deD
+	} else {
+		de = -deS/3600.0 - deM/60.0 + deD
+	}
+	// speed in ra and de, degrees per century
+	if swed.IsOldStarfile {
+		raPm = raPm * 15 / 3600.0
+		dePm = dePm / 3600.0
+	} else {
+		raPm = raPm / 10.0 / 3600.0
+		dePm = dePm / 10.0 / 3600.0
+		parall /= 1000.0
+	}
+	// parallax, degrees
+	if parall > 1 {
+		parall = 1 / parall / 3600.0
+	} else {
+		parall /= 3600
+	}
+	// radial velocity in AU per century
+	radv *= KM_S_TO_AU_CTY
+	// radians
+	ra *= DEGTORAD
+	de *= DEGTORAD
+	raPm *= DEGTORAD
+	dePm *= DEGTORAD
+	raPm /= math.Cos(de) // catalogues give proper motion in RA as great circle
+	parall *= DEGTORAD
+	stardata.Epoch = epoch
+	stardata.Ra = ra
+	stardata.De = de
+	stardata.RaMot = raPm
+	stardata.DeMot = dePm
+	stardata.Parall = parall
+	stardata.RadVel = radv
+	stardata.Mag = mag
+	return OK
+}
+
+// ===== 6322 ===== load_all_fixed_stars sweph.c-6322 ================================================================
+
+// loadAllFixedStars loads all fixed stars from the file sefstars.txt (or, if it is not found, from the old file
+// fixstars.cat) into swed.FixedStars. Every star has a record with its Bayer/Flamsteed designation, prefixed with a
+// comma, as its search key. If a star has a traditional name, there is also a record with this name, without white
+// space and in lower case, as its search key. The records are sorted by search key, so that the Bayer designations
+// come first.
+// Returns OK, ERR, or -2 without doing anything if the stars were loaded at an earlier time.
+// Port: the ephemeris path is set as in SweCalc, if it has not been set yet.
+func loadAllFixedStars(serr *string) int32 {
+	nstars, nrecs, nnamed := 0, 0, 0
+	lastStarbayer := ""
+	if swed.NFixstarsRecords > 0 {
+		return -2
+	}
+	if swed.FixFp == nil {
+		if !swed.EphePathIsSet {
+			SweSetEphePath("")
+		}
+		fp, err := SwiFopen(SEI_FILE_FIXSTAR, SE_STARFILE, swed.EphePath)
+		if err != nil {
+			swed.IsOldStarfile = true
+			if fp, _ = SwiFopen(SEI_FILE_FIXSTAR, SE_STARFILE_OLD, swed.EphePath); fp == nil {
+				swed.IsOldStarfile = false
+				// no fixed star file available
+				*serr = err.Error()
+				return ERR
+			}
+		}
+		swed.FixFp = fp
+	}
+	if _, err := swed.FixFp.Seek(0, io.SeekStart); err != nil {
+		*serr = err.Error()
+		return ERR
+	}
+	var stars []FixedStar
+	scanner := bufio.NewScanner(swed.FixFp)
+	for scanner.Scan() {
+		s := scanner.Text()
+		// skip comment lines
+		if s == "" || s[0] == '#' || s[0] == '\r' {
+			continue
+		}
+		var fstdata FixedStar
+		if fixstarCutString(s, &fstdata, serr) == ERR {
+			return ERR
+		}
+		// if star has a traditional name, save it with that name as its search key
+		if fstdata.StarName != "" {
+			nrecs++
+			nnamed++
+			// remove white spaces from star name, star name to lowercase
+			fstdata.Skey = strings.ToLower(strings.ReplaceAll(fstdata.StarName, " ", ""))
+			stars = append(stars, fstdata)
+		}
+		// also save it with Bayer designation as search key; only if it has not been saved already
+		if fstdata.StarBayer == lastStarbayer {
+			continue
+		}
+		nstars++
+		nrecs++
+		// , sorts before alnum; remove white spaces from star bayer name
+		fstdata.Skey = strings.ReplaceAll(","+fstdata.StarBayer, " ", "")
+		lastStarbayer = fstdata.StarBayer
+		stars = append(stars, fstdata)
+	}
+	if err := scanner.Err(); err != nil {
+		*serr = err.Error()
+		return ERR
+	}
+	sort.SliceStable(stars, func(i, j int) bool {
+		return stars[i].Skey < stars[j].Skey
+	})
+	swed.FixedStars = stars
+	swed.NFixstarsReal = nstars
+	swed.NFixstarsNamed = nnamed
+	swed.NFixstarsRecords = nrecs
+	return OK
+}
+
+// ===== 6405 ===== fixstar_calc_from_struct sweph.c-6405 ============================================================
+
+// fixstarCalcFromStruct calculates the position of a fixed star from its catalogue data
+// stardata	fixed star data
+// tjd		julian daynumber
+// iflag		SEFLG_ specifications
+// xx		position and speed
+// Port: sidereal and topocentric positions return an error, the reminder to call swe_set_ephe_path() first is
+// skipped.
+func fixstarCalcFromStruct(stardata *FixedStar, tjd float64, iflag int32, xx []float64, serr *string) int32 {
+	var x, xobs, xobsDt, xearth, xearthDt, xsun, xsunDt [6]float64
+	var xpo, xpoDt []float64
+	dt := PLAN_SPEED_INTV * 0.1
+	iflgsave := iflag
+	iflag |= SEFLG_SPEED // we need this in order to work correctly
+	*serr = ""
+	iflag = plausIflag(iflag, -1, tjd, serr)
+	epheflag := iflag & SEFLG_EPHMASK
+	swiInitSwedIfStart()
+	if swed.LastEpheFlag != epheflag {
+		freePlanets()
+		// close and free ephemeris files
+		if swed.JplFileIsOpen {
+			swiCloseJplFile()
+			swed.JplFileIsOpen = false
+		}
+		for i := 0; i < SEI_NEPHFILES; i++ {
+			if swed.Fidat[i].Fptr != nil {
+				swed.Fidat[i].Fptr.Close()
+			}
+			swed.Fidat[i] = FileData{}
+		}
+		swed.LastEpheFlag = epheflag
+	}
+	// Port: sidereal and topocentric positions are not yet supported
+	if iflag&(SEFLG_SIDEREAL|SEFLG_TOPOCTR) != 0 {
+		*serr = "sidereal and topocentric positions are not supported."
+		return ERR
+	}
+	// obliquity of ecliptic 2000 and of date
+	swiCheckEcliptic(tjd, iflag)
+	// nutation
+	swiCheckNutation(tjd, iflag)
+	epoch := stardata.Epoch
+	t := tjd - J2000 // days since 2000.0
+	if epoch == 1950 {
+		t = tjd - B1950 // days since 1950.0
+	}
+	x[0] = stardata.Ra
+	x[1] = stardata.De
+	if stardata.Parall == 0 {
+		x[2] = 1000000000
+	} else {
+		x[2] = 1.0 / (stardata.Parall * RADTODEG * 3600) * PARSEC_TO_AUNIT
+	}
+	x[3] = stardata.RaMot / 36525.0
+	x[4] = stardata.DeMot / 36525.0
+	x[5] = stardata.RadVel / 36525.0
+	// Cartesian space motion vector
+	swiPolcartSp(x[:], x[:])
+	// FK5
+	if epoch == 1950 {
+		swiFK4FK5(x[:], B1950)
+		swiPrecess(x[:], B1950, 0, J_TO_J2000)
+		swiPrecess(x[3:], B1950, 0, J_TO_J2000)
+	}
+	// FK5 to ICRF, if jpl ephemeris is referred to ICRF. With data that are already ICRF, epoch = 0
+	if epoch != 0 {
+		swiIcrs2fk5(&x, iflag, true) // backward, i. e. to icrf
+		// with ephemerides < DE403, we now convert to J2000
+		if swiGetDenum(SEI_SUN, iflag) >= 403 {
+			SwiBias(x[:], J2000, SEFLG_SPEED, false)
+		}
+	}
+	// earth/sun for parallax, light deflection, and aberration
+	if iflag&SEFLG_BARYCTR == 0 && (iflag&SEFLG_HELCTR == 0 || iflag&SEFLG_MOSEPH == 0) {
+		if mainPlanetBary(tjd-dt, SEI_EARTH, epheflag, iflag, NO_SAVE, xearthDt[:], xearthDt[:], xsunDt[:], nil,
+			serr) != OK {
+			return ERR
+		}
+		if mainPlanetBary(tjd, SEI_EARTH, epheflag, iflag, DO_SAVE, xearth[:], xearth[:], xsun[:], nil,
+			serr) != OK {
+			return ERR
+		}
+		// observer: barycentric position of geocenter
+		xobs = xearth
+		xobsDt = xearthDt
+	}
+	// position and speed at tjd, for parallax
+	switch {
+	case iflag&SEFLG_HELCTR != 0 && iflag&SEFLG_MOSEPH != 0:
+		// no parallax, if moshier and heliocentric
+	case iflag&SEFLG_HELCTR != 0:
+		xpo = xsun[:]
+		xpoDt = xsunDt[:]
+	case iflag&SEFLG_BARYCTR != 0:
+		// no parallax, if barycentric
+	default:
+		xpo = xobs[:]
+		xpoDt = xobsDt[:]
+	}
+	for i := 0; i <= 2; i++ {
+		x[i] += t * x[i+3]
+		if xpo != nil {
+			x[i] -= xpo[i]
+			x[i+3] -= xpo[i+3]
+		}
+	}
+	// relativistic deflection of light
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOGDEFL == 0 {
+		swiDeflectLight(x[:], 0, iflag&SEFLG_SPEED)
+	}
+	// 'annual' aberration of light. speed is incorrect !!!
+	if iflag&SEFLG_TRUEPOS == 0 && iflag&SEFLG_NOABERR == 0 {
+		swiAberrLightEx(x[:], xpo, xpoDt, dt, iflag&SEFLG_SPEED)
+	}
+	// ICRS to J2000
+	if iflag&SEFLG_ICRS == 0 && (swiGetDenum(SEI_SUN, iflag) >= 403 || iflag&SEFLG_BARYCTR != 0) {
+		SwiBias(x[:], tjd, iflag, false)
+	}
+	// precession, equator 2000 -> equator of date
+	oe := &swed.Oec2000
+	if iflag&SEFLG_J2000 == 0 {
+		swiPrecess(x[:], tjd, iflag, J2000_TO_J)
+		if iflag&SEFLG_SPEED != 0 {
+			swiPrecessSpeed(x[:], tjd, iflag, J2000_TO_J)
+		}
+		oe = &swed.Oec
+	}
+	// nutation
+	if iflag&SEFLG_NONUT == 0 {
+		SwiNutate(x[:], iflag, false)
+	}
+	// transformation to ecliptic
+	if iflag&SEFLG_EQUATORIAL == 0 {
+		swiCoortrf2(x[:], x[:], oe.Seps, oe.Ceps)
+		if iflag&SEFLG_SPEED != 0 {
+			swiCoortrf2(x[3:], x[3:], oe.Seps, oe.Ceps)
+		}
+		if iflag&SEFLG_NONUT == 0 {
+			swiCoortrf2(x[:], x[:], swed.Nut.Snut, swed.Nut.Cnut)
+			if iflag&SEFLG_SPEED != 0 {
+				swiCoortrf2(x[3:], x[3:], swed.Nut.Snut, swed.Nut.Cnut)
+			}
+		}
+	}
+	// transformation to polar coordinates
+	if iflag&SEFLG_XYZ == 0 {
+		swiCartpolSp(x[:], x[:])
+	}
+	// radians to degrees
+	if iflag&SEFLG_RADIANS == 0 && iflag&SEFLG_XYZ == 0 {
+		for i := 0; i < 2; i++ {
+			x[i] *= RADTODEG
+			x[i+3] *= RADTODEG
+		}
+	}
+	copy(xx[:6], x[:])
+	if iflgsave&SEFLG_SPEED == 0 {
+		clear(xx[3:6])
+	}
+	// if no ephemeris has been specified, do not return chosen ephemeris
+	if iflgsave&SEFLG_EPHMASK == 0 {
+		iflag = iflag &^ SEFLG_DEFAULTEPH
+	}
+	return iflag &^ SEFLG_SPEED
+}
+
+// ===== 6672 ===== search_star_in_list sweph.c-6672 =================================================================
+
+// searchStarInList searches the star sstar, formatted with fixstarFormatSearchName, in the fixed stars list: by
+// sequential number, by traditional name with a wildcard '%' at the end, or by traditional name or Bayer/Flamsteed
+// designation with binary search.
+func searchStarInList(sstar string, stardata *FixedStar, serr *string) int32 {
+	isBayer := false
+	starNr := 0
+	if sstar[0] == ',' {
+		isBayer = true
+	} else if sstar[0] >= '0' && sstar[0] <= '9' {
+		starNr = atoi(sstar)
+	} else if i := strings.IndexByte(sstar, ','); i >= 0 {
+		sstar = sstar[i:]
+		isBayer = true
+	}
+	named := swed.FixedStars[swed.NFixstarsReal : swed.NFixstarsReal+swed.NFixstarsNamed]
+	if starNr > 0 {
+		if starNr > swed.NFixstarsReal {
+			*serr = fmt.Sprintf("error, swe_fixstar(): sequential fixed star number %d is not available", starNr)
+			return ERR
+		}
+		*stardata = swed.FixedStars[starNr-1] // keys start from 1
+		return OK
+	}
+	// traditional name with wildcard '%' at end of string
+	if sp := strings.IndexByte(sstar, '%'); !isBayer && sp >= 0 {
+		if sp != len(sstar)-1 {
+			*serr = fmt.Sprintf("error, swe_fixstar(): invalid search string %s", sstar)
+			return ERR
+		}
+		for i := range named {
+			if strings.HasPrefix(named[i].Skey, sstar[:sp]) {
+				*stardata = named[i]
+				return OK
+			}
+		}
+		*serr = fmt.Sprintf("error, swe_fixstar(): star search string %s did not match", sstar)
+		return ERR
+	}
+	// traditional name or Bayer/Flamsteed: find it with binary search
+	list := named
+	if isBayer {
+		list = swed.FixedStars[:swed.NFixstarsReal]
+	}
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].Skey >= sstar
+	})
+	if i == len(list) || list[i].Skey != sstar {
+		*serr = fmt.Sprintf("error, swe_fixstar(): could not find star name %s", sstar)
+		return ERR
+	}
+	*stardata = list[i]
+	return OK
+}
+
+// ===== 6748 ===== get_builtin_star sweph.c-6748 ====================================================================
+
+// getBuiltinStar returns the record of the stars that are built-in, because they are required for Hindu sidereal
+// ephemerides and the galactic ayanamsas.
+// Port: the search name of the star is not returned, there is no cache of the last star.
+func getBuiltinStar(star string) (string, bool) {
+	switch {
+	// Ayanamsha SE_SIDM_TRUE_CITRA
+	case strings.HasPrefix(star, "spica") || strings.HasPrefix(star, "Spica"):
+		return "Spica,alVir,ICRS,13,25,11.57937,-11,09,40.7501,-42.35,-30.67,1,13.06,0.97,-10,3672", true
+	// Ayanamsha SE_SIDM_TRUE_REVATI
+	case strings.Contains(star, ",zePsc") || strings.HasPrefix(star, "revati") || strings.HasPrefix(star, "Revati"):
+		return "Revati,zePsc,ICRS,01,13,43.88735,+07,34,31.2745,145,-55.69,15,18.76,5.187,06,174", true
+	// Ayanamsha SE_SIDM_TRUE_PUSHYA, SE_SIDM_TRUE_SHEORAN
+	case strings.Contains(star, ",deCnc") || strings.HasPrefix(star, "pushya") || strings.HasPrefix(star, "Pushya"):
+		return "Pushya,deCnc,ICRS,08,44,41.09921,+18,09,15.5034,-17.67,-229.26,17.14,24.98,3.94,18,2027", true
+	// Ayanamsha SE_SIDM_TRUE_MULA
+	case strings.Contains(star, ",laSco") || star == "mula" || star == "Mula":
+		return "Mula,laSco,ICRS,17,33,36.52012,-37,06,13.7648,-8.53,-30.8,-3,5.71,1.62,-37,11673", true
+	// Ayanamsha SE_SIDM_GALCENT_0SAG, SE_SIDM_GALCENT_COCHRANE, SE_SIDM_GALCENT_RGILBRAND
+	case strings.Contains(star, ",SgrA*"):
+		return "Gal. Center,SgrA*,2000,17,45,40.03599,-29,00,28.1699,-2.755718425,-5.547,0.0,0.125,999.99,0,0", true
+	// Ayanamsha SE_SIDM_GALEQU_IAU1958
+	case strings.Contains(star, ",GP1958"):
+		return "Gal. Pole IAU1958,GP1958,1950,12,49,0.0,27,24,0.0,0.0,0.0,0.0,0.0,0.0,0,0", true
+	// Ayanamsha SE_SIDM_GALEQU_TRUE, SE_SIDM_GALEQU_MULA
+	case strings.Contains(star, ",GPol"):
+		return "Gal. Pole,GPol,ICRS,12,51,36.7151981,27,06,11.193172,0.0,0.0,0.0,0.0,0.0,0,0", true
+	}
+	return "", false
+}
+
+// ===== 6816 ===== swe_fixstar2 sweph.c-6816 ========================================================================
+
+// SweFixstar2 computes the position of a fixed star for the Julian Day tjd (TT). The stars are loaded from the file
+// sefstars.txt (or fixstars.cat) in the ephemeris path at the first call.
+// star is the traditional name (e.g. "Aldebaran"), the Bayer/Flamsteed designation after a comma (e.g. ",alTau"), a
+// traditional name with the wildcard '%' at the end (e.g. "alde%") or the sequential number of the star in the
+// list of Bayer designations, starting from 1. Case and white space of the traditional name are ignored. iflag
+// contains the flags SEFLG_*, as for SweCalc.
+// Returns longitude, latitude and distance (or the equatorial or cartesian variants), the flags, the name of the star
+// in the format "traditional name,Bayer designation" and an error. In case of an error the flags are ERR, otherwise
+// the error is a warning and the position is valid.
+// Port: tracing and the cache of the star of the last call are skipped, sidereal and topocentric positions return an
+// error. If the star is not found and the file could not be loaded, the error of loading is returned.
+func SweFixstar2(star string, tjd float64, iflag int32) ([6]float64, int32, string, error) {
+	var xx [6]float64
+	var serr, serrLoad string
+	var stardata FixedStar
+	returnError := func() ([6]float64, int32, string, error) {
+		return [6]float64{}, ERR, "", errors.New(serr)
+	}
+	swiInitSwedIfStart()
+	// loads stars unless loaded with an earlier call of function
+	loadAllFixedStars(&serrLoad)
+	sstar, retc := fixstarFormatSearchName(star, &serr)
+	if retc == ERR {
+		return returnError()
+	}
+	if srecord, ok := getBuiltinStar(star); ok {
+		if fixstarCutString(srecord, &stardata, &serr) == ERR {
+			return returnError()
+		}
+	} else if searchStarInList(sstar, &stardata, &serr) == ERR {
+		if serrLoad != "" {
+			serr = serrLoad
+		}
+		return returnError()
+	}
+	if fixstarCalcFromStruct(&stardata, tjd, iflag, xx[:], &serr) == ERR {
+		return returnError()
+	}
+	name := stardata.StarName + "," + stardata.StarBayer
+	if serr != "" {
+		return xx, iflag, name, errors.New(serr)
+	}
+	return xx, iflag, name, nil
+}
+
+// ===== 6876 ===== swe_fixstar2_ut sweph.c-6876 =====================================================================
+
+// SweFixstar2Ut is SweFixstar2 for the Julian Day tjdUt in Universal Time.
+func SweFixstar2Ut(star string, tjdUt float64, iflag int32) ([6]float64, int32, string, error) {
+	iflag = plausIflag(iflag, -1, tjdUt, nil)
+	epheflag := iflag & SEFLG_EPHMASK
+	if epheflag == 0 {
+		epheflag = SEFLG_SWIEPH
+		iflag |= SEFLG_SWIEPH
+	}
+	deltat, _ := sweDeltatEx(tjdUt, iflag)
+	xx, retflag, name, err := SweFixstar2(star, tjdUt+deltat, iflag)
+	// if ephe required is not ephe returned, adjust delta t
+	if retflag != ERR && retflag&SEFLG_EPHMASK != epheflag {
+		deltat, _ = sweDeltatEx(tjdUt, retflag)
+		xx, retflag, name, err = SweFixstar2(star, tjdUt+deltat, iflag)
+	}
+	return xx, retflag, name, err
+}
+
+// ===== 6909 ===== swe_fixstar2_mag sweph.c-6909 ====================================================================
+
+// SweFixstar2Mag returns the magnitude of a fixed star and its name in the format "traditional name,Bayer
+// designation". The star is searched as in SweFixstar2, but without the built-in stars.
+// Port: the cache of the star of the last call is skipped.
+func SweFixstar2Mag(star string) (float64, string, error) {
+	var serr, serrLoad string
+	var stardata FixedStar
+	swiInitSwedIfStart()
+	// loads stars unless loaded with an earlier call of function
+	loadAllFixedStars(&serrLoad)
+	sstar, retc := fixstarFormatSearchName(star, &serr)
+	if retc == ERR {
+		return 0, "", errors.New(serr)
+	}
+	if searchStarInList(sstar, &stardata, &serr) == ERR {
+		if serrLoad != "" {
+			serr = serrLoad
+		}
+		return 0, "", errors.New(serr)
+	}
+	return stardata.Mag, stardata.StarName + "," + stardata.StarBayer, nil
+}
+
+// ===== 7267 ===== swi_force_app_pos_etc sweph.c-7267 ===============================================================
+
+// swiForceAppPosEtc forces a new calculation of light-time etc. and clears the saved positions.
+func swiForceAppPosEtc() {
+	for i := 0; i < SEI_NPLANETS; i++ {
+		swed.Pldat[i].Xflgs = -1
+	}
+	for i := 0; i < SEI_NNODE_ETC; i++ {
+		swed.Nddat[i].Xflgs = -1
+	}
+	for i := 0; i <= SE_NPLANETS; i++ { // "<=" because save area for asteroids > SE_AST_OFFSET is at i == SE_NPLANETS
+		swed.Savedat[i].Tsave = 0
+		swed.Savedat[i].Iflgsave = -1
+	}
+}
+
+// ===== 7436 ===== open_jpl_file sweph.c-7436 =======================================================================
+
+// openJplFile opens the JPL file fname in the directories of fpath. If the default file (DE431) fails, the second
+// default (DE406) is tried, but only if serr is not nil and a warning can be returned.
+func openJplFile(ss []float64, fname, fpath string, serr *string) int {
+	retc := swiOpenJplFile(ss, fname, fpath, serr)
+	if retc != OK && strings.Contains(fname, SE_FNAME_DFT) && serr != nil {
+		var serr2 string
+		if retc = swiOpenJplFile(ss, SE_FNAME_DFT2, fpath, &serr2); retc == OK {
+			swed.JplFnam = SE_FNAME_DFT2
+			*serr = fmt.Sprintf("Error with JPL ephemeris file %s: %s. Defaulting to %s", SE_FNAME_DFT, *serr,
+				SE_FNAME_DFT2)
+		}
+	}
+	if retc == OK {
+		swed.JplDenum = swiGetJplDenum()
+		swed.JplFileIsOpen = true
+		swiSetTidAcc(0, 0, swed.JplDenum)
+	}
+	return retc
+}
@@ -49,6 +49,11 @@ const (
 	SE_FICT_OFFSET_1 = 39
 	SE_FICT_MAX      = 999
 	SE_NFICT_ELEM    = 15
+	SE_FICT_REG      = 500 // Port: added, first number of the bodies of SweRegisterElements
+
+	// Port: added, built-in elements of the Uranian planets Cupido .. Poseidon, see SweCalcFictEl
+	SE_FICTEL_NEELY   = 1 // revised elements of James Neely, as in C where SE_NEELY is defined
+	SE_FICTEL_CLASSIC = 2
 
 	SE_COMET_OFFSET = 1000
 
@@ -115,7 +120,9 @@ const (
 	SEFLG_JPLHOR_APPROX = 524288
 	SEFLG_CENTER_BODY   = 1048576
 	SEFLG_TEST_PLMOON   = 2097152 | SEFLG_J2000 | SEFLG_ICRS | SEFLG_HELCTR | SEFLG_TRUEPOS
-	SEFLG_EPHMASK       = SEFLG_JPLEPH | SEFLG_SWIEPH | SEFLG_MOSEPH
+	SEFLG_SPKEPH        = 4194304 // Port: added, SPK kernels, see SweLoadSpkFile
+	SEFLG_EPHMASK       = SEFLG_JPLEPH | SEFLG_SWIEPH | SEFLG_MOSEPH | SEFLG_SPKEPH
+	SEFLG_COORDSYS      = SEFLG_EQUATORIAL | SEFLG_XYZ | SEFLG_RADIANS
 
 	// Sidereal bits
 	SE_SIDBITS               = 256
@@ -582,8 +582,6 @@ type NodeData struct {
 //	T0IsUT  bool    // True if T0 is UT
 //}
 //
-//const SWI_STAR_LENGTH = 40
-//
 //// FixedStar represents fixed star data
 //type FixedStar struct {
 //	Skey      [SWI_STAR_LENGTH + 2]byte // May be prefixed with comma, one char more
@@ -600,6 +598,9 @@ type NodeData struct {
 //	Mag       float64
 //}
 
+// SWI_STAR_LENGTH is the maximum length of the name of a fixed star
+const SWI_STAR_LENGTH = 40
+
 // SWE_DATA_DPSI_DEPS represents the number of days for dpsi and deps data (100 years after 1962)
 const SWE_DATA_DPSI_DEPS = 36525
 
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+// maximum error of interpolated nutation in milli-arcseconds, as documented with SweSetInterpolateNut
+const maxNutIntpErrorMas = 3.0
+
+// setNutModel sets the nutation model in swed.AstroModels, which swiNutation uses, for the rest of a test. The
+// previous model is restored when the test ends.
+func setNutModel(tb testing.TB, model int32) {
+	swiInitSwedIfStart()
+	saved := swed.AstroModels[SE_MODEL_NUT]
+	swed.AstroModels[SE_MODEL_NUT] = model
+	tb.Cleanup(func() { swed.AstroModels[SE_MODEL_NUT] = saved })
+}
+
+func TestNutationInterpolationAccuracy(t *testing.T) {
+	defer SweSetInterpolateNut(false)
+	SweSetInterpolateNut(true)
+	for _, model := range []int32{SEMOD_NUT_IAU_2000A, SEMOD_NUT_IAU_2000B, SEMOD_NUT_IAU_1980} {
+		setNutModel(t, model)
+		maxErr := 0.0
+		nutExact := make([]float64, 2)
+		nutIntp := make([]float64, 2)
+		// 60 days in steps of 17 minutes
+		for tjd := 2460000.5; tjd < 2460060.5; tjd += 17.0 / 1440.0 {
+			calcNutation(tjd, 0, nutExact)
+			swiNutation(tjd, 0, nutIntp)
+			for i := 0; i < 2; i++ {
+				maxErr = math.Max(maxErr, math.Abs(nutIntp[i]-nutExact[i])*RADTODEG*3600000)
+			}
+		}
+		if maxErr > maxNutIntpErrorMas {
+			t.Errorf("nutation model %d: maximum error of interpolation = %.3f mas; want <= %.1f mas", model,
+				maxErr, maxNutIntpErrorMas)
+		}
+	}
+}
+
+func TestNutationInterpolationModelChange(t *testing.T) {
+	defer SweSetInterpolateNut(false)
+	SweSetInterpolateNut(true)
+	nut := make([]float64, 2)
+	exact := make([]float64, 2)
+	tjd := 2460000.5
+	setNutModel(t, SEMOD_NUT_IAU_2000B)
+	swiNutation(tjd, 0, nut)
+	setNutModel(t, SEMOD_NUT_WOOLARD)
+	swiNutation(tjd+0.1, 0, nut)
+	calcNutation(tjd+0.1, 0, exact)
+	if math.Abs(nut[0]-exact[0])*RADTODEG*3600 > 0.01 {
+		t.Errorf("after a change of the nutation model, dpsi = %e; want %e", nut[0], exact[0])
+	}
+}
+
+func TestEpsilnLaskar(t *testing.T) {
+	// values from the C version of the Swiss Ephemeris (swi_epsiln)
+	tests := []struct {
+		tjd, want float64
+	}{
+		{2460000.5, 4.09040268413661534e-01},
+		{1721057.5, 4.13553623225014100e-01},
+	}
+	models := make([]int32, SEI_NMODELS)
+	models[SE_MODEL_PREC_LONGTERM] = SEMOD_PREC_LASKAR_1986
+	models[SE_MODEL_PREC_SHORTTERM] = SEMOD_PREC_LASKAR_1986
+	for _, tt := range tests {
+		if eps := epsiln(tt.tjd, 0, models); math.Abs(eps-tt.want) > 1e-15 {
+			t.Errorf("epsiln(%.1f) with SEMOD_PREC_LASKAR_1986 = %.17e; want %.17e", tt.tjd, eps, tt.want)
+		}
+	}
+}
+
+func TestNutationIau1980(t *testing.T) {
+	// values from the C version of the Swiss Ephemeris (swi_nutation), in radians
+	tests := []struct {
+		tjd   float64
+		model int32
+		want  [2]float64
+	}{
+		{2460000.5, SEMOD_NUT_IAU_1980, [2]float64{-4.49660202989236301e-05, 3.75060808077803582e-05}},
+		{2460000.5, SEMOD_NUT_IAU_CORR_1987, [2]float64{-4.49605405411433227e-05, 3.75274982150422215e-05}},
+		{1721057.5, SEMOD_NUT_IAU_1980, [2]float64{7.96354536421454062e-05, 1.18155251599775566e-05}},
+		{1721057.5, SEMOD_NUT_IAU_CORR_1987, [2]float64{7.96961670872213686e-05, 1.18204910877852649e-05}},
+	}
+	models := make([]int32, SEI_NMODELS)
+	nutlo := make([]float64, 2)
+	for _, tt := range tests {
+		models[SE_MODEL_NUT] = tt.model
+		nutation(tt.tjd, 0, nutlo, models)
+		if math.Abs(nutlo[0]-tt.want[0]) > 1e-17 || math.Abs(nutlo[1]-tt.want[1]) > 1e-17 {
+			t.Errorf("nutation(%.1f) with model %d = %.17e; want %.17e", tt.tjd, tt.model, nutlo, tt.want)
+		}
+	}
+}
+
+func benchmarkNutation(b *testing.B, model int32, interpolate bool) {
+	defer SweSetInterpolateNut(false)
+	SweSetInterpolateNut(interpolate)
+	nut := make([]float64, 2)
+	setNutModel(b, model)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// minute by minute
+		swiNutation(2460000.5+float64(i)/1440.0, 0, nut)
+	}
+}
+
+func BenchmarkNutationIau2000A(b *testing.B) {
+	benchmarkNutation(b, SEMOD_NUT_IAU_2000A, false)
+}
+
+func BenchmarkNutationIau2000AInterpolated(b *testing.B) {
+	benchmarkNutation(b, SEMOD_NUT_IAU_2000A, true)
+}
+
+func BenchmarkNutationIau2000B(b *testing.B) {
+	benchmarkNutation(b, SEMOD_NUT_IAU_2000B, false)
+}
+
+func BenchmarkNutationIau2000BInterpolated(b *testing.B) {
+	benchmarkNutation(b, SEMOD_NUT_IAU_2000B, true)
+}
+
+func TestSwiCartpolSpZeroPosition(t *testing.T) {
+	// with zero position, the direction of motion is returned
+	want := []float64{math.Pi / 2, math.Pi / 4, 0, 0, 0, math.Sqrt2}
+	x := []float64{0, 0, 0, 0, 1, 1}
+	l := make([]float64, 6)
+	swiCartpolSp(x, l)
+	// x = l is allowed
+	swiCartpolSp(x, x)
+	for i := range want {
+		if math.Abs(l[i]-want[i]) > 1e-15 || math.Abs(x[i]-want[i]) > 1e-15 {
+			t.Errorf("swiCartpolSp = %v, in place %v; want %v", l, x, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,660 @@
+package internal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Port: the functions in this file are not part of the C version. They read NAIF SPICE SPK kernels (.bsp), e.g. the
+// JPL ephemeris DE440s or the kernels of asteroids and comets from JPL Horizons, for SEFLG_SPKEPH.
+
+// NAIF ids of the bodies that are needed for every position
+const (
+	spkSsb   = 0   // solar system barycenter
+	spkSun   = 10  // sun
+	spkMoon  = 301 // moon
+	spkEarth = 399 // earth
+)
+
+// reference frames of the SPK segments
+const (
+	spkFrameJ2000      = 1  // equator and equinox J2000, the ICRF
+	spkFrameEclipJ2000 = 17 // ecliptic and equinox J2000
+)
+
+const (
+	spkRecordSize  = 1024              // size of the records of a DAF file in bytes
+	spkSummarySize = 5                 // size of a segment summary in doubles: 2 doubles and 6 integers
+	spkMaxChain    = 20                // maximum number of segments from a body to the barycenter
+	spkEpsJ2000    = 84381.448 / 3600. // obliquity of the frame ECLIPJ2000 in degrees
+)
+
+// spkSegment is a segment of an SPK kernel: the state of the body target relative to the body center in the time
+// range start .. end.
+type spkSegment struct {
+	target, center int32   // NAIF ids
+	frame, dtype   int32   // reference frame and segment type
+	start, end     float64 // time range in TDB seconds after J2000
+	begin, last    int     // first and last address of the data, in doubles from 1
+	name           string  // segment identifier
+	file           *spkFile
+	// control data at the end of the segment and epochs of types 13 and 21, read with the first evaluation
+	ctrl   []float64
+	epochs []float64
+}
+
+// spkFile is a loaded SPK kernel.
+type spkFile struct {
+	fnam  string
+	fptr  *os.File
+	order binary.ByteOrder
+	segs  []*spkSegment
+}
+
+// spkFiles holds the loaded SPK kernels in the order of loading.
+var spkFiles []*spkFile
+
+// SweLoadSpkFile loads the SPK kernel fname for SEFLG_SPKEPH. Without a directory, the file is searched in the
+// ephemeris path. If several segments cover a body, the last segment of the kernel that was loaded last is used, as
+// with SPICE. Segments of the types 2, 3, 13 and 21 in the frames J2000 and ECLIPJ2000 are supported.
+// The bodies are the sun (NAIF id 10), the barycenters of mercury - pluto (1 - 9), the earth (399), the moon (301)
+// and the asteroids SE_AST_OFFSET + n (20000000 + n or 2000000 + n). As positions are computed from the solar system
+// barycenter, the kernels must also provide the earth and the sun, e.g. de440s.bsp.
+func SweLoadSpkFile(fname string) error {
+	swiInitSwedIfStart()
+	if !swed.EphePathIsSet {
+		SweSetEphePath("")
+	}
+	var fptr *os.File
+	var err error
+	if strings.Contains(fname, DIR_GLUE) {
+		fptr, err = os.Open(fname)
+	} else {
+		fptr, err = SwiFopen(-1, fname, swed.EphePath)
+	}
+	if err != nil {
+		return err
+	}
+	f := &spkFile{fnam: fptr.Name(), fptr: fptr}
+	if err := f.readSummaries(); err != nil {
+		fptr.Close()
+		return err
+	}
+	spkFiles = append(spkFiles, f)
+	// positions in the save areas may have been computed with other kernels
+	freePlanets()
+	return nil
+}
+
+// spkCloseFiles closes and unloads all SPK kernels.
+func spkCloseFiles() {
+	for _, f := range spkFiles {
+		f.fptr.Close()
+	}
+	spkFiles = nil
+}
+
+// readSummaries reads the file record and the segment summaries of the kernel.
+func (f *spkFile) readSummaries() error {
+	var rec, names [spkRecordSize]byte
+	if _, err := f.fptr.ReadAt(rec[:], 0); err != nil {
+		return fmt.Errorf("SPK file %s: %v", f.fnam, err)
+	}
+	if idw := string(rec[:8]); idw != "DAF/SPK " && idw != "NAIF/DAF" {
+		return fmt.Errorf("%s is not an SPK file", f.fnam)
+	}
+	switch string(rec[88:96]) {
+	case "LTL-IEEE":
+		f.order = binary.LittleEndian
+	case "BIG-IEEE":
+		f.order = binary.BigEndian
+	default:
+		// older files have no format: the number of doubles in the summaries is 2
+		f.order = binary.LittleEndian
+		if binary.LittleEndian.Uint32(rec[8:]) != 2 {
+			f.order = binary.BigEndian
+		}
+	}
+	if nd, ni := int32(f.order.Uint32(rec[8:])), int32(f.order.Uint32(rec[12:])); nd != 2 || ni != 6 {
+		return fmt.Errorf("SPK file %s: summaries with %d doubles and %d integers instead of 2 and 6", f.fnam, nd, ni)
+	}
+	corrupt := fmt.Errorf("SPK file %s is corrupt", f.fnam)
+	next := int(int32(f.order.Uint32(rec[76:])))
+	for nrec := 0; next > 0; nrec++ {
+		if nrec > 100000 {
+			return corrupt
+		}
+		// summary record, followed by the name record
+		if _, err := f.fptr.ReadAt(rec[:], int64(next-1)*spkRecordSize); err != nil {
+			return fmt.Errorf("SPK file %s: %v", f.fnam, err)
+		}
+		if _, err := f.fptr.ReadAt(names[:], int64(next)*spkRecordSize); err != nil {
+			return fmt.Errorf("SPK file %s: %v", f.fnam, err)
+		}
+		nsum := int(f.double(rec[16:]))
+		if nsum < 0 || 3+nsum*spkSummarySize > spkRecordSize/8 {
+			return corrupt
+		}
+		for i := 0; i < nsum; i++ {
+			b := rec[24+i*spkSummarySize*8:]
+			var ic [6]int32
+			for j := range ic {
+				ic[j] = int32(f.order.Uint32(b[16+4*j:]))
+			}
+			seg := &spkSegment{target: ic[0], center: ic[1], frame: ic[2], dtype: ic[3], start: f.double(b),
+				end: f.double(b[8:]), begin: int(ic[4]), last: int(ic[5]), file: f}
+			if seg.begin < 1 || seg.last < seg.begin {
+				return corrupt
+			}
+			seg.name = strings.TrimRight(string(names[i*spkSummarySize*8:(i+1)*spkSummarySize*8]), " \x00")
+			f.segs = append(f.segs, seg)
+		}
+		next = int(f.double(rec[:]))
+	}
+	return nil
+}
+
+// double decodes a double of the kernel.
+func (f *spkFile) double(b []byte) float64 {
+	return math.Float64frombits(f.order.Uint64(b))
+}
+
+// read reads n doubles from the address addr (from 1).
+func (f *spkFile) read(addr, n int) ([]float64, error) {
+	b := make([]byte, n*8)
+	if _, err := f.fptr.ReadAt(b, int64(addr-1)*8); err != nil {
+		return nil, fmt.Errorf("read error: %v", err)
+	}
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = f.double(b[i*8:])
+	}
+	return x, nil
+}
+
+// spkFindSegment returns the segment that provides the body target at et: the last segment of the last loaded
+// kernel that covers et, or nil.
+func spkFindSegment(target int32, et float64) *spkSegment {
+	for i := len(spkFiles) - 1; i >= 0; i-- {
+		segs := spkFiles[i].segs
+		for j := len(segs) - 1; j >= 0; j-- {
+			if s := segs[j]; s.target == target && et >= s.start && et <= s.end {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// spkState computes the state of the body target relative to the solar system barycenter at et (TDB seconds after
+// J2000), in km and km/s, equator J2000. The centers of the segments are followed down to the barycenter.
+// Returns OK, NOT_AVAILABLE if the kernels do not provide a body of the chain at et, or ERR.
+func spkState(target int32, et float64, x []float64, serr *string) int {
+	clear(x[:6])
+	for body, n := target, 0; body != spkSsb; n++ {
+		seg := spkFindSegment(body, et)
+		if seg == nil || n == spkMaxChain {
+			if serr != nil {
+				*serr = fmt.Sprintf("SPK kernels do not provide body %d at JD %.1f", body, et/86400.0+J2000)
+				if body != target {
+					*serr += fmt.Sprintf(" (center of body %d)", target)
+				}
+			}
+			return NOT_AVAILABLE
+		}
+		var xs [6]float64
+		if retc := seg.state(et, xs[:], serr); retc != OK {
+			return retc
+		}
+		for i := 0; i <= 5; i++ {
+			x[i] += xs[i]
+		}
+		body = seg.center
+	}
+	return OK
+}
+
+// state computes the state of the target of the segment relative to its center at et, equator J2000.
+func (s *spkSegment) state(et float64, x []float64, serr *string) int {
+	var err error
+	switch {
+	case s.frame != spkFrameJ2000 && s.frame != spkFrameEclipJ2000:
+		err = fmt.Errorf("frame %d is not supported", s.frame)
+	case s.dtype == 2 || s.dtype == 3:
+		err = s.chebyshev(et, x)
+	case s.dtype == 13:
+		err = s.hermite(et, x)
+	case s.dtype == 21:
+		err = s.mda(et, x)
+	default:
+		err = fmt.Errorf("type %d is not supported", s.dtype)
+	}
+	if err != nil {
+		if serr != nil {
+			*serr = fmt.Sprintf("SPK segment '%s' of body %d in %s: %v", s.name, s.target, s.file.fnam, err)
+		}
+		return ERR
+	}
+	if s.frame == spkFrameEclipJ2000 {
+		sineps, coseps := math.Sin(-spkEpsJ2000*DEGTORAD), math.Cos(spkEpsJ2000*DEGTORAD)
+		swiCoortrf2(x, x, sineps, coseps)
+		swiCoortrf2(x[3:], x[3:], sineps, coseps)
+	}
+	return OK
+}
+
+// control returns the n control words at the end of the segment.
+func (s *spkSegment) control(n int) ([]float64, error) {
+	if s.ctrl == nil {
+		if s.last-s.begin+1 < n {
+			return nil, errors.New("segment is corrupt")
+		}
+		ctrl, err := s.file.read(s.last-n+1, n)
+		if err != nil {
+			return nil, err
+		}
+		s.ctrl = ctrl
+	}
+	return s.ctrl, nil
+}
+
+// readEpochs reads the n epochs of a segment of type 13 or 21 from the address addr.
+func (s *spkSegment) readEpochs(addr, n int) error {
+	if s.epochs != nil {
+		return nil
+	}
+	if n < 1 || addr+n-1 > s.last {
+		return errors.New("segment is corrupt")
+	}
+	epochs, err := s.file.read(addr, n)
+	if err != nil {
+		return err
+	}
+	s.epochs = epochs
+	return nil
+}
+
+// chebyshev evaluates a segment of type 2 (Chebyshev polynomials for the position) or type 3 (Chebyshev polynomials
+// for position and velocity). The records of a segment have the same length; each record contains the midpoint and
+// radius of its interval and the coefficients of the components.
+func (s *spkSegment) chebyshev(et float64, x []float64) error {
+	ctrl, err := s.control(4)
+	if err != nil {
+		return err
+	}
+	init, intlen, rsize, n := ctrl[0], ctrl[1], int(ctrl[2]), int(ctrl[3])
+	ncomp := 3
+	if s.dtype == 3 {
+		ncomp = 6
+	}
+	ncf := (rsize - 2) / ncomp
+	if intlen <= 0 || n < 1 || ncf < 1 || s.begin+n*rsize+3 > s.last {
+		return errors.New("segment is corrupt")
+	}
+	irec := min(max(int(math.Floor((et-init)/intlen)), 0), n-1)
+	rec, err := s.file.read(s.begin+irec*rsize, rsize)
+	if err != nil {
+		return err
+	}
+	radius := rec[1]
+	tc := (et - rec[0]) / radius
+	// Chebyshev polynomials and their derivatives
+	pc := make([]float64, ncf)
+	vc := make([]float64, ncf)
+	pc[0] = 1
+	if ncf > 1 {
+		pc[1] = tc
+		vc[1] = 1
+	}
+	for i := 2; i < ncf; i++ {
+		pc[i] = 2*tc*pc[i-1] - pc[i-2]
+		vc[i] = 2*pc[i-1] + 2*tc*vc[i-1] - vc[i-2]
+	}
+	for j := 0; j < 3; j++ {
+		x[j], x[j+3] = 0, 0
+		cf := rec[2+j*ncf : 2+(j+1)*ncf]
+		for i := ncf - 1; i >= 0; i-- {
+			x[j] += cf[i] * pc[i]
+		}
+		if s.dtype == 3 {
+			// velocity from its own coefficients
+			cf = rec[2+(j+3)*ncf : 2+(j+4)*ncf]
+			for i := ncf - 1; i >= 0; i-- {
+				x[j+3] += cf[i] * pc[i]
+			}
+		} else {
+			for i := ncf - 1; i >= 0; i-- {
+				x[j+3] += cf[i] * vc[i]
+			}
+			x[j+3] /= radius
+		}
+	}
+	return nil
+}
+
+// hermite evaluates a segment of type 13: states at unequal time steps, interpolated by Hermite polynomials from the
+// positions and velocities of a window of states around et. The segment contains the states, their epochs, a
+// directory of every 100th epoch, the window size - 1 and the number of states.
+func (s *spkSegment) hermite(et float64, x []float64) error {
+	ctrl, err := s.control(2)
+	if err != nil {
+		return err
+	}
+	winsiz, n := int(ctrl[0])+1, int(ctrl[1])
+	if winsiz < 1 || n < 1 {
+		return errors.New("segment is corrupt")
+	}
+	if err := s.readEpochs(s.begin+6*n, n); err != nil {
+		return err
+	}
+	winsiz = min(winsiz, n)
+	// first state of the window: with an even window size, et lies in the middle interval of the window, with an odd
+	// window size, the epoch nearest to et is in the middle of the window
+	i := sort.SearchFloat64s(s.epochs, et)
+	var first int
+	if winsiz%2 == 0 {
+		first = i - winsiz/2
+	} else {
+		if i == n || i > 0 && et-s.epochs[i-1] < s.epochs[i]-et {
+			i--
+		}
+		first = i - winsiz/2
+	}
+	first = min(max(first, 0), n-winsiz)
+	states, err := s.file.read(s.begin+6*first, 6*winsiz)
+	if err != nil {
+		return err
+	}
+	t := make([]float64, winsiz)
+	f := make([]float64, winsiz)
+	df := make([]float64, winsiz)
+	for k := range t {
+		t[k] = s.epochs[first+k] - et
+	}
+	for j := 0; j < 3; j++ {
+		for k := range f {
+			f[k], df[k] = states[6*k+j], states[6*k+j+3]
+		}
+		x[j], x[j+3] = spkHermite(t, f, df, 0)
+	}
+	return nil
+}
+
+// spkHermite returns the value and the derivative at tx of the Hermite polynomial with the values f and derivatives
+// df at the times t.
+func spkHermite(t, f, df []float64, tx float64) (float64, float64) {
+	n := 2 * len(t)
+	z := make([]float64, n)
+	d := make([]float64, n)
+	for i := range t {
+		z[2*i], z[2*i+1] = t[i], t[i]
+		d[2*i], d[2*i+1] = f[i], f[i]
+	}
+	// divided differences of the nodes, each node twice
+	for j := 1; j < n; j++ {
+		for i := n - 1; i >= j; i-- {
+			if j == 1 && i%2 == 1 {
+				d[i] = df[i/2]
+			} else {
+				d[i] = (d[i] - d[i-1]) / (z[i] - z[i-j])
+			}
+		}
+	}
+	// Newton form, with derivative
+	p, dp := d[n-1], 0.0
+	for i := n - 2; i >= 0; i-- {
+		dp = dp*(tx-z[i]) + p
+		p = p*(tx-z[i]) + d[i]
+	}
+	return p, dp
+}
+
+// mda evaluates a segment of type 21: extended modified difference arrays, as produced by the integrator of JPL for
+// the asteroids and comets of Horizons. Each record contains a difference line of 4 * maxdim + 11 doubles; the
+// segment contains the records, the final epochs of the records, a directory of every 100th epoch, maxdim and the
+// number of records.
+func (s *spkSegment) mda(et float64, x []float64) error {
+	ctrl, err := s.control(2)
+	if err != nil {
+		return err
+	}
+	maxdim, n := int(ctrl[0]), int(ctrl[1])
+	dlsize := 4*maxdim + 11
+	if maxdim < 1 || n < 1 {
+		return errors.New("segment is corrupt")
+	}
+	if err := s.readEpochs(s.begin+n*dlsize, n); err != nil {
+		return err
+	}
+	// the first record with a final epoch >= et
+	irec := min(sort.SearchFloat64s(s.epochs, et), n-1)
+	rec, err := s.file.read(s.begin+irec*dlsize, dlsize)
+	if err != nil {
+		return err
+	}
+	// reference epoch, step sizes, reference position and velocity, modified divided differences
+	tl := rec[0]
+	g := rec[1 : maxdim+1]
+	var refpos, refvel [3]float64
+	for j := 0; j < 3; j++ {
+		refpos[j] = rec[maxdim+1+2*j]
+		refvel[j] = rec[maxdim+2+2*j]
+	}
+	dt := rec[maxdim+7 : 4*maxdim+7]
+	kqmax1 := int(rec[4*maxdim+7])
+	var kq [3]int
+	for j := range kq {
+		kq[j] = int(rec[4*maxdim+8+j])
+		if kq[j] < 0 || kq[j] > maxdim {
+			return errors.New("segment is corrupt")
+		}
+	}
+	if kqmax1 < 2 || kqmax1 > maxdim+1 {
+		return errors.New("segment is corrupt")
+	}
+	// coefficients of the differences
+	delta := et - tl
+	tp := delta
+	mq2 := kqmax1 - 2
+	ks := kqmax1 - 1
+	fc := make([]float64, maxdim+1)
+	wc := make([]float64, maxdim)
+	w := make([]float64, maxdim+3)
+	fc[0] = 1
+	for j := 0; j < mq2; j++ {
+		if g[j] == 0 {
+			return errors.New("segment is corrupt: step size 0")
+		}
+		fc[j+1] = tp / g[j]
+		wc[j] = delta / g[j]
+		tp = delta + g[j]
+	}
+	for j := 1; j <= kqmax1; j++ {
+		w[j-1] = 1.0 / float64(j)
+	}
+	// with 1-based indices: w(j+ks) = fc(j+1)*w(j+ks1) - wc(j)*w(j+ks)
+	jx := 0
+	ks1 := ks - 1
+	for ks >= 2 {
+		jx++
+		for j := 1; j <= jx; j++ {
+			w[j+ks-1] = fc[j]*w[j+ks1-1] - wc[j-1]*w[j+ks-1]
+		}
+		ks = ks1
+		ks1--
+	}
+	// position
+	for i := 0; i < 3; i++ {
+		sum := 0.0
+		for j := kq[i]; j >= 1; j-- {
+			sum += dt[i*maxdim+j-1] * w[j+ks-1]
+		}
+		x[i] = refpos[i] + delta*(refvel[i]+delta*sum)
+	}
+	// velocity
+	for j := 1; j <= jx; j++ {
+		w[j+ks-1] = fc[j]*w[j+ks1-1] - wc[j-1]*w[j+ks-1]
+	}
+	ks--
+	for i := 0; i < 3; i++ {
+		sum := 0.0
+		for j := kq[i]; j >= 1; j-- {
+			sum += dt[i*maxdim+j-1] * w[j+ks-1]
+		}
+		x[i+3] = refvel[i] + delta*sum
+	}
+	return nil
+}
+
+// spkBody returns the NAIF id of the body ipli (internal planet number, or the external number of an asteroid) at et.
+// Asteroids have the id 20000000 + n of the newer kernels or 2000000 + n of the older ones. The barycenters of the
+// planet systems are used, as with the JPL ephemeris. Returns -1 for other bodies.
+func spkBody(ipli int, et float64) int32 {
+	var ast int32
+	switch {
+	case ipli == SEI_EARTH:
+		return spkEarth
+	case ipli == SEI_MOON:
+		return spkMoon
+	case ipli == SEI_MERCURY || ipli == SEI_VENUS:
+		return int32(ipli - SEI_MERCURY + 1)
+	case ipli >= SEI_MARS && ipli <= SEI_PLUTO:
+		// 3 is the earth-moon barycenter
+		return int32(ipli - SEI_MARS + 4)
+	case ipli == SEI_SUNBARY:
+		return spkSun
+	case ipli == SEI_CHIRON:
+		ast = 2060
+	case ipli == SEI_PHOLUS:
+		ast = 5145
+	case ipli >= SEI_CERES && ipli <= SEI_VESTA:
+		ast = int32(ipli - SEI_CERES + 1)
+	case ipli > SE_AST_OFFSET:
+		ast = int32(ipli - SE_AST_OFFSET)
+	default:
+		return -1
+	}
+	if ast < 1000000 && spkFindSegment(20000000+ast, et) == nil && spkFindSegment(2000000+ast, et) != nil {
+		return 2000000 + ast
+	}
+	return 20000000 + ast
+}
+
+// spkplan computes a body from the SPK kernels in barycentric cartesian equatorial coordinates J2000 (the moon
+// geocentric), as jplplan() with the JPL file. The barycentric earth and sun are computed as well, if they are saved
+// or returned.
+// tjd		julian day
+// ipli		internal planet number, or the external number of an asteroid
+// doSave	write new positions in save area swed.Pldat
+// xpret	position and speed of the body
+// xperet	of the earth
+// xpsret	of the barycentric sun
+// The return slices can be nil.
+// Returns OK, ERR or NOT_AVAILABLE if the kernels do not provide the body, the earth or the sun at tjd.
+func spkplan(tjd float64, ipli int, iflag int32, doSave bool, xpret, xperet, xpsret []float64, serr *string) int {
+	var xxp, xxe, xxs [6]float64
+	pdp := &swed.Pldat[SEI_ANYBODY]
+	if ipli < SEI_NPLANETS {
+		pdp = &swed.Pldat[ipli]
+	}
+	pedp := &swed.Pldat[SEI_EARTH]
+	psdp := &swed.Pldat[SEI_SUNBARY]
+	xp, xpe, xps := xxp[:], xxe[:], xxs[:]
+	if doSave {
+		xp, xpe, xps = pdp.X[:], pedp.X[:], psdp.X[:]
+	}
+	doEarth := doSave || ipli == SEI_EARTH || xperet != nil || ipli == SEI_MOON
+	doSunbary := doSave || ipli == SEI_SUNBARY || xpsret != nil || ipli == SEI_MOON
+	et := (tjd - J2000) * 86400.0
+	// state computes the body target in AU and AU/day, with the save area bdp
+	state := func(target int32, x []float64, bdp *PlanData) int {
+		if retc := spkState(target, et, x, serr); retc != OK {
+			return retc
+		}
+		for i := 0; i <= 2; i++ {
+			x[i] *= 1000.0 / AUNIT
+			x[i+3] *= 1000.0 * 86400.0 / AUNIT
+		}
+		if doSave {
+			bdp.Teval = tjd
+			bdp.Xflgs = -1 // new light-time etc. required
+			bdp.Iephe = SEFLG_SPKEPH
+		}
+		return OK
+	}
+	if len(spkFiles) == 0 {
+		if serr != nil {
+			*serr = "no SPK kernels loaded"
+		}
+		return NOT_AVAILABLE
+	}
+	if doEarth {
+		// barycentric earth
+		if tjd != pedp.Teval || tjd == 0 || pedp.Iephe != SEFLG_SPKEPH {
+			if retc := state(spkEarth, xpe, pedp); retc != OK {
+				return retc
+			}
+		} else {
+			xpe = pedp.X[:]
+		}
+		if xperet != nil {
+			copy(xperet[:6], xpe)
+		}
+	}
+	if doSunbary {
+		// barycentric sun
+		if tjd != psdp.Teval || tjd == 0 || psdp.Iephe != SEFLG_SPKEPH {
+			if retc := state(spkSun, xps, psdp); retc != OK {
+				return retc
+			}
+		} else {
+			xps = psdp.X[:]
+		}
+		if xpsret != nil {
+			copy(xpsret[:6], xps)
+		}
+	}
+	switch ipli {
+	case SEI_EARTH:
+		// earth is wanted
+		copy(xp, xpe)
+	case SEI_SUNBARY:
+		// sunbary is wanted
+		copy(xp, xps)
+	default:
+		target := spkBody(ipli, et)
+		if target < 0 {
+			if serr != nil {
+				*serr = fmt.Sprintf("body %d is not supported with SPK kernels", ipli)
+			}
+			return ERR
+		}
+		if retc := state(target, xp, pdp); retc != OK {
+			return retc
+		}
+		// geocentric moon
+		if ipli == SEI_MOON {
+			for i := 0; i <= 5; i++ {
+				xp[i] -= xpe[i]
+			}
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp)
+	}
+	return OK
+}
+
+// spkFileName returns the path of the kernel that provides the body ipli (see spkBody) at tjd, or an empty string.
+func spkFileName(ipli int, tjd float64) string {
+	et := (tjd - J2000) * 86400.0
+	if seg := spkFindSegment(spkBody(ipli, et), et); seg != nil {
+		return seg.file.fnam
+	}
+	return ""
+}
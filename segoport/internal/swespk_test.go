@@ -0,0 +1,423 @@
+package internal
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// spkTestSegment is a segment of a synthetic SPK kernel.
+type spkTestSegment struct {
+	target, center, frame, dtype int32
+	start, end                   float64 // TDB seconds after J2000
+	name                         string
+	data                         []float64
+}
+
+// writeSpkFile writes an SPK kernel with the segments segs and the byte order order: the file record, a summary
+// record, a name record and the data of the segments.
+func writeSpkFile(t *testing.T, fnam string, order binary.ByteOrder, segs ...spkTestSegment) {
+	t.Helper()
+	record := func(b []byte) []byte {
+		return append(b, make([]byte, spkRecordSize-len(b))...)
+	}
+	var data, summaries, names []byte
+	addr := 3*spkRecordSize/8 + 1
+	summaries, _ = binary.Append(nil, order, []float64{0, 0, float64(len(segs))})
+	for _, s := range segs {
+		summaries, _ = binary.Append(summaries, order, []float64{s.start, s.end})
+		summaries, _ = binary.Append(summaries, order, []int32{s.target, s.center, s.frame, s.dtype, int32(addr),
+			int32(addr + len(s.data) - 1)})
+		names = append(names, []byte(s.name+strings.Repeat(" ", 8*spkSummarySize-len(s.name)))...)
+		data, _ = binary.Append(data, order, s.data)
+		addr += len(s.data)
+	}
+	locfmt := "LTL-IEEE"
+	if order == binary.BigEndian {
+		locfmt = "BIG-IEEE"
+	}
+	file := []byte("DAF/SPK ")
+	file, _ = binary.Append(file, order, []int32{2, 6})
+	file = append(file, []byte("synthetic"+strings.Repeat(" ", 51))...)
+	file, _ = binary.Append(file, order, []int32{2, 2, int32(addr)})
+	file = append(file, locfmt...)
+	out := append(record(file), record(summaries)...)
+	out = append(out, record(names)...)
+	if err := os.WriteFile(fnam, append(out, data...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// spkEt returns the TDB seconds after J2000 of the julian day tjd.
+func spkEt(tjd float64) float64 {
+	return (tjd - J2000) * 86400.0
+}
+
+// spkJplSegments returns segments of type 2 with the data of the synthetic JPL file: the barycenters of mercury -
+// pluto, the sun, and the moon and the earth relative to the earth-moon barycenter.
+func spkJplSegments() []spkTestSegment {
+	ipt, recs := jplTestData()
+	// coefficients of JPL body b with factor fac
+	segment := func(target, center int32, b int, fac float64) spkTestSegment {
+		s := spkTestSegment{target: target, center: center, frame: spkFrameJ2000, dtype: 2,
+			start: spkEt(jplTestStart), end: spkEt(jplTestStart + jplTestNseg*jplTestSeg), name: "DE-0405"}
+		for _, buf := range recs {
+			s.data = append(s.data, spkEt((buf[0]+buf[1])/2), (buf[1]-buf[0])/2*86400.0)
+			for c := 0; c < 3; c++ {
+				for _, cf := range buf[int(ipt[b*3])-1+c*jplTestNcf:][:jplTestNcf] {
+					s.data = append(s.data, cf*fac)
+				}
+			}
+		}
+		s.data = append(s.data, s.start, jplTestSeg*86400.0, float64(2+3*jplTestNcf), float64(len(recs)))
+		return s
+	}
+	var segs []spkTestSegment
+	for b := 0; b <= 8; b++ {
+		segs = append(segs, segment(int32(b+1), spkSsb, b, 1))
+	}
+	segs = append(segs, segment(spkSun, spkSsb, 10, 1))
+	segs = append(segs, segment(spkMoon, 3, 9, jplTestEmrat/(1+jplTestEmrat)))
+	segs = append(segs, segment(spkEarth, 3, 9, -1/(1+jplTestEmrat)))
+	return segs
+}
+
+// spkType3 converts a segment of type 2 into type 3, with the coefficients of the velocity.
+func spkType3(s spkTestSegment) spkTestSegment {
+	n := len(s.data)
+	rsize, nrec := int(s.data[n-2]), int(s.data[n-1])
+	ncf := (rsize - 2) / 3
+	s3 := s
+	s3.dtype = 3
+	s3.data = nil
+	for r := 0; r < nrec; r++ {
+		rec := s.data[r*rsize : (r+1)*rsize]
+		s3.data = append(s3.data, rec...)
+		for c := 0; c < 3; c++ {
+			cf := rec[2+c*ncf : 2+(c+1)*ncf]
+			// derivative of the Chebyshev series
+			d := make([]float64, ncf+1)
+			for k := ncf - 1; k >= 1; k-- {
+				d[k-1] = d[k+1] + 2*float64(k)*cf[k]
+			}
+			d[0] /= 2
+			for k := 0; k < ncf; k++ {
+				s3.data = append(s3.data, d[k]/rec[1])
+			}
+		}
+	}
+	s3.data = append(s3.data, s.data[n-4], s.data[n-3], float64(2+6*ncf), float64(nrec))
+	return s3
+}
+
+func TestSweCalcSpk(t *testing.T) {
+	dir := t.TempDir()
+	writeJplFile(t, filepath.Join(dir, "de405.eph"), binary.LittleEndian)
+	segs := spkJplSegments()
+	writeSpkFile(t, filepath.Join(dir, "de405.bsp"), binary.LittleEndian, segs...)
+	writeSpkFile(t, filepath.Join(dir, "de405be.bsp"), binary.BigEndian, segs...)
+	// mars with type 3, loaded after the planets
+	writeSpkFile(t, filepath.Join(dir, "mars.bsp"), binary.BigEndian, spkType3(segs[3]))
+	SweSetEphePath(dir)
+	defer SweSetEphePath("")
+	defer SweSetJplFile(SE_FNAME_DFT)
+	defer spkCloseFiles()
+	if err := SweSetJplFile("de405.eph"); err != nil {
+		t.Fatalf("SweSetJplFile: %v", err)
+	}
+	// without kernels, the JPL ephemeris is used
+	_, iflgret, prov, err := SweCalc(2451545.0, SE_MARS, SEFLG_SPKEPH|SEFLG_SPEED)
+	if iflgret != SEFLG_JPLEPH|SEFLG_SPEED || prov.Iephe != SEFLG_JPLEPH || !prov.Fallback || err == nil ||
+		!strings.Contains(err.Error(), "trying JPL Eph") {
+		t.Errorf("SweCalc without kernels: flags %d, %+v, error %v; want the JPL ephemeris", iflgret, prov, err)
+	}
+	flags := []int32{SEFLG_SPEED, SEFLG_SPEED | SEFLG_EQUATORIAL | SEFLG_J2000, SEFLG_SPEED | SEFLG_HELCTR,
+		SEFLG_SPEED | SEFLG_BARYCTR | SEFLG_TRUEPOS, SEFLG_SPEED | SEFLG_NOABERR | SEFLG_XYZ}
+	bodies := []int{SE_SUN, SE_MOON, SE_MERCURY, SE_MARS, SE_JUPITER, SE_PLUTO, SE_EARTH, SE_TRUE_NODE, SE_OSCU_APOG}
+	for _, kernel := range []string{"de405.bsp", "de405be.bsp"} {
+		spkCloseFiles()
+		for _, fnam := range []string{kernel, filepath.Join(dir, "mars.bsp")} {
+			if err := SweLoadSpkFile(fnam); err != nil {
+				t.Fatalf("SweLoadSpkFile(%s): %v", fnam, err)
+			}
+		}
+		for _, tjd := range []float64{2451545.0, 2451580.3} {
+			for _, iflag := range flags {
+				for _, ipl := range bodies {
+					if iflag&(SEFLG_HELCTR|SEFLG_BARYCTR) != 0 && ipl >= SE_TRUE_NODE {
+						continue
+					}
+					want, wantFlag, _, _ := SweCalc(tjd, ipl, iflag|SEFLG_JPLEPH)
+					x, iflgret, prov, err := SweCalc(tjd, ipl, iflag|SEFLG_SPKEPH)
+					wantFnam := filepath.Join(dir, kernel)
+					if ipl == SE_MARS {
+						wantFnam = filepath.Join(dir, "mars.bsp")
+					}
+					if err != nil || iflgret != wantFlag&^SEFLG_JPLEPH|SEFLG_SPKEPH ||
+						prov != (Provenance{Iephe: SEFLG_SPKEPH, Fnam: wantFnam}) {
+						t.Errorf("SweCalc(%s, %f, %d, %d): flags %d, %+v, error %v", kernel, tjd, ipl, iflag, iflgret,
+							prov, err)
+					}
+					// the speed of the light deflection is a difference over DEFL_SPEED_INTV, which amplifies
+					// rounding errors
+					for i := range x {
+						if math.Abs(x[i]-want[i]) > 1e-9 && (i < 3 || math.Abs(x[i]-want[i]) > 1e-7) {
+							t.Errorf("SweCalc(%s, %f, %d, %d) = %v; want %v", kernel, tjd, ipl, iflag, x, want)
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+	// asteroid (433) Eros with the orbit of mars, as states for Hermite interpolation, within the first record of
+	// the planets
+	days := []float64{0, 2, 5, 7, 10, 14, 17, 20, 24, 27, 30, 31.5}
+	eros := spkTestSegment{target: 2000433, center: spkSsb, frame: spkFrameJ2000, dtype: 13,
+		start: spkEt(jplTestStart), end: spkEt(jplTestStart + 31.5), name: "EROS"}
+	for _, d := range days {
+		var xs [6]float64
+		if retc := spkState(4, spkEt(jplTestStart+d), xs[:], nil); retc != OK {
+			t.Fatalf("spkState for mars: %d", retc)
+		}
+		eros.data = append(eros.data, xs[:]...)
+	}
+	for _, d := range days {
+		eros.data = append(eros.data, spkEt(jplTestStart+d))
+	}
+	eros.data = append(eros.data, 8-1, float64(len(days)))
+	writeSpkFile(t, filepath.Join(dir, "eros.bsp"), binary.LittleEndian, eros)
+	if err := SweLoadSpkFile("eros.bsp"); err != nil {
+		t.Fatalf("SweLoadSpkFile(eros.bsp): %v", err)
+	}
+	for _, iflag := range flags {
+		for _, tjd := range []float64{jplTestStart + 3.3, jplTestStart + 16, jplTestStart + 31} {
+			want, _, _, _ := SweCalc(tjd, SE_MARS, iflag|SEFLG_SPKEPH)
+			x, iflgret, prov, err := SweCalc(tjd, SE_AST_OFFSET+433, iflag|SEFLG_SPKEPH)
+			if err != nil || iflgret&SEFLG_EPHMASK != SEFLG_SPKEPH || prov.Fnam != filepath.Join(dir, "eros.bsp") {
+				t.Errorf("SweCalc(%f, Eros, %d): flags %d, %+v, error %v", tjd, iflag, iflgret, prov, err)
+			}
+			for i := range x {
+				if math.Abs(x[i]-want[i]) > 1e-8 {
+					t.Errorf("SweCalc(%f, Eros, %d) = %v; want %v", tjd, iflag, x, want)
+					break
+				}
+			}
+		}
+	}
+	// beyond the segment of Eros, the fallback to the JPL ephemeris needs an asteroid file, which is missing
+	if _, iflgret, _, err := SweCalc(jplTestStart+40, SE_AST_OFFSET+433, SEFLG_SPKEPH); iflgret != ERR ||
+		err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("SweCalc for Eros beyond the kernel: flags %d, error %v; want ERR", iflgret, err)
+	}
+}
+
+func TestSpkState(t *testing.T) {
+	dir := t.TempDir()
+	defer spkCloseFiles()
+	// cubic motion with difference arrays: x = r + v*d + a*d^2/2 + j*d^3/6
+	et0 := spkEt(2460000.5)
+	g := 86400.0
+	r, v, a, j := [3]float64{1e8, -2e8, 3e7}, [3]float64{10, -20, 5}, [3]float64{1e-4, 2e-4, -3e-5},
+		[3]float64{1e-10, -4e-11, 2e-11}
+	maxdim := 4
+	rec := []float64{et0, g, 0, 0, 0}
+	for c := 0; c < 3; c++ {
+		rec = append(rec, r[c], v[c])
+	}
+	for c := 0; c < 3; c++ {
+		rec = append(rec, a[c], j[c]*g, 0, 0)
+	}
+	rec = append(rec, 3, 2, 2, 2)
+	mda := spkTestSegment{target: 2000001, center: spkSsb, frame: spkFrameJ2000, dtype: 21, start: et0 - 5*86400,
+		end: et0 + 10*86400, name: "CERES"}
+	mda.data = append(rec, et0+10*86400, float64(maxdim), 1)
+	ecl := mda
+	ecl.target, ecl.frame = 2000002, spkFrameEclipJ2000
+	unsupported := mda
+	unsupported.target, unsupported.dtype = 2000003, 9
+	fnam := filepath.Join(dir, "mda.bsp")
+	writeSpkFile(t, fnam, binary.LittleEndian, mda, ecl, unsupported)
+	if err := SweLoadSpkFile(fnam); err != nil {
+		t.Fatalf("SweLoadSpkFile: %v", err)
+	}
+	if n := len(spkFiles[0].segs); n != 3 || spkFiles[0].segs[0].name != "CERES" {
+		t.Fatalf("SweLoadSpkFile: %d segments; want 3", n)
+	}
+	sine, cose := math.Sincos(spkEpsJ2000 * DEGTORAD)
+	for _, days := range []float64{-5, -1.5, 0, 3.25, 10} {
+		d := days * 86400
+		var want [6]float64
+		for c := 0; c < 3; c++ {
+			want[c] = r[c] + v[c]*d + a[c]*d*d/2 + j[c]*d*d*d/6
+			want[c+3] = v[c] + a[c]*d + j[c]*d*d/2
+		}
+		var x [6]float64
+		if retc := spkState(2000001, et0+d, x[:], nil); retc != OK {
+			t.Errorf("spkState(%f days): %d", days, retc)
+		}
+		for i := range x {
+			if math.Abs(x[i]-want[i]) > 1e-9*math.Max(1, math.Abs(want[i])) {
+				t.Errorf("spkState(%f days) = %v; want %v", days, x, want)
+				break
+			}
+		}
+		// ecliptic frame
+		for _, k := range []int{0, 3} {
+			want[k+1], want[k+2] = want[k+1]*cose-want[k+2]*sine, want[k+1]*sine+want[k+2]*cose
+		}
+		if retc := spkState(2000002, et0+d, x[:], nil); retc != OK {
+			t.Errorf("spkState(%f days) for ECLIPJ2000: %d", days, retc)
+		}
+		for i := range x {
+			if math.Abs(x[i]-want[i]) > 1e-9*math.Max(1, math.Abs(want[i])) {
+				t.Errorf("spkState(%f days) for ECLIPJ2000 = %v; want %v", days, x, want)
+				break
+			}
+		}
+	}
+	var x [6]float64
+	var serr string
+	if retc := spkState(2000001, et0+11*86400, x[:], &serr); retc != NOT_AVAILABLE ||
+		!strings.Contains(serr, "do not provide body 2000001") {
+		t.Errorf("spkState beyond the segment: %d, %q; want NOT_AVAILABLE", retc, serr)
+	}
+	if retc := spkState(2000003, et0, x[:], &serr); retc != ERR || !strings.Contains(serr, "type 9 is not supported") {
+		t.Errorf("spkState for type 9: %d, %q; want ERR", retc, serr)
+	}
+	// Hermite interpolation of a cubic with two states
+	tt := []float64{-1, 2}
+	cubic := func(x float64) (float64, float64) {
+		return 3 - 2*x + x*x - 0.5*x*x*x, -2 + 2*x - 1.5*x*x
+	}
+	f0, df0 := cubic(tt[0])
+	f1, df1 := cubic(tt[1])
+	for _, tx := range []float64{-1, 0.3, 1.7, 2} {
+		p, dp := spkHermite(tt, []float64{f0, f1}, []float64{df0, df1}, tx)
+		if wp, wdp := cubic(tx); math.Abs(p-wp) > 1e-12 || math.Abs(dp-wdp) > 1e-12 {
+			t.Errorf("spkHermite(%f) = %f, %f; want %f, %f", tx, p, dp, wp, wdp)
+		}
+	}
+	// no SPK file
+	other := filepath.Join(dir, "other.bsp")
+	if err := os.WriteFile(other, make([]byte, spkRecordSize), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SweLoadSpkFile(other); err == nil || !strings.Contains(err.Error(), "is not an SPK file") {
+		t.Errorf("SweLoadSpkFile(other.bsp): error %v", err)
+	}
+	if err := SweLoadSpkFile("missing.bsp"); err == nil {
+		t.Errorf("SweLoadSpkFile(missing.bsp): no error")
+	}
+}
+
+// mdaPolynomials returns the coefficients in powers of delta of the basis of the modified divided differences with
+// the step sizes g: phi[0] = 1, phi[k] = phi[k-1] * (delta + g[k-2]) / g[k-1], with g[-1] = 0. Also returned are the
+// first and second integrals of the basis from 0 to delta.
+func mdaPolynomials(g []float64, n int) (phi, phi1, phi2 [][]float64) {
+	p := []float64{1}
+	for k := 0; k < n; k++ {
+		if k > 0 {
+			g0 := 0.0
+			if k > 1 {
+				g0 = g[k-2]
+			}
+			q := make([]float64, len(p)+1)
+			for i, c := range p {
+				q[i+1] += c / g[k-1]
+				q[i] += c * g0 / g[k-1]
+			}
+			p = q
+		}
+		i1 := make([]float64, len(p)+1)
+		i2 := make([]float64, len(p)+2)
+		for i, c := range p {
+			i1[i+1] = c / float64(i+1)
+			i2[i+2] = c / float64((i+1)*(i+2))
+		}
+		phi, phi1, phi2 = append(phi, p), append(phi1, i1), append(phi2, i2)
+	}
+	return phi, phi1, phi2
+}
+
+func TestSpkStateMdaHighOrder(t *testing.T) {
+	defer spkCloseFiles()
+	// two records with 15 differences, of which 12 are used, and irregular step sizes, as in the kernels of Horizons
+	maxdim, kqmax1 := 15, 13
+	kq := [3]int{12, 11, 10}
+	et0 := spkEt(2460000.5)
+	type mdaRecord struct {
+		tl             float64
+		g              []float64
+		refpos, refvel [3]float64
+		dt             [3][]float64
+	}
+	var recs []mdaRecord
+	for r, tl := range []float64{et0, et0 + 12*86400} {
+		rec := mdaRecord{tl: tl, refpos: [3]float64{2.1e8, -1.4e8 + float64(r)*1e6, 3.3e7},
+			refvel: [3]float64{11.5, 14.2, -3.1 + float64(r)}}
+		for k := 1; k <= maxdim; k++ {
+			rec.g = append(rec.g, (1.3*float64(k)+0.4*math.Sin(float64(3*k+r)))*86400)
+		}
+		for c := 0; c < 3; c++ {
+			for k := 0; k < maxdim; k++ {
+				rec.dt[c] = append(rec.dt[c], 1e-6*math.Cos(float64(k+4*c+r))/float64(k+1))
+			}
+		}
+		recs = append(recs, rec)
+	}
+	s := spkTestSegment{target: 2000004, center: spkSsb, frame: spkFrameJ2000, dtype: 21, start: et0 - 12*86400,
+		end: et0 + 12*86400, name: "MDA"}
+	for _, rec := range recs {
+		s.data = append(s.data, rec.tl)
+		s.data = append(s.data, rec.g...)
+		for c := 0; c < 3; c++ {
+			s.data = append(s.data, rec.refpos[c], rec.refvel[c])
+		}
+		for c := 0; c < 3; c++ {
+			s.data = append(s.data, rec.dt[c]...)
+		}
+		s.data = append(s.data, float64(kqmax1), float64(kq[0]), float64(kq[1]), float64(kq[2]))
+	}
+	s.data = append(s.data, recs[0].tl, recs[1].tl, float64(maxdim), float64(len(recs)))
+	fnam := filepath.Join(t.TempDir(), "mda.bsp")
+	writeSpkFile(t, fnam, binary.BigEndian, s)
+	if err := SweLoadSpkFile(fnam); err != nil {
+		t.Fatalf("SweLoadSpkFile: %v", err)
+	}
+	for _, days := range []float64{-12, -7.3, -0.5, 0, 0.5, 4.75, 11.9, 12} {
+		et := et0 + days*86400
+		rec := recs[0]
+		if et > rec.tl {
+			rec = recs[1]
+		}
+		delta := et - rec.tl
+		_, phi1, phi2 := mdaPolynomials(rec.g, maxdim)
+		var want [6]float64
+		for c := 0; c < 3; c++ {
+			want[c] = rec.refpos[c] + rec.refvel[c]*delta
+			want[c+3] = rec.refvel[c]
+			for k := 0; k < kq[c]; k++ {
+				for i := len(phi2[k]) - 1; i >= 0; i-- {
+					want[c] += rec.dt[c][k] * phi2[k][i] * math.Pow(delta, float64(i))
+				}
+				for i := len(phi1[k]) - 1; i >= 0; i-- {
+					want[c+3] += rec.dt[c][k] * phi1[k][i] * math.Pow(delta, float64(i))
+				}
+			}
+		}
+		var x [6]float64
+		if retc := spkState(2000004, et, x[:], nil); retc != OK {
+			t.Fatalf("spkState(%f days): %d", days, retc)
+		}
+		for i := range x {
+			if math.Abs(x[i]-want[i]) > 1e-9*math.Max(1, math.Abs(want[i])) {
+				t.Errorf("spkState(%f days) = %v; want %v", days, x, want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// Port: the functions in this file are not part of the C version. They write ephemeris files in the format that is
+// read by readConst and getNewSegment, e.g. for asteroid orbits that are computed with other software.
+
+// EphemerisBody contains the data of one body for WriteEphemerisFile.
+type EphemerisBody struct {
+	// body number: SEI_* for planets and moon, SE_AST_OFFSET + MPC number for asteroids and the number of the
+	// planetary moon (e.g. 9501) for planetary moons
+	Ipl     int
+	Iflg    int32   // SEI_FLG_HELIO, SEI_FLG_ROTATE and SEI_FLG_ELLIPSE (which requires SEI_FLG_ROTATE)
+	Ncoe    int     // number of Chebyshev coefficients per segment and coordinate, at most MAXORD + 1
+	Tfstart float64 // start of the first segment, Julian day (TT)
+	Dseg    float64 // segment size in days
+	// orbital elements for SEI_FLG_ROTATE and SEI_FLG_ELLIPSE, see PlanData and rotBack
+	Telem float64
+	Prot  float64
+	Qrot  float64
+	Dprot float64
+	Dqrot float64
+	Peri  float64
+	Dperi float64
+	Refep []float64 // Chebyshev coefficients of the reference ellipse, x and y (2 * Ncoe), for SEI_FLG_ELLIPSE
+	// Chebyshev coefficients for each segment: x, y and z (Ncoe each) of the position for the mean equator and
+	// equinox J2000, as evaluated by swiEcheb with -1 at the start and 1 at the end of the segment
+	Segments [][]float64
+}
+
+// EphemerisFile contains the data for WriteEphemerisFile.
+type EphemerisFile struct {
+	Fversion   int              // version number of file
+	Copyright  string           // copyright line
+	SwephDenum int32            // DE number of JPL ephemeris, which this file is derived from
+	Astelem    string           // orbital elements record, for files with a single asteroid or planetary moon
+	Astnam     string           // name of the asteroid or planetary moon
+	ByteOrder  binary.ByteOrder // nil for little-endian
+	Gcdat      GenConst         // general constants, the defaults are used if Clight is 0
+	Bodies     []EphemerisBody
+}
+
+// WriteEphemerisFile writes an ephemeris file that can be read by readConst and getNewSegment. The name of the file
+// (without path) is written into the header and determines the type of file, as for ReadFileHeader: files with a
+// single asteroid or planetary moon need an elements record. If none is given in Astelem, a record with only the
+// number and the name (Astnam) is written.
+// The coefficients are rotated to the orbital plane and reduced by the reference ellipse according to the flags of
+// each body, and packed as described in getNewSegment.
+func WriteEphemerisFile(fnam string, ef *EphemerisFile) error {
+	order := ef.ByteOrder
+	if order == nil {
+		order = binary.LittleEndian
+	}
+	if len(ef.Bodies) < 1 || len(ef.Bodies) > 20 {
+		return fmt.Errorf("%d bodies in ephemeris file, must be 1 to 20", len(ef.Bodies))
+	}
+	isAst := fileNumberFromName(fnam) == SEI_FILE_ANY_AST
+	tfstart, tfend := math.Inf(1), math.Inf(-1)
+	nbytesIpl := 2
+	for k := range ef.Bodies {
+		bd := &ef.Bodies[k]
+		if err := checkEphemerisBody(bd); err != nil {
+			return err
+		}
+		tfstart = math.Min(tfstart, bd.Tfstart)
+		tfend = math.Max(tfend, bd.Tfstart+float64(len(bd.Segments))*bd.Dseg)
+		if bd.Ipl > math.MaxInt16 {
+			nbytesIpl = 4
+		}
+	}
+	var b bytes.Buffer
+	w := func(data any) {
+		// writing to a bytes.Buffer does not fail
+		_ = binary.Write(&b, order, data)
+	}
+	fmt.Fprintf(&b, "SWISSEPH version %d\r\n", ef.Fversion)
+	fmt.Fprintf(&b, "%s\r\n", filepath.Base(fnam))
+	fmt.Fprintf(&b, "%s\r\n", ef.Copyright)
+	if isAst {
+		astelem := ef.Astelem
+		if astelem == "" {
+			nr := ef.Bodies[0].Ipl
+			if nr >= SE_AST_OFFSET {
+				nr -= SE_AST_OFFSET
+			}
+			astelem = fmt.Sprintf("%5d %-19s", nr, ef.Astnam)
+		}
+		fmt.Fprintf(&b, "%s\r\n", astelem)
+	}
+	w(int32(SEI_FILE_TEST_ENDIAN))
+	flenPos := b.Len()
+	w(int32(0)) // length of file, set below
+	w(ef.SwephDenum)
+	w(tfstart)
+	w(tfend)
+	if nbytesIpl == 4 {
+		w(int16(len(ef.Bodies) + 256))
+	} else {
+		w(int16(len(ef.Bodies)))
+	}
+	for _, bd := range ef.Bodies {
+		if nbytesIpl == 4 {
+			w(int32(bd.Ipl))
+		} else {
+			w(int16(bd.Ipl))
+		}
+	}
+	if isAst {
+		var astnam [30]byte
+		copy(astnam[:], ef.Astnam)
+		b.Write(astnam[:])
+	}
+	crcPos := b.Len()
+	if crcPos-1 > 2*AS_MAXCH {
+		return fmt.Errorf("header of ephemeris file too long: %d bytes", crcPos)
+	}
+	w(uint32(0)) // CRC, set below
+	gc := ef.Gcdat
+	if gc.Clight == 0 {
+		gc = GenConst{Clight: CLIGHT, Aunit: AUNIT, Helgravconst: HELGRAVCONST, Ratme: EARTH_MOON_MRAT,
+			Sunradius: SUN_RADIUS}
+	}
+	w([]float64{gc.Clight, gc.Aunit, gc.Helgravconst, gc.Ratme, gc.Sunradius})
+	// constants of the bodies
+	lndxPos := make([]int, len(ef.Bodies))
+	segments := make([][][]float64, len(ef.Bodies))
+	rmax := make([]float64, len(ef.Bodies))
+	for k := range ef.Bodies {
+		bd := &ef.Bodies[k]
+		var lng int32
+		segments[k], rmax[k], lng = encodeEphemerisBody(bd)
+		lndxPos[k] = b.Len()
+		w(int32(0)) // file position of index, set below
+		b.WriteByte(byte(bd.Iflg))
+		b.WriteByte(byte(bd.Ncoe))
+		w(lng)
+		w([]float64{bd.Tfstart, bd.Tfstart + float64(len(bd.Segments))*bd.Dseg, bd.Dseg, bd.Telem, bd.Prot,
+			bd.Dprot, bd.Qrot, bd.Dqrot, bd.Peri, bd.Dperi})
+		if bd.Iflg&SEI_FLG_ELLIPSE != 0 {
+			w(bd.Refep)
+		}
+	}
+	// index and segments
+	for k := range ef.Bodies {
+		lndx0 := b.Len()
+		order.PutUint32(b.Bytes()[lndxPos[k]:], uint32(lndx0))
+		b.Write(make([]byte, 3*len(segments[k])))
+		for iseg, segp := range segments[k] {
+			fpos := b.Len()
+			if fpos >= 1<<24 {
+				return errors.New("ephemeris file too large, segments must start within the first 16 MB")
+			}
+			putUint(b.Bytes()[lndx0+3*iseg:], uint32(fpos), 3, order)
+			if err := packSegment(&b, segp, ef.Bodies[k].Ncoe, rmax[k], order); err != nil {
+				return fmt.Errorf("body %d, segment %d: %w", ef.Bodies[k].Ipl, iseg, err)
+			}
+		}
+	}
+	data := b.Bytes()
+	order.PutUint32(data[flenPos:], uint32(len(data)))
+	order.PutUint32(data[crcPos:], swiCrc32(data[:crcPos]))
+	return os.WriteFile(fnam, data, 0o644)
+}
+
+// checkEphemerisBody checks the consistency of the data of a body for WriteEphemerisFile.
+func checkEphemerisBody(bd *EphemerisBody) error {
+	if bd.Ncoe < 1 || bd.Ncoe > MAXORD+1 {
+		return fmt.Errorf("body %d: %d coefficients, must be 1 to %d", bd.Ipl, bd.Ncoe, MAXORD+1)
+	}
+	if bd.Dseg <= 0 || len(bd.Segments) == 0 {
+		return fmt.Errorf("body %d: no segments", bd.Ipl)
+	}
+	for iseg, segp := range bd.Segments {
+		if len(segp) != 3*bd.Ncoe {
+			return fmt.Errorf("body %d, segment %d: %d coefficients instead of %d", bd.Ipl, iseg, len(segp),
+				3*bd.Ncoe)
+		}
+	}
+	if bd.Iflg&SEI_FLG_ELLIPSE != 0 {
+		if bd.Iflg&SEI_FLG_ROTATE == 0 {
+			return fmt.Errorf("body %d: SEI_FLG_ELLIPSE requires SEI_FLG_ROTATE", bd.Ipl)
+		}
+		if len(bd.Refep) != 2*bd.Ncoe {
+			return fmt.Errorf("body %d: %d coefficients of reference ellipse instead of %d", bd.Ipl, len(bd.Refep),
+				2*bd.Ncoe)
+		}
+	}
+	return nil
+}
+
+// encodeEphemerisBody returns the coefficients of the segments of a body as they are stored in the file (rotated to
+// the orbital plane and reduced by the reference ellipse, if required), the normalisation factor rmax and its value
+// as stored in the file.
+func encodeEphemerisBody(bd *EphemerisBody) ([][]float64, float64, int32) {
+	pd := PlanData{Ibdy: bd.Ipl, Iflg: bd.Iflg, Ncoe: bd.Ncoe, Dseg: bd.Dseg, Telem: bd.Telem, Prot: bd.Prot,
+		Qrot: bd.Qrot, Dprot: bd.Dprot, Dqrot: bd.Dqrot, Peri: bd.Peri, Dperi: bd.Dperi, Refep: bd.Refep}
+	segments := make([][]float64, len(bd.Segments))
+	cmax := 0.0
+	for iseg := range bd.Segments {
+		segp := append([]float64{}, bd.Segments[iseg]...)
+		if bd.Iflg&SEI_FLG_ROTATE != 0 {
+			rotForward(&pd, bd.Ipl, bd.Tfstart+float64(iseg)*bd.Dseg, segp)
+		}
+		for _, c := range segp {
+			cmax = math.Max(cmax, math.Abs(c))
+		}
+		segments[iseg] = segp
+	}
+	// rmax is stored in units of 1/1000, for some planetary moons in units of 1/1000000 (see readFileHeader)
+	unit := 1000.0
+	if bd.Ipl >= SE_PLMOON_OFFSET && bd.Ipl < SE_AST_OFFSET && (bd.Ipl%100 == 99 || (bd.Ipl-9000)/100 == SE_MARS) {
+		unit = 1000000.0
+	}
+	lng := int32(math.Max(1, math.Ceil(cmax*unit)))
+	return segments, float64(lng) / unit, lng
+}
+
+// rotForward is the inverse of rotBack: it rotates the Chebyshev coefficients segp of the segment that starts at
+// tseg0 from the mean equator J2000 to the coordinate system of the orbital plane and subtracts the reference orbit
+// (if SEI_FLG_ELLIPSE).
+func rotForward(pdp *PlanData, ipli int, tseg0 float64, segp []float64) {
+	seps2000 := 0.39777715572793088 // sin(eps2000)
+	ceps2000 := 0.91748206215761929 // cos(eps2000)
+	nco := pdp.Ncoe
+	tdiff := (tseg0 + pdp.Dseg/2 - pdp.Telem) / 365250.0
+	uix, uiy, uiz := rotBackAxes(pdp, ipli, tdiff)
+	for i := 0; i < nco; i++ {
+		x := [3]float64{segp[i], segp[nco+i], segp[2*nco+i]}
+		if ipli == SEI_MOON {
+			// rotate from J2000 equator to ecliptic
+			x[1], x[2] = ceps2000*x[1]+seps2000*x[2], -seps2000*x[1]+ceps2000*x[2]
+		}
+		// the axes are orthonormal, the inverse rotation uses the transposed matrix
+		segp[i] = dotProd(x, uix)
+		segp[nco+i] = dotProd(x, uiy)
+		segp[2*nco+i] = dotProd(x, uiz)
+	}
+	if pdp.Iflg&SEI_FLG_ELLIPSE != 0 {
+		refepx := pdp.Refep
+		refepy := refepx[nco:]
+		com, som := refOrbitRotation(pdp, tdiff)
+		for i := 0; i < nco; i++ {
+			segp[i] -= com*refepx[i] - som*refepy[i]
+			segp[nco+i] -= com*refepy[i] + som*refepx[i]
+		}
+	}
+}
+
+// packSegment writes the coefficients of a segment into b, packed as read by getNewSegment: for each coordinate a
+// header with the number of coefficients that are stored in 4, 3, 2 and 1 byte(s), half bytes and quarter bytes,
+// followed by the coefficients. A coefficient c is stored as the integer n = c / (rmax / 2) * 1e9, as 2 * n if n >= 0
+// and as -2 * n - 1 otherwise.
+func packSegment(b *bytes.Buffer, segp []float64, ncoe int, rmax float64, order binary.ByteOrder) error {
+	for icoord := 0; icoord < 3; icoord++ {
+		n := make([]int64, ncoe)
+		class := make([]int, ncoe)
+		for i := range n {
+			n[i] = int64(math.Round(segp[icoord*ncoe+i] / (rmax / 2) * 1e9))
+		}
+		// the classes (0: 4 bytes ... 5: quarter bytes) must not decrease in size from one coefficient to the next
+		for i := ncoe - 1; i >= 0; i-- {
+			class[i] = packClass(n[i])
+			if i < ncoe-1 && class[i+1] < class[i] {
+				class[i] = class[i+1]
+			}
+		}
+		// at most 15 coefficients per class: move the first ones of a class to the next larger size
+		var nsize [6]int
+		for _, c := range class {
+			nsize[c]++
+		}
+		for c := 5; c > 0; c-- {
+			for i := 0; nsize[c] > 15; i++ {
+				if class[i] == c {
+					class[i] = c - 1
+					nsize[c]--
+					nsize[c-1]++
+				}
+			}
+		}
+		if nsize[0] > 15 {
+			return fmt.Errorf("%d coefficients need 4 bytes, at most 15 are possible", nsize[0])
+		}
+		if nsize[4] == 0 && nsize[5] == 0 && nsize[0] < 8 {
+			b.WriteByte(byte(nsize[0]*16 + nsize[1]))
+			b.WriteByte(byte(nsize[2]*16 + nsize[3]))
+		} else {
+			b.WriteByte(128)
+			b.WriteByte(byte(nsize[0]*16 + nsize[1]))
+			b.WriteByte(byte(nsize[2]*16 + nsize[3]))
+			b.WriteByte(byte(nsize[4]*16 + nsize[5]))
+		}
+		var buf [4]byte
+		i := 0
+		for c := 0; c < 4; c++ {
+			for ; i < ncoe && class[i] == c; i++ {
+				putUint(buf[:], packedValue(n[i]), 4-c, order)
+				b.Write(buf[:4-c])
+			}
+		}
+		// half bytes and quarter bytes, the first coefficient in the highest bits
+		for c, nbits := 4, 4; c < 6; c, nbits = c+1, nbits/2 {
+			var v, used int
+			for ; i < ncoe && class[i] == c; i++ {
+				used += nbits
+				v |= int(packedValue(n[i])) << (8 - used)
+				if used == 8 {
+					b.WriteByte(byte(v))
+					v, used = 0, 0
+				}
+			}
+			if used > 0 {
+				b.WriteByte(byte(v))
+			}
+		}
+	}
+	return nil
+}
+
+// packClass returns the smallest class of packing for the integer n: 0 to 3 for 4 to 1 byte(s), 4 for half bytes and
+// 5 for quarter bytes.
+func packClass(n int64) int {
+	switch {
+	case n >= -2 && n <= 1:
+		return 5
+	case n >= -8 && n <= 7:
+		return 4
+	case n >= -128 && n <= 127:
+		return 3
+	case n >= -32768 && n <= 32767:
+		return 2
+	case n >= -(1<<23) && n < 1<<23:
+		return 1
+	}
+	return 0
+}
+
+// packedValue returns the value that is stored for the integer n: 2 * n if n >= 0, -2 * n - 1 otherwise.
+func packedValue(n int64) uint32 {
+	if n < 0 {
+		return uint32(-2*n - 1)
+	}
+	return uint32(2 * n)
+}
+
+// putUint writes the lowest size bytes of v into buf, in the given byte order.
+func putUint(buf []byte, v uint32, size int, order binary.ByteOrder) {
+	for j := 0; j < size; j++ {
+		shift := 8 * j
+		if order == binary.BigEndian {
+			shift = 8 * (size - 1 - j)
+		}
+		buf[j] = byte(v >> shift)
+	}
+}
+
+// FitChebyshev returns the Chebyshev coefficients of nseg segments of dseg days, starting at tfstart, for the position
+// function fn: for each segment ncoe coefficients for x, y and z, as required by EphemerisBody. The series
+// interpolates fn at the ncoe Chebyshev nodes of each segment.
+func FitChebyshev(fn func(tjd float64) [3]float64, tfstart, dseg float64, nseg, ncoe int) [][]float64 {
+	segments := make([][]float64, nseg)
+	f := make([][3]float64, ncoe)
+	for iseg := range segments {
+		tseg0 := tfstart + float64(iseg)*dseg
+		for k := 0; k < ncoe; k++ {
+			x := math.Cos(math.Pi * (float64(k) + 0.5) / float64(ncoe))
+			f[k] = fn(tseg0 + (x+1)/2*dseg)
+		}
+		segp := make([]float64, 3*ncoe)
+		for icoord := 0; icoord < 3; icoord++ {
+			for j := 0; j < ncoe; j++ {
+				sum := 0.0
+				for k := 0; k < ncoe; k++ {
+					sum += f[k][icoord] * math.Cos(math.Pi*float64(j)*(float64(k)+0.5)/float64(ncoe))
+				}
+				segp[icoord*ncoe+j] = 2 * sum / float64(ncoe)
+			}
+		}
+		segments[iseg] = segp
+	}
+	return segments
+}
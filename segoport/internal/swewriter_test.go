@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// inclinedOrbit returns a position function for a circular orbit with radius r (AU), period (days) and inclination
+// incl (radians) to the equator J2000.
+func inclinedOrbit(r, period, incl float64) func(tjd float64) [3]float64 {
+	return func(tjd float64) [3]float64 {
+		l := TWOPI * (tjd - J2000) / period
+		return [3]float64{r * math.Cos(l), r * math.Sin(l) * math.Cos(incl), r * math.Sin(l) * math.Sin(incl)}
+	}
+}
+
+// packingSegment returns Chebyshev coefficients that decrease from 1 to 1e-10 with alternating signs, so that all
+// sizes of packing are needed.
+func packingSegment(ncoe int) []float64 {
+	segp := make([]float64, 3*ncoe)
+	for icoord := 0; icoord < 3; icoord++ {
+		for i := 0; i < ncoe; i++ {
+			segp[icoord*ncoe+i] = math.Pow(-1, float64(i+icoord)) * math.Pow(10, -float64(i))
+		}
+	}
+	return segp
+}
+
+// readSegments reads the file fnam as file ifno and returns the coefficients of all segments of body ipli, as
+// returned by getNewSegment and rotBack.
+func readSegments(t *testing.T, fnam string, ifno, ipli int) [][]float64 {
+	fp, err := os.Open(fnam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fdp := &swed.Fidat[ifno]
+	*fdp = FileData{Fnam: fnam, Fptr: fp}
+	defer func() {
+		if fdp.Fptr != nil {
+			fdp.Fptr.Close()
+		}
+		*fdp = FileData{}
+		freePlanets()
+	}()
+	if err = readConst(ifno); err != nil {
+		t.Fatalf("readConst: %v", err)
+	}
+	pdp := &swed.Pldat[ipli]
+	var segments [][]float64
+	for tjd := pdp.Tfstart + pdp.Dseg/2; tjd < pdp.Tfend; tjd += pdp.Dseg {
+		if retc, serr := getNewSegment(tjd, ipli, ifno); retc != OK {
+			t.Fatalf("getNewSegment: %s", serr)
+		}
+		if pdp.Iflg&SEI_FLG_ROTATE != 0 {
+			rotBack(ipli)
+		}
+		segments = append(segments, append([]float64{}, pdp.Segp...))
+	}
+	return segments
+}
+
+func TestWriteEphemerisFile(t *testing.T) {
+	tfstart := 2451536.5
+	mercury := inclinedOrbit(0.387, 87.969, 0.12)
+	moon := inclinedOrbit(0.00257, 27.3217, 0.4)
+	tests := []struct {
+		name string
+		ifno int
+		fn   func(tjd float64) [3]float64 // position function, nil for packingSegment
+		body EphemerisBody
+	}{
+		{"sepl_18.se1", SEI_FILE_PLANET, mercury, EphemerisBody{Ipl: SEI_MERCURY,
+			Iflg: SEI_FLG_HELIO | SEI_FLG_ROTATE | SEI_FLG_ELLIPSE, Ncoe: 14, Dseg: 8, Telem: J2000, Prot: 0.03,
+			Qrot: 0.06, Dprot: 0.001, Dqrot: -0.002, Peri: 1.35, Dperi: 0.1,
+			Refep: []float64{0.1, 0.2, -0.05, 0.01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+				0.3, -0.1, 0.02, -0.01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}}},
+		{"sepl_18.se1", SEI_FILE_PLANET, mercury, EphemerisBody{Ipl: SEI_VENUS, Iflg: SEI_FLG_HELIO, Ncoe: 14, Dseg: 8}},
+		{"semo_18.se1", SEI_FILE_MOON, moon, EphemerisBody{Ipl: SEI_MOON, Iflg: SEI_FLG_ROTATE, Ncoe: 12, Dseg: 4,
+			Telem: J2000, Prot: 2.18, Qrot: 0.045, Dprot: -33.8, Dqrot: 0.001}},
+		{"sepl_18.se1", SEI_FILE_PLANET, nil, EphemerisBody{Ipl: SEI_MARS, Ncoe: 11, Dseg: 16}},
+		{"sepl_18.se1", SEI_FILE_PLANET, nil, EphemerisBody{Ipl: SEI_JUPITER, Ncoe: 40, Dseg: 32}},
+	}
+	for _, tt := range tests {
+		bd := tt.body
+		bd.Tfstart = tfstart
+		if tt.fn != nil {
+			bd.Segments = FitChebyshev(tt.fn, bd.Tfstart, bd.Dseg, 5, bd.Ncoe)
+		} else {
+			bd.Segments = [][]float64{packingSegment(bd.Ncoe), packingSegment(bd.Ncoe)}
+			bd.Segments[1][0] = 0
+		}
+		fnam := filepath.Join(t.TempDir(), tt.name)
+		ef := EphemerisFile{Fversion: 2, Copyright: "test", SwephDenum: 431, Bodies: []EphemerisBody{bd}}
+		if err := WriteEphemerisFile(fnam, &ef); err != nil {
+			t.Fatalf("WriteEphemerisFile for body %d: %v", bd.Ipl, err)
+		}
+		segments := readSegments(t, fnam, tt.ifno, bd.Ipl)
+		if len(segments) != len(bd.Segments) {
+			t.Fatalf("body %d: %d segments; want %d", bd.Ipl, len(segments), len(bd.Segments))
+		}
+		// the coefficients are stored as multiples of rmax / 2e9 (rmax <= 2 here)
+		for iseg, segp := range segments {
+			for i, c := range segp {
+				if want := bd.Segments[iseg][i]; math.Abs(c-want) > 2e-9 {
+					t.Errorf("body %d, segment %d, coefficient %d = %.12f; want %.12f", bd.Ipl, iseg, i, c, want)
+				}
+			}
+		}
+		if tt.fn == nil {
+			continue
+		}
+		// positions from the file and from the position function
+		for iseg, segp := range segments {
+			for x := -1.0; x <= 1; x += 0.25 {
+				want := tt.fn(bd.Tfstart + float64(iseg)*bd.Dseg + (x+1)/2*bd.Dseg)
+				for icoord := 0; icoord < 3; icoord++ {
+					v := swiEcheb(x, segp[icoord*bd.Ncoe:], bd.Ncoe)
+					if math.Abs(v-want[icoord]) > 1e-8 {
+						t.Errorf("body %d, segment %d, x = %f: coordinate %d = %.12f; want %.12f", bd.Ipl, iseg, x,
+							icoord, v, want[icoord])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestWriteEphemerisFileAsteroid(t *testing.T) {
+	fnam := filepath.Join(t.TempDir(), "se00433.se1")
+	bd := EphemerisBody{Ipl: SE_AST_OFFSET + 433, Iflg: SEI_FLG_HELIO, Ncoe: 10, Tfstart: 2451536.5, Dseg: 32}
+	bd.Segments = FitChebyshev(inclinedOrbit(1.458, 643.2, 0.2), bd.Tfstart, bd.Dseg, 3, bd.Ncoe)
+	ef := EphemerisFile{Fversion: 2, Copyright: "test", SwephDenum: 431, Astnam: "Eros", Bodies: []EphemerisBody{bd}}
+	if err := WriteEphemerisFile(fnam, &ef); err != nil {
+		t.Fatalf("WriteEphemerisFile: %v", err)
+	}
+	h, err := ReadFileHeader(fnam)
+	if err != nil {
+		t.Fatalf("ReadFileHeader: %v", err)
+	}
+	f := h.File
+	if f.Astnam != "Eros" || f.Npl != 1 || int(f.Ipl[0]) != bd.Ipl || f.Tfstart != bd.Tfstart || f.Tfend != bd.Tfstart+96 ||
+		f.SwephDenum != 431 || h.Planets[0].Ncoe != 10 {
+		t.Errorf("ReadFileHeader: file data %+v, planet data %+v", f, h.Planets[0])
+	}
+	// 4 byte coefficients are limited to 15 per coordinate
+	bd.Ncoe = 16
+	bd.Segments = [][]float64{make([]float64, 48)}
+	for i := range bd.Segments[0] {
+		bd.Segments[0][i] = 1
+	}
+	ef.Bodies = []EphemerisBody{bd}
+	if err = WriteEphemerisFile(fnam, &ef); err == nil {
+		t.Errorf("WriteEphemerisFile with 16 large coefficients: no error")
+	}
+}
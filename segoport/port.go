@@ -3,7 +3,13 @@ package segoport
 import "github.com/jankampherbeek/segoport/internal"
 
 // Port gives access to all the public functions of segoport module.
-type Port struct{}
+// The zero value is ready for use. Only the elements of the Uranian planets (see SetUranianElements) are a setting
+// of each Port. All other settings, e.g. the ephemeris path, the JPL file, SPK kernels, the bodies of
+// RegisterElements and LoadMpcFile, the ephemeris chain and the interpolation of nutation, are process-wide: they
+// are shared by all Port values.
+type Port struct {
+	fictEl int32 // built-in elements of the Uranian planets, 0 for SE_FICTEL_NEELY
+}
 
 // Version returns the current version of segoport.
 func (p *Port) Version() string {
@@ -16,3 +22,485 @@ func (p *Port) Version() string {
 func (p *Port) UseSweJulDay(year, month, day int, hour float64, gregflag int) float64 {
 	return internal.SweJulday(year, month, day, hour, gregflag)
 }
+
+// UseSweSidTime returns the apparent sidereal time at Greenwich in hours.
+// Input: Julian Day Number for UT.
+// Output: sidereal time in hours, using the default model for sidereal time (long term).
+func (p *Port) UseSweSidTime(tjdUt float64) float64 {
+	return internal.SweSidtime(tjdUt)
+}
+
+// UseSweSidTime0 returns the sidereal time at Greenwich in hours for a given obliquity and nutation.
+// Input: Julian Day Number for UT, true obliquity of the ecliptic in degrees and nutation in longitude in degrees.
+// Use nut = 0 to get mean sidereal time.
+// Output: sidereal time in hours.
+func (p *Port) UseSweSidTime0(tjdUt, eps, nut float64) float64 {
+	return internal.SweSidtime0(tjdUt, eps, nut)
+}
+
+// SiderealTime returns the mean or apparent sidereal time in hours for a given geographic longitude.
+// Input: Julian Day Number for UT, geographic longitude in degrees (east is positive, 0 for Greenwich), apparent
+// (true: include the equation of the equinoxes) and the model for sidereal time: SEMOD_SIDT_IAU_1976,
+// SEMOD_SIDT_IAU_2006, SEMOD_SIDT_IERS_CONV_2010, SEMOD_SIDT_LONGTERM or 0 for the default.
+// Output: sidereal time in hours.
+func (p *Port) SiderealTime(tjdUt, geolon float64, apparent bool, sidtModel int) float64 {
+	return internal.SweSiderealTime(tjdUt, geolon, apparent, int32(sidtModel))
+}
+
+// Matrix3 is a 3x3 rotation matrix, stored row by row. Use Apply, ApplySpeed or ApplyPolar to rotate a vector and
+// Mul or Transpose to combine or invert rotations.
+type Matrix3 = internal.Matrix3
+
+// PrecessionMatrix returns the matrix that precesses cartesian equatorial coordinates from the mean equator and
+// equinox J2000 to the mean equator and equinox of date.
+// Input: Julian Day Number for TT and the precession model (SEMOD_PREC_*, 0 for the default).
+func (p *Port) PrecessionMatrix(tjdTt float64, precModel int) Matrix3 {
+	return internal.PrecessionMatrix(tjdTt, int32(precModel))
+}
+
+// NutationMatrix returns the matrix that rotates cartesian equatorial coordinates from the mean equator and equinox
+// of date to the true equator and equinox of date.
+// Input: Julian Day Number for TT, the precession model used for the mean obliquity (SEMOD_PREC_*) and the nutation
+// model (SEMOD_NUT_*). Use 0 for the default models.
+func (p *Port) NutationMatrix(tjdTt float64, precModel, nutModel int) Matrix3 {
+	return internal.NutationMatrix(tjdTt, int32(precModel), int32(nutModel))
+}
+
+// BiasMatrix returns the frame bias matrix that rotates ICRS coordinates to the mean equator and equinox J2000.
+// Input: the bias model (SEMOD_BIAS_*, 0 for the default).
+func (p *Port) BiasMatrix(biasModel int) Matrix3 {
+	return internal.BiasMatrix(int32(biasModel))
+}
+
+// NpbMatrix returns the combined nutation, precession and bias matrix that rotates ICRS coordinates to the true
+// equator and equinox of date.
+// Input: Julian Day Number for TT and the models for precession, nutation and frame bias (0 for the defaults).
+func (p *Port) NpbMatrix(tjdTt float64, precModel, nutModel, biasModel int) Matrix3 {
+	return internal.NpbMatrix(tjdTt, int32(precModel), int32(nutModel), int32(biasModel))
+}
+
+// SetInterpolateNut switches the interpolation of nutation on or off. Interpolation speeds up dense time series
+// (e.g. minute by minute) considerably, at the cost of a maximum error of about 3 milli-arcseconds.
+// The setting is process-wide, it applies to all Port values.
+func (p *Port) SetInterpolateNut(doInterpolate bool) {
+	internal.SweSetInterpolateNut(doInterpolate)
+}
+
+// Nutation returns the nutation in longitude and in obliquity in degrees.
+// Input: Julian Day Number for TT and flags. With SEFLG_JPLHOR the celestial pole offsets from the IERS files
+// eop_1962_today.txt and eop_finals.txt (in the ephemeris path) are applied, to reproduce JPL Horizons.
+// Output: nutation in longitude and obliquity, the flags that were actually used and an error if SEFLG_JPLHOR could
+// not be used and SEFLG_JPLHOR_APPROX was used instead. The results are valid, also if an error is returned.
+func (p *Port) Nutation(tjdTt float64, iflag int) (float64, float64, int, error) {
+	dpsi, deps, iflagRet, err := internal.SweNutationEx(tjdTt, int32(iflag))
+	return dpsi, deps, int(iflagRet), err
+}
+
+// Cotrans transforms polar coordinates between ecliptic and equator.
+// Input: longitude (or right ascension) and latitude (or declination) in degrees and distance, and the obliquity in
+// degrees: negative for ecliptic to equator, positive for equator to ecliptic.
+// Output: the transformed coordinates, the distance is unchanged.
+func (p *Port) Cotrans(xpo [3]float64, eps float64) [3]float64 {
+	return internal.SweCotrans(xpo, eps)
+}
+
+// CotransSp transforms polar coordinates and their daily speeds between ecliptic and equator, see Cotrans.
+func (p *Port) CotransSp(xpo [6]float64, eps float64) [6]float64 {
+	return internal.SweCotransSp(xpo, eps)
+}
+
+// EquatorialToGalactic transforms equatorial coordinates ICRS (J2000) to galactic coordinates (IAU 1958 system).
+// Input and output: longitude, latitude, distance and their daily speeds, angles in degrees.
+func (p *Port) EquatorialToGalactic(xpo [6]float64) [6]float64 {
+	return internal.SweEquToGal(xpo, false)
+}
+
+// GalacticToEquatorial transforms galactic coordinates to equatorial coordinates ICRS (J2000), see
+// EquatorialToGalactic.
+func (p *Port) GalacticToEquatorial(xpo [6]float64) [6]float64 {
+	return internal.SweEquToGal(xpo, true)
+}
+
+// GalacticToSupergalactic transforms galactic coordinates to supergalactic coordinates.
+// Input and output: longitude, latitude, distance and their daily speeds, angles in degrees.
+func (p *Port) GalacticToSupergalactic(xpo [6]float64) [6]float64 {
+	return internal.SweGalToSgal(xpo, false)
+}
+
+// SupergalacticToGalactic transforms supergalactic coordinates to galactic coordinates, see GalacticToSupergalactic.
+func (p *Port) SupergalacticToGalactic(xpo [6]float64) [6]float64 {
+	return internal.SweGalToSgal(xpo, true)
+}
+
+// EquatorialToHorizontal calculates azimuth and true altitude.
+// Input: right ascension and declination of date in degrees, local sidereal time in hours and geographic latitude
+// in degrees.
+// Output: azimuth (from the south point, clockwise via west) and altitude, in degrees.
+func (p *Port) EquatorialToHorizontal(ra, decl, lst, geolat float64) (float64, float64) {
+	xaz := internal.SweEquToHor([2]float64{ra, decl}, lst*15, geolat)
+	return xaz[0], xaz[1]
+}
+
+// HorizontalToEquatorial calculates right ascension and declination of date, see EquatorialToHorizontal.
+func (p *Port) HorizontalToEquatorial(azimuth, altitude, lst, geolat float64) (float64, float64) {
+	x := internal.SweHorToEqu([2]float64{azimuth, altitude}, lst*15, geolat)
+	return x[0], x[1]
+}
+
+// CatalogPosition is a star position as given in a catalogue: right ascension and declination in degrees, proper
+// motions in arcsec per year (PmRa as dRA/dt, not multiplied by cos(Dec)), parallax in arcsec and radial velocity in
+// km/s.
+type CatalogPosition = internal.CatalogPosition
+
+// Fk4ToFk5 converts a catalogue position in the FK4 system for equinox and epoch B1950 to the FK5 system for equinox
+// and epoch J2000, removing the E-terms of aberration. Proper motions in FK4 are per tropical year, in FK5 per Julian
+// year.
+func (p *Port) Fk4ToFk5(pos CatalogPosition) CatalogPosition {
+	return internal.SweFk4ToFk5(pos)
+}
+
+// Fk5ToFk4 converts a catalogue position in the FK5 system for equinox and epoch J2000 to the FK4 system for equinox
+// and epoch B1950, see Fk4ToFk5.
+func (p *Port) Fk5ToFk4(pos CatalogPosition) CatalogPosition {
+	return internal.SweFk5ToFk4(pos)
+}
+
+// PrecessionMatrixEpochs returns the matrix that precesses cartesian equatorial coordinates from the mean equator and
+// equinox of one epoch to another.
+// Input: Julian Day Numbers (TT) of both epochs and the precession model (SEMOD_PREC_*, 0 for the default). Use
+// SEMOD_PREC_NEWCOMB for FK4 (B1950) and older catalogues.
+func (p *Port) PrecessionMatrixEpochs(tjdFrom, tjdTo float64, precModel int) Matrix3 {
+	return internal.PrecessionMatrixEpochs(tjdFrom, tjdTo, int32(precModel))
+}
+
+// PrecessEpochs precesses equatorial coordinates from the mean equator and equinox of one epoch to another.
+// Input: right ascension, declination (degrees), distance and their speeds, Julian Day Numbers (TT) of both epochs
+// and the precession model (SEMOD_PREC_*, 0 for the default).
+// Output: the precessed coordinates and speeds. Proper motion is not applied.
+func (p *Port) PrecessEpochs(xpo [6]float64, tjdFrom, tjdTo float64, precModel int) [6]float64 {
+	return internal.SwePrecessEpochs(xpo, tjdFrom, tjdTo, int32(precModel))
+}
+
+// FileHeader contains the header of a Swiss Ephemeris file: the file data, the general constants and the constants of
+// each planet in the file.
+type FileHeader struct {
+	File    FileInfo
+	Gcdat   GenConst
+	Planets []PlanetInfo // in the order of the file
+}
+
+// FileInfo contains the data of a Swiss Ephemeris file, see FileHeader: name, version, name of the asteroid (only for
+// asteroid files), DE number, time range (Julian days TT), byte order flags (SEI_FILE_LITENDIAN, SEI_FILE_REORD) and
+// number of planets.
+type FileInfo struct {
+	Fnam       string
+	Fversion   int
+	Astnam     string
+	SwephDenum int32
+	Tfstart    float64
+	Tfend      float64
+	Iflg       int32
+	Npl        int
+}
+
+// PlanetInfo contains the constants of a planet in a Swiss Ephemeris file, see FileHeader: internal body number (SEI_*,
+// or SE_AST_OFFSET + MPC number), flags (SEI_FLG_*), number of coefficients, time range (Julian days TT), segment size
+// in days and normalisation factor of the coefficients.
+type PlanetInfo struct {
+	Ibdy    int
+	Iflg    int32
+	Ncoe    int
+	Tfstart float64
+	Tfend   float64
+	Dseg    float64
+	Rmax    float64
+}
+
+// CorruptFileError is the error for a damaged ephemeris file, e.g. a truncated file or a file with a wrong checksum.
+// Use errors.As to check for it.
+type CorruptFileError = internal.CorruptFileError
+
+// ReadFileHeader reads the header of a Swiss Ephemeris file (.se1).
+// Input: the file name including the path. The name of the file must not have been changed, it is checked against
+// the name in the header.
+// Output: the header or an error if the file could not be read. If the file is damaged, e.g. truncated or with a
+// wrong checksum, the error is a *CorruptFileError.
+func (p *Port) ReadFileHeader(fnam string) (*FileHeader, error) {
+	ih, err := internal.ReadFileHeader(fnam)
+	if err != nil {
+		return nil, err
+	}
+	f := ih.File
+	h := &FileHeader{Gcdat: ih.Gcdat, File: FileInfo{Fnam: f.Fnam, Fversion: f.Fversion, Astnam: f.Astnam,
+		SwephDenum: f.SwephDenum, Tfstart: f.Tfstart, Tfend: f.Tfend, Iflg: f.Iflg, Npl: int(f.Npl)}}
+	for _, pd := range ih.Planets {
+		h.Planets = append(h.Planets, PlanetInfo{Ibdy: pd.Ibdy, Iflg: pd.Iflg, Ncoe: pd.Ncoe, Tfstart: pd.Tfstart,
+			Tfend: pd.Tfend, Dseg: pd.Dseg, Rmax: pd.Rmax})
+	}
+	return h, nil
+}
+
+// UseSweRevJul returns the calendar date for a Julian Day Number.
+// Input: Julian Day Number and gregflag to indicate the calender: 1 = Gregorian, 0 = Julian.
+// Output: year, month, day and hour as number with fraction.
+func (p *Port) UseSweRevJul(jd float64, gregflag int) (int, int, int, float64) {
+	return internal.SweRevJul(jd, gregflag)
+}
+
+// GenConst contains the general constants of a Swiss Ephemeris file, see FileHeader and EphemerisFile.
+type GenConst = internal.GenConst
+
+// EphemerisFile contains the data for WriteEphemerisFile: version, copyright, DE number, byte order, general
+// constants and the bodies. For files with a single asteroid or planetary moon also the name and the orbital elements.
+type EphemerisFile = internal.EphemerisFile
+
+// EphemerisBody contains the data of a body for WriteEphemerisFile: body number, flags, number of coefficients, time
+// range, orbital elements for the rotation and the reference ellipse and the Chebyshev coefficients of the segments.
+type EphemerisBody = internal.EphemerisBody
+
+// WriteEphemerisFile writes a Swiss Ephemeris file (.se1).
+// Input: the file name including the path and the data of the file. The name of the file determines the type of
+// file: sepl*, semo*, seas* or a file with a single asteroid or planetary moon.
+// Output: an error if the data are inconsistent or the file could not be written.
+func (p *Port) WriteEphemerisFile(fnam string, ef *EphemerisFile) error {
+	return internal.WriteEphemerisFile(fnam, ef)
+}
+
+// FitChebyshev returns Chebyshev coefficients for segments of an ephemeris.
+// Input: position function (x, y, z for the mean equator J2000 in AU), start of the first segment (Julian day TT),
+// segment size in days, number of segments and number of coefficients per coordinate.
+// Output: the coefficients of each segment, as required for EphemerisBody.
+func (p *Port) FitChebyshev(fn func(tjd float64) [3]float64, tfstart, dseg float64, nseg, ncoe int) [][]float64 {
+	return internal.FitChebyshev(fn, tfstart, dseg, nseg, ncoe)
+}
+
+// SetEphePath sets the path for the ephemeris files.
+// Input: one or more directories, separated by a colon or semicolon. An empty string sets the default path. The
+// environment variable SE_EPHE_PATH has priority.
+// The path is process-wide, it applies to all Port values.
+func (p *Port) SetEphePath(path string) {
+	internal.SweSetEphePath(path)
+}
+
+// SetJplFile sets the name of the JPL ephemeris file (default de431.eph) that is used with SEFLG_JPLEPH.
+// Input: the file name; a directory is ignored, the file is searched in the ephemeris path (see SetEphePath).
+// Output: an error if the file cannot be opened or has an invalid format.
+// As the ephemeris path, the file is process-wide.
+func (p *Port) SetJplFile(fname string) error {
+	return internal.SweSetJplFile(fname)
+}
+
+// ConvertJplAscii converts the ASCII files of a JPL ephemeris into a binary file for SetJplFile.
+// Input: name of the binary file, the header file (header.4xx), the data files (ascpNNNN.4xx) in chronological order
+// and optionally the range in Julian days (TT) that is converted; 0 for the start and the end of the data.
+// Output: the start and end of the binary file in Julian days and an error.
+func (p *Port) ConvertJplAscii(fnam, header string, dataFiles []string, tjdStart, tjdEnd float64) (float64,
+	float64, error) {
+	return internal.ConvertJplAscii(fnam, header, dataFiles, tjdStart, tjdEnd)
+}
+
+// LoadSpkFile loads a NAIF SPICE SPK kernel (.bsp) that is used with SEFLG_SPKEPH, e.g. de440s.bsp or an asteroid
+// kernel of JPL Horizons. Kernels that are loaded later take precedence.
+// Input: the file name; without a directory, the file is searched in the ephemeris path (see SetEphePath).
+// Output: an error if the file cannot be opened or is not an SPK file.
+// The kernels are loaded for the whole process and are used by all Port values.
+func (p *Port) LoadSpkFile(fname string) error {
+	return internal.SweLoadSpkFile(fname)
+}
+
+// AsteroidFile describes an asteroid file in the ephemeris path: MPC number, name, file name and time range.
+type AsteroidFile = internal.AsteroidFile
+
+// ListAsteroids returns the asteroid files in the ephemeris path, sorted by MPC number.
+// Output: the asteroid files and an error if some files could not be read; the other files are still listed.
+func (p *Port) ListAsteroids() ([]AsteroidFile, error) {
+	return internal.SweListAsteroids()
+}
+
+// FindAsteroid returns the asteroid file for an asteroid name.
+// Input: the name of the asteroid, the case is ignored.
+// Output: the asteroid file and false if there is no file for the asteroid in the ephemeris path.
+func (p *Port) FindAsteroid(name string) (AsteroidFile, bool) {
+	return internal.SweFindAsteroid(name)
+}
+
+// FictName returns the name of a fictitious body.
+// Input: the number of the body, SE_FICT_OFFSET + the number of the body in seorbel.txt or the built-in elements.
+// Output: the name, e.g. Cupido for 40, or "name not found".
+func (p *Port) FictName(ipl int) string {
+	return internal.SweGetFictName(ipl)
+}
+
+// SetUranianElements chooses the built-in elements of the Uranian planets Cupido .. Poseidon, which are used if there
+// is no seorbel.txt in the ephemeris path.
+// Input: SE_FICTEL_NEELY for the revised elements of James Neely (the default, as in the C version) or
+// SE_FICTEL_CLASSIC for the classic elements.
+// Output: an error for another value, the current elements are then kept. Calc reports the elements in the
+// provenance (FictEl).
+// The elements are a setting of this Port, other Port values keep their own elements.
+func (p *Port) SetUranianElements(set int) error {
+	if err := internal.SweCheckFictElements(int32(set)); err != nil {
+		return err
+	}
+	p.fictEl = int32(set)
+	return nil
+}
+
+// UranianElements returns the built-in elements of the Uranian planets, see SetUranianElements.
+func (p *Port) UranianElements() int {
+	if p.fictEl == 0 {
+		return SE_FICTEL_NEELY
+	}
+	return int(p.fictEl)
+}
+
+// RegisterElements defines a hypothetical body by its osculating elements; its positions are calculated with Calc, as
+// for the fictitious bodies of seorbel.txt.
+// Input: name, epoch and equinox as Julian Day Numbers (equinox 0 for the equinox of date), mean anomaly, semi-axis
+// in AU, eccentricity, argument of perihelion, ascending node and inclination, the angles in degrees.
+// Output: the body number ipl for Calc and CalcUt, SE_FICT_REG + n for the n-th body (from 0), and an error if the
+// elements are invalid or all numbers up to SE_FICT_MAX are used.
+// The bodies are process-wide, every Port can calculate them.
+func (p *Port) RegisterElements(name string, epoch, equinox, meanAnomaly, a, e, peri, node, incl float64) (int,
+	error) {
+	return internal.SweRegisterElements(name, epoch, equinox, meanAnomaly, a, e, peri, node, incl)
+}
+
+// ClearElements removes the bodies of RegisterElements for all Port values; new bodies get their numbers again.
+func (p *Port) ClearElements() {
+	internal.SweClearElements()
+}
+
+// MpcObject contains the elements of a comet or asteroid of LoadMpcFile, heliocentric for the ecliptic J2000.
+type MpcObject = internal.MpcObject
+
+// LoadMpcFile loads comets and asteroids from a file of the Minor Planet Center in the format of CometEls.txt or
+// MPCORB.DAT (or an extract); their positions are calculated with Calc by two-body propagation of the elements.
+// Input: the name of the file, it is searched in the ephemeris path if it has no directory.
+// Output: the number of objects in the file and an error for a line with invalid elements. The objects get their
+// body numbers from FindMpcObject.
+// As the bodies of RegisterElements, the objects are process-wide.
+func (p *Port) LoadMpcFile(fname string) (int, error) {
+	return internal.SweLoadMpcFile(fname)
+}
+
+// MpcObjects returns the comets and asteroids of LoadMpcFile; Ipl is 0 for objects without a number of FindMpcObject.
+func (p *Port) MpcObjects() []MpcObject {
+	return internal.SweListMpcObjects()
+}
+
+// FindMpcObject finds a comet or asteroid of LoadMpcFile and gives it a body number for Calc.
+// Input: the name, the designation or both, e.g. "Hale-Bopp", "C/1995 O1", "1P/Halley" or "Ceres"; case is ignored.
+// Output: the object, with the body number SE_COMET_OFFSET + n for Calc in Ipl, and an error if there is no such
+// object or 8000 objects have a number already.
+func (p *Port) FindMpcObject(name string) (MpcObject, error) {
+	return internal.SweFindMpcObject(name)
+}
+
+// ClearMpcObjects removes the objects of LoadMpcFile for all Port values; their body numbers are given again by
+// FindMpcObject.
+func (p *Port) ClearMpcObjects() {
+	internal.SweClearMpcObjects()
+}
+
+// NBodyOrbit contains an orbit of IntegrateOrbit: name, time range, ephemeris and DE number of the planets and the
+// number of steps. Position returns the heliocentric position and speed for the equator J2000.
+type NBodyOrbit = internal.NBodyOrbit
+
+// IntegrateOrbit integrates the orbit of a comet or asteroid of LoadMpcFile with the perturbations of the planets and
+// the moon, for positions more accurate than the two-body orbits of Calc.
+// Input: the object, start and end of the orbit (Julian Day Numbers for TT) and the ephemeris of the planets
+// (SEFLG_JPLEPH, SEFLG_SWIEPH, SEFLG_MOSEPH or SEFLG_SPKEPH).
+// Output: the orbit and an error if a planet could not be calculated.
+func (p *Port) IntegrateOrbit(obj MpcObject, tjdStart, tjdEnd float64, iflag int) (*NBodyOrbit, error) {
+	return internal.SweIntegrateOrbit(obj, tjdStart, tjdEnd, int32(iflag))
+}
+
+// WriteOrbitFile writes an orbit of IntegrateOrbit as an asteroid file, so that Calc computes the positions of
+// SE_AST_OFFSET + nr from the file, with all corrections of the apparent place.
+// Input: the orbit, the file name including the path (e.g. ast0/se00433.se1 in the ephemeris path), the number of
+// the asteroid, the segment size in days and the number of Chebyshev coefficients per segment (e.g. 32 and 13).
+// Output: an error if the orbit is shorter than a segment or the file could not be written.
+func (p *Port) WriteOrbitFile(o *NBodyOrbit, fnam string, nr int, dseg float64, ncoe int) error {
+	return internal.SweWriteOrbitFile(o, fnam, nr, dseg, ncoe)
+}
+
+// Provenance describes the source of a calculated position: the ephemeris that was actually used (Iephe), the file
+// that contains the body (Fnam), the JPL DE number (Denum), whether another ephemeris than the requested one was used
+// (Fallback) and the built-in elements of the Uranian planets (FictEl).
+type Provenance = internal.Provenance
+
+// Calc calculates the position of a body.
+// Input: Julian Day Number for TT, body number and flags. The ephemeris is chosen with SEFLG_SPKEPH, SEFLG_JPLEPH,
+// SEFLG_SWIEPH or SEFLG_MOSEPH; if its files are not available, the next ephemeris of the chain is used (see
+// SetEpheChain).
+// Planetary moons have numbers SE_PLMOON_OFFSET + the number of the file, e.g. 9501 for Io. With SEFLG_CENTER_BODY
+// the center of body of a planet is returned instead of the barycenter of the planet and its moons, if the file for
+// the planet is available (e.g. sepm9599.se1 for Jupiter).
+// Fictitious bodies have numbers SE_FICT_OFFSET + the number of the body, e.g. SE_CUPIDO; the elements are read
+// from seorbel.txt in the ephemeris path, without the file the built-in elements are used (see FictName). Bodies from
+// SE_FICT_REG on are defined with RegisterElements. Comets and asteroids of LoadMpcFile have the numbers
+// SE_COMET_OFFSET + n of FindMpcObject.
+// Output: longitude, latitude, distance and their speeds (or the equatorial or cartesian variants, depending on the
+// flags), the flags that were actually used, the source of the position and an error. If the flags are ERR the
+// calculation failed, otherwise the error is a warning (e.g. about a fallback) and the results are valid. If an
+// ephemeris file is damaged, the error is, or wraps, a *CorruptFileError.
+func (p *Port) Calc(tjdTt float64, ipl, iflag int) ([6]float64, int, Provenance, error) {
+	xx, iflagRet, prov, err := internal.SweCalcFictEl(tjdTt, ipl, int32(iflag), int32(p.UranianElements()))
+	return xx, int(iflagRet), prov, err
+}
+
+// CalcUt calculates the position of a body for a Julian Day Number for UT, see Calc.
+func (p *Port) CalcUt(tjdUt float64, ipl, iflag int) ([6]float64, int, Provenance, error) {
+	xx, iflagRet, prov, err := internal.SweCalcUtFictEl(tjdUt, ipl, int32(iflag), int32(p.UranianElements()))
+	return xx, int(iflagRet), prov, err
+}
+
+// Fixstar calculates the position of a fixed star. The stars are read from sefstars.txt in the ephemeris path, or from
+// the older fixstars.cat; some stars that are needed for ayanamsas, e.g. Spica, are built in.
+// Input: the star, Julian Day Number for TT and flags as for Calc; sidereal and topocentric positions are not
+// supported. The star is given by its traditional name (e.g. "Aldebaran", case and spaces are ignored), by its Bayer
+// or Flamsteed designation after a comma (e.g. ",alTau"), by the beginning of the name followed by '%' (e.g.
+// "alde%") or by the sequential number in the list of designations.
+// Output: longitude, latitude, distance and their speeds (or the equatorial or cartesian variants), the flags, the
+// name of the star as "traditional name,designation" and an error. If the flags are ERR the calculation failed,
+// otherwise the error is a warning and the results are valid.
+func (p *Port) Fixstar(star string, tjdTt float64, iflag int) ([6]float64, int, string, error) {
+	xx, iflagRet, name, err := internal.SweFixstar2(star, tjdTt, int32(iflag))
+	return xx, int(iflagRet), name, err
+}
+
+// FixstarUt calculates the position of a fixed star for a Julian Day Number for UT, see Fixstar.
+func (p *Port) FixstarUt(star string, tjdUt float64, iflag int) ([6]float64, int, string, error) {
+	xx, iflagRet, name, err := internal.SweFixstar2Ut(star, tjdUt, int32(iflag))
+	return xx, int(iflagRet), name, err
+}
+
+// FixstarMag returns the visual magnitude of a fixed star and its name as "traditional name,designation". The star
+// is given as for Fixstar; the built-in stars have no magnitude.
+func (p *Port) FixstarMag(star string) (float64, string, error) {
+	return internal.SweFixstar2Mag(star)
+}
+
+// SetEpheChain sets the order in which the ephemerides are tried if the files of an ephemeris are not available.
+// Input: ephemeris flags SEFLG_SPKEPH, SEFLG_JPLEPH, SEFLG_SWIEPH and SEFLG_MOSEPH in the order of the fallback. A
+// calculation starts with the ephemeris that is requested in the flags. An empty chain restores the default order SPK
+// kernels, JPL, Swiss Ephemeris, Moshier; a chain with only SEFLG_SWIEPH is strict and never falls back.
+// Output: an error if the chain contains other flags or an ephemeris twice, the current chain is then kept.
+// The chain is process-wide, it applies to the calculations of all Port values.
+func (p *Port) SetEpheChain(chain []int) error {
+	iChain := make([]int32, len(chain))
+	for i, epheflag := range chain {
+		iChain[i] = int32(epheflag)
+	}
+	return internal.SweSetEpheChain(iChain)
+}
+
+// EpheChain returns the order in which the ephemerides are tried, see SetEpheChain.
+func (p *Port) EpheChain() []int {
+	var chain []int
+	for _, epheflag := range internal.SweGetEpheChain() {
+		chain = append(chain, int(epheflag))
+	}
+	return chain
+}
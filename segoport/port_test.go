@@ -1,6 +1,13 @@
 package segoport
 
-import "testing"
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
 
 func TestPortVersion(t *testing.T) {
 	p := Port{}
@@ -10,3 +17,325 @@ func TestPortVersion(t *testing.T) {
 		t.Errorf("Port.Version should returned a string of %d characters; want 3 characters or more", l)
 	}
 }
+
+func TestSiderealTime(t *testing.T) {
+	p := Port{}
+	// reference values from the C version of the Swiss Ephemeris (swe_sidtime)
+	tests := []struct {
+		model int
+		jd    float64
+		want  float64
+	}{
+		{SEMOD_SIDT_IAU_1976, 2451545.0, 18.697137853163},
+		{SEMOD_SIDT_IAU_1976, 2415020.75, 12.695642788137},
+		{SEMOD_SIDT_IAU_2006, 2460000.25, 4.290210608980},
+		{SEMOD_SIDT_IAU_2006, 2305447.5, 6.656990856141},
+		{SEMOD_SIDT_IERS_CONV_2010, 2451545.0, 18.697138162535},
+		{SEMOD_SIDT_IERS_CONV_2010, 2500000.5, 22.699532059706},
+		{SEMOD_SIDT_LONGTERM, 2460000.25, 4.290210639547},
+		{SEMOD_SIDT_LONGTERM, 2305447.5, 6.657014162183},
+		{SEMOD_SIDT_LONGTERM, 2500000.5, 22.699502626466},
+		{SEMOD_SIDT_LONGTERM, 1721057.5, 6.508683906363},
+	}
+	for _, tt := range tests {
+		result := p.SiderealTime(tt.jd, 0, true, tt.model)
+		if math.Abs(result-tt.want) > 1e-8 {
+			t.Errorf("SiderealTime(%f, model %d) = %.12f; want %.12f", tt.jd, tt.model, result, tt.want)
+		}
+	}
+	if result := p.UseSweSidTime(2451545.0); math.Abs(result-18.697138162535) > 1e-8 {
+		t.Errorf("UseSweSidTime(2451545.0) = %.12f; want 18.697138162535", result)
+	}
+}
+
+func TestLocalMeanSiderealTime(t *testing.T) {
+	p := Port{}
+	jd := 2451545.0
+	gast := p.SiderealTime(jd, 0, true, SEMOD_SIDT_IERS_CONV_2010)
+	gmst := p.SiderealTime(jd, 0, false, SEMOD_SIDT_IERS_CONV_2010)
+	// equation of the equinoxes at J2000 is about -0.85 seconds of time
+	if eqeq := (gast - gmst) * 3600; eqeq > -0.8 || eqeq < -0.9 {
+		t.Errorf("equation of the equinoxes = %f seconds; want about -0.85", eqeq)
+	}
+	lmst := p.SiderealTime(jd, 90.0, false, SEMOD_SIDT_IERS_CONV_2010)
+	want := math.Mod(gmst+6.0, 24.0)
+	if math.Abs(lmst-want) > 1e-10 {
+		t.Errorf("local mean sidereal time = %f; want %f", lmst, want)
+	}
+}
+
+func TestNpbMatrix(t *testing.T) {
+	p := Port{}
+	tjd := 2460000.5
+	// reference values from the C version of the Swiss Ephemeris (swi_bias, swi_precess and swi_nutate)
+	tests := []struct {
+		prec, nut, bias int
+		want            [3]float64
+	}{
+		{0, 0, 0, [3]float64{0.296825426896232, 0.401513766476827, 0.500680897655845}},
+		{SEMOD_PREC_IAU_1976, SEMOD_NUT_IAU_1980, SEMOD_BIAS_IAU2000, [3]float64{0.296825235911383, 0.401513857533456, 0.500680937858389}},
+		{SEMOD_PREC_IAU_2006, SEMOD_NUT_IAU_2000A, SEMOD_BIAS_IAU2006, [3]float64{0.296825426422028, 0.401513765761635, 0.500680898510511}},
+		{SEMOD_PREC_NEWCOMB, SEMOD_NUT_WOOLARD, SEMOD_BIAS_NONE, [3]float64{0.296826288446641, 0.401513322990575, 0.500680742537655}},
+		{SEMOD_PREC_LASKAR_1986, SEMOD_NUT_IAU_2000B, SEMOD_BIAS_IAU2006, [3]float64{0.296825233990497, 0.401513843955682, 0.500680949885672}},
+		{SEMOD_PREC_OWEN_1990, SEMOD_NUT_IAU_2000B, SEMOD_BIAS_IAU2006, [3]float64{0.296825044028229, 0.401513934656740, 0.500680989767040}},
+	}
+	for _, tt := range tests {
+		m := p.NpbMatrix(tjd, tt.prec, tt.nut, tt.bias)
+		result := m.Apply([3]float64{0.3, 0.4, 0.5})
+		for i := 0; i < 3; i++ {
+			if math.Abs(result[i]-tt.want[i]) > 1e-13 {
+				t.Errorf("NpbMatrix(%d, %d, %d) applied: [%d] = %.15f; want %.15f", tt.prec, tt.nut, tt.bias, i,
+					result[i], tt.want[i])
+			}
+		}
+		back := m.Transpose().Apply(result)
+		if math.Abs(back[0]-0.3) > 1e-15 || math.Abs(back[1]-0.4) > 1e-15 || math.Abs(back[2]-0.5) > 1e-15 {
+			t.Errorf("NpbMatrix(%d, %d, %d) inverse = %v; want [0.3 0.4 0.5]", tt.prec, tt.nut, tt.bias, back)
+		}
+	}
+	// the models are passed to the calculation, concurrent calls with other models do not change the result
+	var wg sync.WaitGroup
+	matrices := make([]Matrix3, len(tests))
+	for i, tt := range tests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			matrices[i] = p.NpbMatrix(tjd, tt.prec, tt.nut, tt.bias)
+		}()
+	}
+	wg.Wait()
+	for i, tt := range tests {
+		if m := p.NpbMatrix(tjd, tt.prec, tt.nut, tt.bias); matrices[i] != m {
+			t.Errorf("NpbMatrix(%d, %d, %d) concurrently = %v; want %v", tt.prec, tt.nut, tt.bias, matrices[i], m)
+		}
+	}
+}
+
+func TestCotrans(t *testing.T) {
+	p := Port{}
+	// reference values from the C version of the Swiss Ephemeris (swe_cotrans, swe_cotrans_sp)
+	result := p.Cotrans([3]float64{123.4, -5.6, 1.2}, 23.44)
+	want := [3]float64{127.134706274290, -24.836987795499, 1.2}
+	for i := 0; i < 3; i++ {
+		if math.Abs(result[i]-want[i]) > 1e-10 {
+			t.Errorf("Cotrans()[%d] = %.12f; want %.12f", i, result[i], want[i])
+		}
+	}
+	resultSp := p.CotransSp([6]float64{123.4, -5.6, 1.2, 1.1, -0.05, 0.01}, -23.44)
+	wantSp := [6]float64{124.366789784615, 13.944261572945, 1.2, 1.087281768624, -0.295712531225, 0.01}
+	for i := 0; i < 6; i++ {
+		if math.Abs(resultSp[i]-wantSp[i]) > 1e-10 {
+			t.Errorf("CotransSp()[%d] = %.12f; want %.12f", i, resultSp[i], wantSp[i])
+		}
+	}
+}
+
+func TestGalactic(t *testing.T) {
+	p := Port{}
+	// galactic center and north galactic pole (ICRS), within 0.04"
+	gc := p.EquatorialToGalactic([6]float64{266.40499625, -28.93617242, 1, 0, 0, 0})
+	if math.Abs(math.Mod(gc[0]+180, 360)-180) > 1e-5 || math.Abs(gc[1]) > 1e-5 {
+		t.Errorf("galactic center = %f, %f; want 0, 0", gc[0], gc[1])
+	}
+	ngp := p.EquatorialToGalactic([6]float64{192.85948121, 27.12825118, 1, 0, 0, 0})
+	if math.Abs(ngp[1]-90) > 1e-5 {
+		t.Errorf("galactic latitude of north galactic pole = %f; want 90", ngp[1])
+	}
+	// back and forth with speeds
+	x := [6]float64{10.5, 20.25, 2.0, 0.01, -0.002, 0.0001}
+	back := p.GalacticToEquatorial(p.EquatorialToGalactic(x))
+	for i := 0; i < 6; i++ {
+		if math.Abs(back[i]-x[i]) > 1e-12 {
+			t.Errorf("galactic and back [%d] = %.15f; want %.15f", i, back[i], x[i])
+		}
+	}
+	// zero point of supergalactic longitude and supergalactic pole
+	sg := p.GalacticToSupergalactic([6]float64{137.37, 0, 1, 0, 0, 0})
+	if math.Abs(math.Mod(sg[0]+180, 360)-180) > 1e-10 || math.Abs(sg[1]) > 1e-10 {
+		t.Errorf("supergalactic zero point = %f, %f; want 0, 0", sg[0], sg[1])
+	}
+	sgp := p.SupergalacticToGalactic([6]float64{0, 90, 1, 0, 0, 0})
+	if math.Abs(sgp[0]-47.37) > 1e-8 || math.Abs(sgp[1]-6.32) > 1e-8 {
+		t.Errorf("supergalactic pole = %f, %f; want 47.37, 6.32", sgp[0], sgp[1])
+	}
+}
+
+func TestHorizontal(t *testing.T) {
+	p := Port{}
+	// reference values from the C version of the Swiss Ephemeris (swe_azalt, true altitude)
+	lst := 87.302441107277 / 15
+	az, alt := p.EquatorialToHorizontal(83.63, 22.01, lst, 52.37)
+	if math.Abs(az-6.720931590302) > 1e-9 || math.Abs(alt-59.508489657552) > 1e-9 {
+		t.Errorf("EquatorialToHorizontal = %.12f, %.12f; want 6.720931590302, 59.508489657552", az, alt)
+	}
+	ra, decl := p.HorizontalToEquatorial(az, alt, lst, 52.37)
+	if math.Abs(ra-83.63) > 1e-9 || math.Abs(decl-22.01) > 1e-9 {
+		t.Errorf("HorizontalToEquatorial = %.12f, %.12f; want 83.63, 22.01", ra, decl)
+	}
+}
+
+func TestFk4Fk5(t *testing.T) {
+	p := Port{}
+	// reference values from SLALIB (sla_FK425, sla_FK524), which uses radians per year for the proper motions
+	asPerRad := 3600 * 180 / math.Pi
+	fk5 := p.Fk4ToFk5(CatalogPosition{Ra: 1.234 * 180 / math.Pi, Dec: -0.123 * 180 / math.Pi, PmRa: -1e-5 * asPerRad,
+		PmDec: 2e-6 * asPerRad, Parallax: 0.5, RadVel: 20})
+	want := CatalogPosition{Ra: 1.244117554618727 * 180 / math.Pi, Dec: -0.1213164254458709 * 180 / math.Pi,
+		PmRa: -9.964265838268711e-6 * asPerRad, PmDec: 2.038065265773541e-6 * asPerRad, Parallax: 0.4997443812415410,
+		RadVel: 20.010460915421010}
+	if math.Abs(fk5.Ra-want.Ra) > 1e-10 || math.Abs(fk5.Dec-want.Dec) > 1e-10 ||
+		math.Abs(fk5.PmRa-want.PmRa) > 1e-10 || math.Abs(fk5.PmDec-want.PmDec) > 1e-10 ||
+		math.Abs(fk5.Parallax-want.Parallax) > 1e-12 || math.Abs(fk5.RadVel-want.RadVel) > 1e-10 {
+		t.Errorf("Fk4ToFk5 = %+v; want %+v", fk5, want)
+	}
+	fk4 := p.Fk5ToFk4(CatalogPosition{Ra: 4.567 * 180 / math.Pi, Dec: -1.23 * 180 / math.Pi, PmRa: -3e-5 * asPerRad,
+		PmDec: 8e-6 * asPerRad, Parallax: 0.29, RadVel: -35})
+	want = CatalogPosition{Ra: 4.543778603272084 * 180 / math.Pi, Dec: -1.229642790187574 * 180 / math.Pi,
+		PmRa: -2.957873121769244e-5 * asPerRad, PmDec: 8.117725309659079e-6 * asPerRad, Parallax: 0.2898494999992917,
+		RadVel: -35.026862824252680}
+	if math.Abs(fk4.Ra-want.Ra) > 1e-10 || math.Abs(fk4.Dec-want.Dec) > 1e-10 ||
+		math.Abs(fk4.PmRa-want.PmRa) > 1e-10 || math.Abs(fk4.PmDec-want.PmDec) > 1e-10 ||
+		math.Abs(fk4.Parallax-want.Parallax) > 1e-12 || math.Abs(fk4.RadVel-want.RadVel) > 1e-10 {
+		t.Errorf("Fk5ToFk4 = %+v; want %+v", fk4, want)
+	}
+	// back and forth, the published matrices are accurate to about 1 mas
+	pos := CatalogPosition{Ra: 101.287155, Dec: -16.716116, PmRa: -0.0379, PmDec: -1.211, Parallax: 0.379, RadVel: -7.6}
+	back := p.Fk5ToFk4(p.Fk4ToFk5(pos))
+	if math.Abs(back.Ra-pos.Ra)*3600 > 0.001 || math.Abs(back.Dec-pos.Dec)*3600 > 0.001 {
+		t.Errorf("Fk4ToFk5 and back = %f, %f; want %f, %f", back.Ra, back.Dec, pos.Ra, pos.Dec)
+	}
+}
+
+func TestPrecessEpochs(t *testing.T) {
+	p := Port{}
+	// reference values from the C version of the Swiss Ephemeris (swi_precess from tjdFrom to J2000 and to tjdTo)
+	tests := []struct {
+		ra, decl, tjdFrom, tjdTo float64
+		precModel                int
+		wantRa, wantDecl         float64
+	}{
+		{101.287155, -16.716116, B1950, J2000, SEMOD_PREC_NEWCOMB, 101.845493960187, -16.771924827881},
+		{279.234735, 38.783689, J1900, 2488070.0, SEMOD_PREC_VONDRAK_2011, 280.913181309883, 38.978452364006},
+		{10.0, 20.0, 2299160.5, J2000 + 3000, SEMOD_PREC_IAU_2006, 15.647876446448, 22.310504127985},
+	}
+	for _, tt := range tests {
+		x := p.PrecessEpochs([6]float64{tt.ra, tt.decl, 1, 0, 0, 0}, tt.tjdFrom, tt.tjdTo, tt.precModel)
+		if math.Abs(x[0]-tt.wantRa) > 1e-9 || math.Abs(x[1]-tt.wantDecl) > 1e-9 {
+			t.Errorf("PrecessEpochs(%f, %f, model %d) = %.12f, %.12f; want %.12f, %.12f", tt.ra, tt.decl,
+				tt.precModel, x[0], x[1], tt.wantRa, tt.wantDecl)
+		}
+	}
+	// speeds are rotated with the position: compare with the difference of two positions one day apart
+	x0 := [6]float64{50.0, 30.0, 1.0, 0.2, -0.1, 0.0}
+	x1 := [6]float64{50.2, 29.9, 1.0, 0.0, 0.0, 0.0}
+	r0 := p.PrecessEpochs(x0, B1950, J2000, SEMOD_PREC_NEWCOMB)
+	r1 := p.PrecessEpochs(x1, B1950, J2000, SEMOD_PREC_NEWCOMB)
+	if math.Abs(r1[0]-r0[0]-r0[3]) > 0.001 || math.Abs(r1[1]-r0[1]-r0[4]) > 0.001 {
+		t.Errorf("PrecessEpochs speeds = %f, %f; want about %f, %f", r0[3], r0[4], r1[0]-r0[0], r1[1]-r0[1])
+	}
+}
+
+func TestReadFileHeader(t *testing.T) {
+	p := Port{}
+	h, err := p.ReadFileHeader("internal/testdata/sweph/sepl_18.se1")
+	if err != nil || h.File.SwephDenum != 431 || len(h.Planets) != 2 || h.Planets[1].Dseg != 16 {
+		t.Errorf("ReadFileHeader = %+v, %v; want DE431 with 2 planets", h, err)
+	}
+	data, err := os.ReadFile("internal/testdata/sweph/sepl_18.se1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fnam := filepath.Join(t.TempDir(), "sepl_18.se1")
+	if err = os.WriteFile(fnam, data[:len(data)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.ReadFileHeader(fnam)
+	var corrupt *CorruptFileError
+	if !errors.As(err, &corrupt) {
+		t.Errorf("ReadFileHeader for truncated file: error %v; want CorruptFileError", err)
+	}
+}
+
+func TestCalcCorruptFile(t *testing.T) {
+	data, err := os.ReadFile("internal/testdata/sweph/sepl_18.se1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dir, "sepl_18.se1"), data[:len(data)-100], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := Port{}
+	p.SetEphePath(dir)
+	defer p.SetEphePath("")
+	_, iflag, _, err := p.Calc(2451545, SE_MERCURY, SEFLG_SWIEPH|SEFLG_SPEED)
+	var corrupt *CorruptFileError
+	if iflag != -1 || !errors.As(err, &corrupt) || corrupt.Code != "h" {
+		t.Errorf("Calc with truncated file = %d, %v; want -1 and CorruptFileError with code h", iflag, err)
+	}
+	_, _, _, err = p.CalcUt(2451545, SE_MERCURY, SEFLG_SWIEPH|SEFLG_SPEED)
+	if !errors.As(err, &corrupt) {
+		t.Errorf("CalcUt with truncated file: error %v; want CorruptFileError", err)
+	}
+}
+
+func TestWriteEphemerisFile(t *testing.T) {
+	p := Port{}
+	circle := func(tjd float64) [3]float64 {
+		return [3]float64{math.Cos(tjd / 100), math.Sin(tjd / 100), 0}
+	}
+	ef := EphemerisFile{Fversion: 2, Copyright: "test", SwephDenum: 431}
+	for _, ipl := range []int{SEI_MERCURY, SEI_VENUS} {
+		bd := EphemerisBody{Ipl: ipl, Iflg: SEI_FLG_HELIO, Ncoe: 10, Tfstart: 2451536.5, Dseg: 16}
+		bd.Segments = p.FitChebyshev(circle, bd.Tfstart, bd.Dseg, 4, bd.Ncoe)
+		ef.Bodies = append(ef.Bodies, bd)
+	}
+	fnam := filepath.Join(t.TempDir(), "sepl_18.se1")
+	if err := p.WriteEphemerisFile(fnam, &ef); err != nil {
+		t.Fatalf("WriteEphemerisFile: %v", err)
+	}
+	h, err := p.ReadFileHeader(fnam)
+	if err != nil || h.File.Npl != 2 || h.Planets[1].Ibdy != SEI_VENUS || h.Planets[1].Tfend != 2451600.5 ||
+		h.Gcdat.Clight == 0 {
+		t.Errorf("ReadFileHeader = %+v, %v; want 2 planets until JD 2451600.5", h, err)
+	}
+}
+
+func TestListAsteroids(t *testing.T) {
+	p := Port{}
+	p.SetEphePath("internal/testdata/sweph")
+	defer p.SetEphePath("")
+	asteroids, err := p.ListAsteroids()
+	if err != nil || len(asteroids) != 1 || asteroids[0].Number != 433 {
+		t.Errorf("ListAsteroids = %+v, %v; want asteroid 433", asteroids, err)
+	}
+	if af, ok := p.FindAsteroid("EROS"); !ok || af.Name != "Eros" {
+		t.Errorf("FindAsteroid(EROS) = %+v, %v; want Eros", af, ok)
+	}
+}
+
+func TestUranianElements(t *testing.T) {
+	neely, classic := Port{}, Port{}
+	if err := classic.SetUranianElements(SE_FICTEL_CLASSIC); err != nil {
+		t.Fatal(err)
+	}
+	if err := classic.SetUranianElements(3); err == nil || classic.UranianElements() != SE_FICTEL_CLASSIC {
+		t.Errorf("SetUranianElements(3) = %v, elements %d; want an error and SE_FICTEL_CLASSIC", err,
+			classic.UranianElements())
+	}
+	if neely.UranianElements() != SE_FICTEL_NEELY {
+		t.Errorf("UranianElements() = %d; want SE_FICTEL_NEELY", neely.UranianElements())
+	}
+	neely.SetEphePath(t.TempDir())
+	defer neely.SetEphePath("")
+	// the elements of one Port do not change the results of the other one
+	for i := 0; i < 2; i++ {
+		xn, _, provn, errn := neely.Calc(2451545.0, SE_CUPIDO, SEFLG_MOSEPH)
+		xc, _, provc, errc := classic.Calc(2451545.0, SE_CUPIDO, SEFLG_MOSEPH)
+		if errn != nil || errc != nil || provn.FictEl != SE_FICTEL_NEELY || provc.FictEl != SE_FICTEL_CLASSIC ||
+			math.Abs(xc[0]-243.9330260648) > 1e-9 || xn[0] == xc[0] {
+			t.Errorf("Calc of Cupido = %.10f (%+v, %v) and %.10f (%+v, %v); want different elements", xn[0], provn,
+				errn, xc[0], provc, errc)
+		}
+	}
+}